@@ -0,0 +1,344 @@
+package jira
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// TransferProgress is called while attachments are uploaded or downloaded,
+// with the number of bytes transferred so far and the total number of bytes (-1 if unknown).
+type TransferProgress func(transferred, total int64)
+
+// AttachmentFile is a file to upload with IssueService.PostAttachments
+type AttachmentFile struct {
+	Name   string
+	Reader io.Reader
+	// Size is the number of bytes of Reader. It is only used for progress reporting; 0 means unknown.
+	Size int64
+}
+
+// AttachmentUploadOptions specifies the optional parameters for IssueService.PostAttachments
+type AttachmentUploadOptions struct {
+	// Progress is called after every chunk written to Jira
+	Progress TransferProgress
+}
+
+// AttachmentDownloadOptions specifies the optional parameters for IssueService.DownloadAttachmentTo
+type AttachmentDownloadOptions struct {
+	// Offset resumes a download after the first Offset bytes of the attachment.
+	// A range request is sent; if Jira ignores it, the first Offset bytes are skipped while reading.
+	Offset int64
+	// Hash receives all written bytes, e.g. sha256.New() to compute the checksum of the download
+	Hash hash.Hash
+	// Progress is called after every chunk written to the writer. The transferred bytes include Offset.
+	Progress TransferProgress
+}
+
+// AttachmentDownload is the result of downloading an attachment
+type AttachmentDownload struct {
+	// Written is the number of bytes written by this download
+	Written int64
+	// Size is the size of the whole attachment, or -1 if Jira did not send it
+	Size int64
+	// Checksum is the hex encoded sum of AttachmentDownloadOptions.Hash, if a hash was given
+	Checksum string
+}
+
+// AttachmentSettings are the attachment settings of the Jira instance
+type AttachmentSettings struct {
+	Enabled bool `json:"enabled" structs:"enabled"`
+	// UploadLimit is the maximum size of an attachment in bytes
+	UploadLimit int64 `json:"uploadLimit" structs:"uploadLimit"`
+}
+
+// progressReader reports the bytes read from r
+type progressReader struct {
+	r           io.Reader
+	transferred int64
+	total       int64
+	progress    TransferProgress
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.transferred += int64(n)
+		p.progress(p.transferred, p.total)
+	}
+	return n, err
+}
+
+// progressWriter reports the bytes written to w
+type progressWriter struct {
+	w           io.Writer
+	transferred int64
+	total       int64
+	progress    TransferProgress
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.transferred += int64(n)
+		p.progress(p.transferred, p.total)
+	}
+	return n, err
+}
+
+// PostAttachmentsWithContext uploads files as attachments of an issue in one request.
+// The multipart body is streamed to Jira through an io.Pipe, so the files are never held in memory.
+// The progress callback is called from the goroutine writing the body.
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/issue/{issueIdOrKey}/attachments-addAttachment
+func (s *IssueService) PostAttachmentsWithContext(ctx context.Context, issueID string, files []AttachmentFile, options *AttachmentUploadOptions) (*[]Attachment, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/api/2/issue/%s/attachments", issueID)
+
+	var progress TransferProgress
+	if options != nil {
+		progress = options.Progress
+	}
+	total := int64(0)
+	for _, file := range files {
+		if file.Size <= 0 {
+			total = -1
+			break
+		}
+		total += file.Size
+	}
+
+	// Closing the reader stops the writing goroutine if the request ends before the whole body was sent
+	pr, pw := io.Pipe()
+	defer func() { _ = pr.Close() }()
+	writer := multipart.NewWriter(pw)
+
+	req, err := s.client.NewStreamRequestWithContext(ctx, "POST", apiEndpoint, pr)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	go func() {
+		counter := &progressReader{total: total, progress: progress}
+		for _, file := range files {
+			fw, err := writer.CreateFormFile("file", file.Name)
+			if err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+			if file.Reader == nil {
+				continue
+			}
+			var r io.Reader = file.Reader
+			if progress != nil {
+				counter.r = file.Reader
+				r = counter
+			}
+			if _, err := io.Copy(fw, r); err != nil {
+				_ = pw.CloseWithError(err)
+				return
+			}
+		}
+		_ = pw.CloseWithError(writer.Close())
+	}()
+
+	// PostAttachment response returns a JSON array (as multiple attachments can be posted)
+	attachment := new([]Attachment)
+	resp, err := s.client.Do(req, attachment)
+	if err != nil {
+		jerr := NewJiraError(resp, err)
+		return nil, resp, jerr
+	}
+
+	return attachment, resp, nil
+}
+
+// PostAttachments wraps PostAttachmentsWithContext using the background context.
+func (s *IssueService) PostAttachments(issueID string, files []AttachmentFile, options *AttachmentUploadOptions) (*[]Attachment, *Response, error) {
+	return s.PostAttachmentsWithContext(context.Background(), issueID, files, options)
+}
+
+// DownloadAttachmentToWithContext streams an attachment into w, optionally resuming after an offset,
+// computing a checksum and reporting the progress.
+// If the size of the attachment is known, a download which ends early is reported as an error.
+func (s *IssueService) DownloadAttachmentToWithContext(ctx context.Context, attachmentID string, w io.Writer, options *AttachmentDownloadOptions) (*AttachmentDownload, *Response, error) {
+	if options == nil {
+		options = &AttachmentDownloadOptions{}
+	}
+
+	apiEndpoint := fmt.Sprintf("secure/attachment/%s/", attachmentID)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if options.Offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", options.Offset))
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		// The offset is at the end: nothing is left to download.
+		// An offset after the end means the existing content does not belong to this attachment.
+		if resp != nil && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && options.Offset > 0 {
+			Cleanup(resp)
+			size := contentRangeSize(resp.Header.Get("Content-Range"))
+			if size != options.Offset {
+				return nil, resp, fmt.Errorf("offset %d does not match the size %d of attachment %s", options.Offset, size, attachmentID)
+			}
+			return &AttachmentDownload{Size: size, Checksum: hashSum(options.Hash)}, resp, nil
+		}
+		return nil, resp, NewJiraError(resp, err)
+	}
+	defer Cleanup(resp)
+
+	download := &AttachmentDownload{Size: resp.ContentLength}
+	if resp.StatusCode == http.StatusPartialContent {
+		download.Size = contentRangeSize(resp.Header.Get("Content-Range"))
+	} else if options.Offset > 0 {
+		if _, err := io.CopyN(ioutil.Discard, resp.Body, options.Offset); err != nil {
+			return nil, resp, err
+		}
+	}
+
+	if options.Hash != nil {
+		w = io.MultiWriter(w, options.Hash)
+	}
+	if options.Progress != nil {
+		w = &progressWriter{w: w, transferred: options.Offset, total: download.Size, progress: options.Progress}
+	}
+
+	download.Written, err = io.Copy(w, resp.Body)
+	if err != nil {
+		return download, resp, err
+	}
+	download.Checksum = hashSum(options.Hash)
+
+	if download.Size >= 0 && options.Offset+download.Written != download.Size {
+		return download, resp, fmt.Errorf("incomplete download of attachment %s: %d of %d bytes", attachmentID, options.Offset+download.Written, download.Size)
+	}
+	return download, resp, nil
+}
+
+// DownloadAttachmentTo wraps DownloadAttachmentToWithContext using the background context.
+func (s *IssueService) DownloadAttachmentTo(attachmentID string, w io.Writer, options *AttachmentDownloadOptions) (*AttachmentDownload, *Response, error) {
+	return s.DownloadAttachmentToWithContext(context.Background(), attachmentID, w, options)
+}
+
+// DownloadAttachmentToFileWithContext streams an attachment into the file filename.
+// With resume, an existing file is continued after its current content; the offset of options is ignored,
+// and a given hash is fed with the existing content first, so the checksum covers the whole file.
+func (s *IssueService) DownloadAttachmentToFileWithContext(ctx context.Context, attachmentID, filename string, resume bool, options *AttachmentDownloadOptions) (*AttachmentDownload, error) {
+	opts := AttachmentDownloadOptions{}
+	if options != nil {
+		opts = *options
+	}
+	opts.Offset = 0
+
+	flags := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	if resume {
+		flags = os.O_RDWR | os.O_CREATE
+	}
+	file, err := os.OpenFile(filename, flags, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if resume {
+		if opts.Hash != nil {
+			opts.Offset, err = io.Copy(opts.Hash, file)
+		} else {
+			opts.Offset, err = file.Seek(0, io.SeekEnd)
+		}
+		if err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+
+	download, _, err := s.DownloadAttachmentToWithContext(ctx, attachmentID, file, &opts)
+	if err != nil {
+		_ = file.Close()
+		return download, err
+	}
+	return download, file.Close()
+}
+
+// DownloadAttachmentToFile wraps DownloadAttachmentToFileWithContext using the background context.
+func (s *IssueService) DownloadAttachmentToFile(attachmentID, filename string, resume bool, options *AttachmentDownloadOptions) (*AttachmentDownload, error) {
+	return s.DownloadAttachmentToFileWithContext(context.Background(), attachmentID, filename, resume, options)
+}
+
+// GetAttachmentWithContext returns the meta data of an attachment
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/attachment-getAttachment
+func (s *IssueService) GetAttachmentWithContext(ctx context.Context, attachmentID string) (*Attachment, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/api/2/attachment/%s", attachmentID)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachment := new(Attachment)
+	resp, err := s.client.Do(req, attachment)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+	return attachment, resp, nil
+}
+
+// GetAttachment wraps GetAttachmentWithContext using the background context.
+func (s *IssueService) GetAttachment(attachmentID string) (*Attachment, *Response, error) {
+	return s.GetAttachmentWithContext(context.Background(), attachmentID)
+}
+
+// GetAttachmentSettingsWithContext returns whether attachments are enabled and the maximum upload size
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/attachment-getAttachmentMeta
+func (s *IssueService) GetAttachmentSettingsWithContext(ctx context.Context) (*AttachmentSettings, *Response, error) {
+	apiEndpoint := "rest/api/2/attachment/meta"
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	settings := new(AttachmentSettings)
+	resp, err := s.client.Do(req, settings)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+	return settings, resp, nil
+}
+
+// GetAttachmentSettings wraps GetAttachmentSettingsWithContext using the background context.
+func (s *IssueService) GetAttachmentSettings() (*AttachmentSettings, *Response, error) {
+	return s.GetAttachmentSettingsWithContext(context.Background())
+}
+
+// contentRangeSize returns the complete length of a Content-Range header ("bytes 100-199/200"), or -1 if it is unknown
+func contentRangeSize(contentRange string) int64 {
+	i := strings.LastIndex(contentRange, "/")
+	if i < 0 {
+		return -1
+	}
+	size, err := strconv.ParseInt(contentRange[i+1:], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return size
+}
+
+// hashSum returns the hex encoded sum of h, or "" if h is nil
+func hashSum(h hash.Hash) string {
+	if h == nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
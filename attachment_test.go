@@ -0,0 +1,194 @@
+package jira
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssueService_PostAttachments(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/issue/10000/attachments", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		if r.Header.Get("X-Atlassian-Token") != "nocheck" {
+			t.Error("Expected the X-Atlassian-Token header")
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+		files := r.MultipartForm.File["file"]
+		if len(files) != 2 || files[0].Filename != "a.log" || files[1].Filename != "b.log" {
+			t.Errorf("Expected a.log and b.log, got %v", files)
+		}
+		fmt.Fprint(w, `[{"id":"1","filename":"a.log"},{"id":"2","filename":"b.log"}]`)
+	})
+
+	var progress []int64
+	attachments, _, err := testClient.Issue.PostAttachments("10000", []AttachmentFile{
+		{Name: "a.log", Reader: strings.NewReader("first"), Size: 5},
+		{Name: "b.log", Reader: strings.NewReader("second"), Size: 6},
+	}, &AttachmentUploadOptions{Progress: func(transferred, total int64) {
+		if total != 11 {
+			t.Errorf("Expected a total of 11 bytes, got %d", total)
+		}
+		progress = append(progress, transferred)
+	}})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(*attachments) != 2 {
+		t.Errorf("Expected 2 attachments, got %d", len(*attachments))
+	}
+	if len(progress) == 0 || progress[len(progress)-1] != 11 {
+		t.Errorf("Expected the progress to end at 11 bytes, got %v", progress)
+	}
+}
+
+func TestIssueService_DownloadAttachmentTo(t *testing.T) {
+	setup()
+	defer teardown()
+	content := "Here is an attachment"
+	testMux.HandleFunc("/secure/attachment/10000/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		http.ServeContent(w, r, "attachment.txt", time.Time{}, strings.NewReader(content))
+	})
+
+	var buf bytes.Buffer
+	var transferred int64
+	download, _, err := testClient.Issue.DownloadAttachmentTo("10000", &buf, &AttachmentDownloadOptions{
+		Hash:     sha256.New(),
+		Progress: func(done, total int64) { transferred = done },
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	sum := sha256.Sum256([]byte(content))
+	if buf.String() != content || download.Written != int64(len(content)) || download.Size != int64(len(content)) {
+		t.Errorf("Unexpected download %+v: %q", download, buf.String())
+	}
+	if download.Checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected checksum %s", download.Checksum)
+	}
+	if transferred != int64(len(content)) {
+		t.Errorf("Expected progress up to %d bytes, got %d", len(content), transferred)
+	}
+}
+
+func TestIssueService_DownloadAttachmentTo_Offset(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/secure/attachment/10000/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "bytes=8-" {
+			t.Errorf("Unexpected range %q", r.Header.Get("Range"))
+		}
+		// Jira without range support sends the whole attachment
+		fmt.Fprint(w, "Here is an attachment")
+	})
+
+	var buf bytes.Buffer
+	_, _, err := testClient.Issue.DownloadAttachmentTo("10000", &buf, &AttachmentDownloadOptions{Offset: 8})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if buf.String() != "an attachment" {
+		t.Errorf("Expected the rest of the attachment, got %q", buf.String())
+	}
+}
+
+func TestIssueService_DownloadAttachmentToFile_Resume(t *testing.T) {
+	setup()
+	defer teardown()
+	content := "Here is an attachment"
+	testMux.HandleFunc("/secure/attachment/10000/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "bytes=8-" {
+			t.Errorf("Unexpected range %q", r.Header.Get("Range"))
+		}
+		http.ServeContent(w, r, "attachment.txt", time.Time{}, strings.NewReader(content))
+	})
+
+	filename := filepath.Join(t.TempDir(), "attachment.txt")
+	if err := ioutil.WriteFile(filename, []byte(content[:8]), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	download, err := testClient.Issue.DownloadAttachmentToFile("10000", filename, true, &AttachmentDownloadOptions{Hash: sha256.New()})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(content))
+	if string(data) != content || download.Written != int64(len(content)-8) || download.Checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("Unexpected download %+v: %q", download, string(data))
+	}
+}
+
+func TestIssueService_DownloadAttachmentTo_OffsetAtEnd(t *testing.T) {
+	setup()
+	defer teardown()
+	content := "Here is an attachment"
+	testMux.HandleFunc("/secure/attachment/10000/", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "attachment.txt", time.Time{}, strings.NewReader(content))
+	})
+
+	var buf bytes.Buffer
+	download, _, err := testClient.Issue.DownloadAttachmentTo("10000", &buf, &AttachmentDownloadOptions{Offset: int64(len(content))})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if download.Size != int64(len(content)) || buf.Len() != 0 {
+		t.Errorf("Expected a complete download without content, got %+v and %q", download, buf.String())
+	}
+
+	_, _, err = testClient.Issue.DownloadAttachmentTo("10000", &buf, &AttachmentDownloadOptions{Offset: int64(len(content)) + 5})
+	if err == nil {
+		t.Error("Expected an error for an offset after the end of the attachment")
+	}
+}
+
+func TestIssueService_GetAttachment(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/attachment/10000", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/api/2/attachment/10000")
+		fmt.Fprint(w, `{"id":"10000","filename":"picture.jpg","size":23123,"mimeType":"image/jpeg"}`)
+	})
+
+	attachment, _, err := testClient.Issue.GetAttachment("10000")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if attachment.Filename != "picture.jpg" || attachment.Size != 23123 {
+		t.Errorf("Unexpected attachment %+v", attachment)
+	}
+}
+
+func TestIssueService_GetAttachmentSettings(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/attachment/meta", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/api/2/attachment/meta")
+		fmt.Fprint(w, `{"enabled":true,"uploadLimit":1000000}`)
+	})
+
+	settings, _, err := testClient.Issue.GetAttachmentSettings()
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if !settings.Enabled || settings.UploadLimit != 1000000 {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+}
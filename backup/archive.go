@@ -0,0 +1,235 @@
+package backup
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Format is the container format of a backup archive
+type Format string
+
+// The supported archive formats
+const (
+	Zip   Format = "zip"
+	TarGz Format = "tar.gz"
+)
+
+// archiveWriter adds files to an archive. The content of a file is produced by a function writing into the archive,
+// so attachments are streamed from Jira without being held in memory.
+type archiveWriter interface {
+	create(name string, modified time.Time, write func(io.Writer) error) error
+	close() error
+}
+
+func newArchiveWriter(w io.Writer, format Format) (archiveWriter, error) {
+	switch format {
+	case Zip, "":
+		return &zipWriter{zip: zip.NewWriter(w)}, nil
+	case TarGz:
+		compressed := gzip.NewWriter(w)
+		return &tarWriter{gzip: compressed, tar: tar.NewWriter(compressed)}, nil
+	}
+	return nil, fmt.Errorf("unknown archive format %q", format)
+}
+
+type zipWriter struct {
+	zip *zip.Writer
+}
+
+func (z *zipWriter) create(name string, modified time.Time, write func(io.Writer) error) error {
+	f, err := z.zip.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return err
+	}
+	return write(f)
+}
+
+func (z *zipWriter) close() error {
+	return z.zip.Close()
+}
+
+// tarWriter writes a gzip compressed tar archive. Tar headers need the size of a file up front,
+// so every file is written to a temporary file first.
+type tarWriter struct {
+	gzip *gzip.Writer
+	tar  *tar.Writer
+}
+
+func (t *tarWriter) create(name string, modified time.Time, write func(io.Writer) error) error {
+	tmp, err := ioutil.TempFile("", "jira-backup-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	buffered := bufio.NewWriter(tmp)
+	if err := write(buffered); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	err = t.tar.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Size: size, Mode: 0o644, ModTime: modified})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(t.tar, tmp)
+	return err
+}
+
+func (t *tarWriter) close() error {
+	if err := t.tar.Close(); err != nil {
+		return err
+	}
+	return t.gzip.Close()
+}
+
+// archiveReader gives access to the files of an archive by name
+type archiveReader interface {
+	open(name string) (io.ReadCloser, error)
+	names() []string
+	close() error
+}
+
+// openArchiveReader opens a zip or tar.gz archive, detecting the format from its first bytes.
+// A tar.gz archive is extracted into a temporary directory, as tar does not allow random access.
+func openArchiveReader(file string) (archiveReader, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	magic := make([]byte, 4)
+	_, err = io.ReadFull(f, magic)
+	_ = f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a backup archive: %w", file, err)
+	}
+
+	switch {
+	case bytes.Equal(magic, []byte("PK\x03\x04")):
+		archive, err := zip.OpenReader(file)
+		if err != nil {
+			return nil, err
+		}
+		return &zipReader{archive: archive}, nil
+	case magic[0] == 0x1f && magic[1] == 0x8b:
+		return extractTarGz(file)
+	}
+	return nil, fmt.Errorf("%s is not a zip or tar.gz archive", file)
+}
+
+type zipReader struct {
+	archive *zip.ReadCloser
+}
+
+func (z *zipReader) open(name string) (io.ReadCloser, error) {
+	return z.archive.Open(name)
+}
+
+func (z *zipReader) names() []string {
+	names := make([]string, 0, len(z.archive.File))
+	for _, f := range z.archive.File {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func (z *zipReader) close() error {
+	return z.archive.Close()
+}
+
+// dirReader reads the files of an extracted archive
+type dirReader struct {
+	dir   string
+	files []string
+}
+
+func extractTarGz(file string) (*dirReader, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	compressed, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := ioutil.TempDir("", "jira-restore-*")
+	if err != nil {
+		return nil, err
+	}
+	d := &dirReader{dir: dir}
+
+	archive := tar.NewReader(compressed)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return d, nil
+		}
+		if err != nil {
+			_ = d.close()
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			_ = d.close()
+			return nil, fmt.Errorf("invalid file name %q in archive", header.Name)
+		}
+		if err := d.extract(name, archive); err != nil {
+			_ = d.close()
+			return nil, err
+		}
+	}
+}
+
+func (d *dirReader) extract(name string, r io.Reader) error {
+	target := filepath.Join(d.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	d.files = append(d.files, name)
+	return f.Close()
+}
+
+func (d *dirReader) open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(d.dir, filepath.FromSlash(name)))
+}
+
+func (d *dirReader) names() []string {
+	return d.files
+}
+
+func (d *dirReader) close() error {
+	return os.RemoveAll(d.dir)
+}
@@ -0,0 +1,268 @@
+// Package backup saves the issues and the configuration of a Jira project to a portable archive
+// and restores them into another project, possibly on another Jira instance.
+//
+// An archive is a zip or tar.gz file with this layout:
+//
+//	manifest.json                  the Manifest, written last
+//	project.json                   the project with its versions, components and issue types
+//	fields.json                    the fields of the source instance
+//	issues/<KEY>.json              an IssueRecord: the issue with all fields, its comments and worklogs
+//	attachments/<ID>               the content of an attachment; its name is in the issue
+//
+// Unlike the XML backup of Jira, this works for a single project and on Jira Cloud.
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+	"github.com/perolo/jira-client/jql"
+)
+
+// ManifestFormat identifies backup archives in the manifest
+const ManifestFormat = "jira-client-backup"
+
+// ManifestVersion is the version of the archive layout written by Backup.
+// Restore rejects archives with a newer version.
+const ManifestVersion = 1
+
+// Manifest describes the content of a backup archive
+type Manifest struct {
+	Format      string    `json:"format"`
+	Version     int       `json:"version"`
+	Created     time.Time `json:"created"`
+	Source      string    `json:"source"`
+	Project     string    `json:"project"`
+	Issues      int       `json:"issues"`
+	Comments    int       `json:"comments"`
+	Worklogs    int       `json:"worklogs"`
+	Attachments int       `json:"attachments"`
+}
+
+// IssueRecord is an issue of a backup with its comments and worklogs
+type IssueRecord struct {
+	Issue    *jira.Issue          `json:"issue"`
+	Comments []jira.Comment       `json:"comments,omitempty"`
+	Worklogs []jira.WorklogRecord `json:"worklogs,omitempty"`
+}
+
+// BackupOptions specifies the optional parameters of Backup
+type BackupOptions struct {
+	// Format is the archive format. Default: Zip.
+	Format Format
+	// PageSize is the number of issues fetched with one search request. Default: 100.
+	PageSize int
+	// SkipAttachments leaves out the content of the attachments; they are still listed in the issues
+	SkipAttachments bool
+	// SkipWorklogs leaves out the worklogs
+	SkipWorklogs bool
+}
+
+// BackupWithContext writes the issues of a project, with their comments, worklogs and attachments,
+// and the configuration of the project to w as an archive.
+// Any error aborts the backup, as an archive with missing parts would restore silently incomplete.
+func BackupWithContext(ctx context.Context, client *jira.Client, projectKey string, w io.Writer, options *BackupOptions) (*Manifest, error) {
+	opts := BackupOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 100
+	}
+
+	archive, err := newArchiveWriter(w, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	baseURL := client.GetBaseURL()
+	manifest := &Manifest{
+		Format:  ManifestFormat,
+		Version: ManifestVersion,
+		Created: time.Now().UTC().Truncate(time.Second),
+		Source:  baseURL.String(),
+	}
+
+	project, _, err := client.Project.GetWithContext(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Project = project.Key
+	if err := writeJSON(archive, "project.json", manifest.Created, project); err != nil {
+		return nil, err
+	}
+
+	fields, _, err := client.Field.GetListWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeJSON(archive, "fields.json", manifest.Created, fields); err != nil {
+		return nil, err
+	}
+
+	query := jql.Where(jql.Field("project").Eq(jql.String(project.Key))).OrderBy(jql.Field("key"), jql.Asc).String()
+	search := &jira.SearchOptions{MaxResults: opts.PageSize, Fields: []string{"*all"}}
+	err = client.Issue.SearchPagesWithContext(ctx, query, search, func(issue jira.Issue) error {
+		record := &IssueRecord{Issue: &issue}
+		comments, err := client.Issue.GetAllCommentsWithContext(ctx, issue.Key)
+		if err != nil {
+			return err
+		}
+		record.Comments = comments
+		// The comments are stored in the record, not twice in the fields
+		issue.Fields.Comments = nil
+		if !opts.SkipWorklogs {
+			worklogs, err := client.Issue.GetAllWorklogsWithContext(ctx, issue.Key)
+			if err != nil {
+				return err
+			}
+			record.Worklogs = worklogs
+			issue.Fields.Worklog = nil
+		}
+		if err := writeJSON(archive, "issues/"+issue.Key+".json", manifest.Created, record); err != nil {
+			return err
+		}
+		manifest.Issues++
+		manifest.Comments += len(record.Comments)
+		manifest.Worklogs += len(record.Worklogs)
+
+		if opts.SkipAttachments {
+			return nil
+		}
+		for _, attachment := range issue.Fields.Attachments {
+			modified := manifest.Created
+			if attachment.Created != nil {
+				modified = time.Time(*attachment.Created)
+			}
+			err := archive.create("attachments/"+attachment.ID, modified, func(w io.Writer) error {
+				_, _, err := client.Issue.DownloadAttachmentToWithContext(ctx, attachment.ID, w, nil)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("attachment %s of issue %s: %w", attachment.ID, issue.Key, err)
+			}
+			manifest.Attachments++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeJSON(archive, "manifest.json", manifest.Created, manifest); err != nil {
+		return nil, err
+	}
+	if err := archive.close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Backup wraps BackupWithContext using the background context.
+func Backup(client *jira.Client, projectKey string, w io.Writer, options *BackupOptions) (*Manifest, error) {
+	return BackupWithContext(context.Background(), client, projectKey, w, options)
+}
+
+func writeJSON(archive archiveWriter, name string, modified time.Time, v interface{}) error {
+	return archive.create(name, modified, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	})
+}
+
+// Archive is an opened backup archive
+type Archive struct {
+	Manifest Manifest
+	Project  *jira.Project
+	Fields   []jira.Field
+	// Issues are sorted by their number, so parents usually come before their sub-tasks
+	Issues []*IssueRecord
+
+	reader archiveReader
+}
+
+// OpenArchive opens a backup archive and loads its manifest, project, fields and issues.
+// The attachments are read on demand; the archive must be closed after use.
+func OpenArchive(file string) (*Archive, error) {
+	reader, err := openArchiveReader(file)
+	if err != nil {
+		return nil, err
+	}
+	a := &Archive{reader: reader}
+	if err := a.load(); err != nil {
+		_ = reader.close()
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *Archive) load() error {
+	if err := a.readJSON("manifest.json", &a.Manifest); err != nil {
+		return err
+	}
+	if a.Manifest.Format != ManifestFormat {
+		return fmt.Errorf("not a backup archive: format %q", a.Manifest.Format)
+	}
+	if a.Manifest.Version > ManifestVersion {
+		return fmt.Errorf("archive version %d is newer than the supported version %d", a.Manifest.Version, ManifestVersion)
+	}
+	if err := a.readJSON("project.json", &a.Project); err != nil {
+		return err
+	}
+	if err := a.readJSON("fields.json", &a.Fields); err != nil {
+		return err
+	}
+
+	for _, name := range a.reader.names() {
+		if !strings.HasPrefix(name, "issues/") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		record := new(IssueRecord)
+		if err := a.readJSON(name, record); err != nil {
+			return err
+		}
+		if record.Issue == nil || record.Issue.Fields == nil {
+			return fmt.Errorf("invalid issue %s in archive", name)
+		}
+		a.Issues = append(a.Issues, record)
+	}
+	sort.Slice(a.Issues, func(i, j int) bool {
+		return issueNumber(a.Issues[i].Issue.Key) < issueNumber(a.Issues[j].Issue.Key)
+	})
+	return nil
+}
+
+func (a *Archive) readJSON(name string, v interface{}) error {
+	f, err := a.reader.open(name)
+	if err != nil {
+		return fmt.Errorf("%s missing in archive: %w", name, err)
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(v); err != nil {
+		return fmt.Errorf("invalid %s in archive: %w", name, err)
+	}
+	return nil
+}
+
+// OpenAttachment returns the content of an attachment, or an error if the archive does not contain it
+func (a *Archive) OpenAttachment(id string) (io.ReadCloser, error) {
+	return a.reader.open("attachments/" + id)
+}
+
+// Close releases the archive
+func (a *Archive) Close() error {
+	return a.reader.close()
+}
+
+// issueNumber returns the number of an issue key, e.g. 12 for ABC-12
+func issueNumber(key string) int {
+	number, _ := strconv.Atoi(key[strings.LastIndex(key, "-")+1:])
+	return number
+}
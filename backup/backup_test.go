@@ -0,0 +1,340 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	jira "github.com/perolo/jira-client"
+)
+
+const testProject = `{"id": "10000", "key": "TST", "name": "Test",
+	"versions": [{"id": "1", "name": "1.0", "released": true}],
+	"components": [{"id": "2", "name": "Backend"}]}`
+
+const testFields = `[
+	{"id": "summary", "name": "Summary", "schema": {"type": "string", "system": "summary"}},
+	{"id": "labels", "name": "Labels", "schema": {"type": "array", "items": "string", "system": "labels"}},
+	{"id": "customfield_10010", "name": "Severity", "custom": true, "schema": {"type": "option", "custom": "com.atlassian.jira.plugin.system.customfieldtypes:select"}},
+	{"id": "customfield_10020", "name": "Sprint", "custom": true, "schema": {"type": "array", "custom": "com.pyxis.greenhopper.jira:gh-sprint"}}
+]`
+
+// TST-2 is a sub-task of TST-3, so it can only be restored after TST-3
+const testIssues = `{"startAt": 0, "maxResults": 100, "total": 3, "issues": [
+	{"id": "10001", "key": "TST-1", "fields": {
+		"summary": "First",
+		"issuetype": {"name": "Task"},
+		"status": {"name": "In Progress"},
+		"assignee": {"accountId": "old-1", "displayName": "Jane Doe"},
+		"labels": ["x"],
+		"fixVersions": [{"id": "1", "name": "1.0"}],
+		"customfield_10010": {"self": "http://old/option/1", "id": "1", "value": "High"},
+		"customfield_10020": [{"id": 1, "name": "Sprint 1"}],
+		"issuelinks": [{"type": {"name": "Blocks"}, "outwardIssue": {"key": "TST-2"}}],
+		"attachment": [{"id": "100", "filename": "log.txt"}],
+		"comment": {"comments": [{"id": "1", "body": "truncated"}]}
+	}},
+	{"id": "10002", "key": "TST-2", "fields": {
+		"summary": "Second",
+		"issuetype": {"name": "Sub-task"},
+		"status": {"name": "To Do"},
+		"parent": {"key": "TST-3"},
+		"issuelinks": [{"type": {"name": "Blocks"}, "inwardIssue": {"key": "TST-1"}}]
+	}},
+	{"id": "10003", "key": "TST-3", "fields": {"summary": "Third", "issuetype": {"name": "Task"}, "status": {"name": "To Do"}}}
+]}`
+
+func sourceServer(t *testing.T) (*jira.Client, func()) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/rest/api/2/project/TST", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testProject)
+	})
+	mux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testFields)
+	})
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		if got, expected := r.URL.Query().Get("jql"), `project = "TST" ORDER BY key ASC`; got != expected {
+			t.Errorf("Expected query %s, got %s", expected, got)
+		}
+		_, _ = fmt.Fprint(w, testIssues)
+	})
+	mux.HandleFunc("/rest/api/2/issue/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/issue/TST-1/comment":
+			_, _ = fmt.Fprint(w, `{"startAt": 0, "total": 2, "comments": [
+				{"id": "1", "body": "Hello", "author": {"displayName": "Jane Doe"}, "created": "2021-03-01T10:00:00.000+0000"},
+				{"id": "2", "body": "World"}]}`)
+		case "/rest/api/2/issue/TST-1/worklog":
+			_, _ = fmt.Fprint(w, `{"startAt": 0, "total": 1, "worklogs": [
+				{"id": "7", "comment": "Work", "timeSpentSeconds": 3600, "started": "2021-03-01T10:00:00.000+0000"}]}`)
+		default:
+			if strings.HasSuffix(r.URL.Path, "/comment") {
+				_, _ = fmt.Fprint(w, `{"startAt": 0, "total": 0, "comments": []}`)
+			} else {
+				_, _ = fmt.Fprint(w, `{"startAt": 0, "total": 0, "worklogs": []}`)
+			}
+		}
+	})
+	mux.HandleFunc("/secure/attachment/100/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "log content")
+	})
+
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server.Close
+}
+
+func backupArchive(t *testing.T, format Format) *Archive {
+	client, closeServer := sourceServer(t)
+	defer closeServer()
+
+	file := filepath.Join(t.TempDir(), "backup")
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := Backup(client, "TST", f, &BackupOptions{Format: format})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Issues != 3 || manifest.Comments != 2 || manifest.Worklogs != 1 || manifest.Attachments != 1 {
+		t.Errorf("Unexpected manifest %+v", manifest)
+	}
+
+	archive, err := OpenArchive(file)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	t.Cleanup(func() { _ = archive.Close() })
+	return archive
+}
+
+func TestBackup(t *testing.T) {
+	for _, format := range []Format{Zip, TarGz} {
+		t.Run(string(format), func(t *testing.T) {
+			archive := backupArchive(t, format)
+
+			if archive.Manifest.Format != ManifestFormat || archive.Manifest.Version != ManifestVersion || archive.Manifest.Project != "TST" {
+				t.Errorf("Unexpected manifest %+v", archive.Manifest)
+			}
+			if archive.Project.Key != "TST" || len(archive.Project.Versions) != 1 || len(archive.Fields) != 4 {
+				t.Errorf("Unexpected project %+v and fields %+v", archive.Project, archive.Fields)
+			}
+			var keys []string
+			for _, record := range archive.Issues {
+				keys = append(keys, record.Issue.Key)
+			}
+			if strings.Join(keys, ",") != "TST-1,TST-2,TST-3" {
+				t.Errorf("Expected issues TST-1,TST-2,TST-3, got %v", keys)
+			}
+			first := archive.Issues[0]
+			if len(first.Comments) != 2 || first.Comments[1].Body != "World" || first.Issue.Fields.Comments != nil {
+				t.Errorf("Expected the comments in the record only, got %+v", first.Comments)
+			}
+			if len(first.Worklogs) != 1 || first.Worklogs[0].TimeSpentSeconds != 3600 {
+				t.Errorf("Unexpected worklogs %+v", first.Worklogs)
+			}
+
+			content, err := archive.OpenAttachment("100")
+			if err != nil {
+				t.Fatalf("Error given: %s", err)
+			}
+			defer content.Close()
+			data, _ := ioutil.ReadAll(content)
+			if string(data) != "log content" {
+				t.Errorf("Expected attachment content, got %q", data)
+			}
+		})
+	}
+}
+
+func TestOpenArchive_invalid(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "invalid")
+	if err := ioutil.WriteFile(file, []byte("not an archive"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenArchive(file); err == nil {
+		t.Error("Expected an error for an invalid archive")
+	}
+}
+
+// testTarget is a Jira instance recording the requests of a restore
+type testTarget struct {
+	mutex       sync.Mutex
+	created     []map[string]interface{}
+	versions    []string
+	components  []string
+	transitions []string
+	comments    []string
+	worklogs    []string
+	attachments []string
+	links       []string
+}
+
+func (target *testTarget) server(t *testing.T) (*jira.Client, func()) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	decode := func(r *http.Request) map[string]interface{} {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Invalid request body: %s", err)
+		}
+		return body
+	}
+
+	mux.HandleFunc("/rest/api/2/project/NEW", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"id": "20000", "key": "NEW", "components": [{"id": "5", "name": "backend"}]}`)
+	})
+	mux.HandleFunc("/rest/api/2/version", func(w http.ResponseWriter, r *http.Request) {
+		body := decode(r)
+		target.versions = append(target.versions, fmt.Sprintf("%v/%v", body["name"], body["projectId"]))
+		_, _ = fmt.Fprint(w, `{"id": "30"}`)
+	})
+	mux.HandleFunc("/rest/api/2/component", func(w http.ResponseWriter, r *http.Request) {
+		body := decode(r)
+		target.components = append(target.components, fmt.Sprint(body["name"]))
+		_, _ = fmt.Fprint(w, `{"id": "31"}`)
+	})
+	mux.HandleFunc("/rest/api/2/issue/createmeta", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"projects": [{"key": "NEW", "issuetypes": [
+			{"id": "1", "name": "Task", "fields": {"summary": {}, "assignee": {}, "fixVersions": {}, "customfield_10010": {}, "customfield_10020": {}}},
+			{"id": "2", "name": "Sub-task", "subtask": true, "fields": {"summary": {}, "parent": {}}}]}]}`)
+	})
+	mux.HandleFunc("/rest/api/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		body := decode(r)
+		target.mutex.Lock()
+		target.created = append(target.created, body["fields"].(map[string]interface{}))
+		key := fmt.Sprintf("NEW-%d", len(target.created))
+		target.mutex.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"key": %q}`, key)
+	})
+	mux.HandleFunc("/rest/api/2/issue/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		parts := strings.Split(path, "/")
+		switch {
+		case len(parts) == 1:
+			_, _ = fmt.Fprintf(w, `{"key": %q, "fields": {"status": {"name": "To Do"}}}`, parts[0])
+		case parts[1] == "transitions" && r.Method == "GET":
+			_, _ = fmt.Fprint(w, `{"transitions": [{"id": "11", "name": "Start", "to": {"name": "In Progress"}}]}`)
+		case parts[1] == "transitions":
+			body := decode(r)
+			target.transitions = append(target.transitions, fmt.Sprintf("%s:%v", parts[0], body["transition"]))
+			w.WriteHeader(http.StatusNoContent)
+		case parts[1] == "comment":
+			body := decode(r)
+			target.comments = append(target.comments, fmt.Sprintf("%s:%v", parts[0], body["body"]))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprint(w, `{"id": "1"}`)
+		case parts[1] == "worklog":
+			body := decode(r)
+			target.worklogs = append(target.worklogs, fmt.Sprintf("%s:%v:%v", parts[0], body["timeSpentSeconds"], body["comment"]))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprint(w, `{"id": "1"}`)
+		case parts[1] == "attachments":
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("Invalid attachment upload: %s", err)
+				return
+			}
+			data, _ := ioutil.ReadAll(file)
+			target.attachments = append(target.attachments, fmt.Sprintf("%s:%s:%s", parts[0], header.Filename, data))
+			_, _ = fmt.Fprint(w, `[{"id": "1"}]`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	mux.HandleFunc("/rest/api/2/issueLink", func(w http.ResponseWriter, r *http.Request) {
+		var link jira.IssueLink
+		if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+			t.Errorf("Invalid link: %s", err)
+		}
+		target.links = append(target.links, fmt.Sprintf("%s %s %s", link.InwardIssue.Key, link.Type.Name, link.OutwardIssue.Key))
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server.Close
+}
+
+func TestRestore(t *testing.T) {
+	archive := backupArchive(t, Zip)
+	target := &testTarget{}
+	client, closeServer := target.server(t)
+	defer closeServer()
+
+	report, err := Restore(client, archive, &RestoreOptions{
+		ProjectKey: "NEW",
+		Users:      map[string]string{"old-1": "new-1"},
+		Attribute:  true,
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+
+	expectedIssues := map[string]string{"TST-1": "NEW-1", "TST-3": "NEW-2", "TST-2": "NEW-3"}
+	if fmt.Sprint(report.Issues) != fmt.Sprint(expectedIssues) {
+		t.Errorf("Expected issues %v, got %v", expectedIssues, report.Issues)
+	}
+	if fmt.Sprint(target.versions) != "[1.0/20000]" || len(target.components) != 0 {
+		t.Errorf("Expected only version 1.0 to be created, got %v and %v", target.versions, target.components)
+	}
+
+	first := target.created[0]
+	if fmt.Sprint(first["assignee"]) != "map[accountId:new-1]" {
+		t.Errorf("Expected the mapped assignee, got %v", first["assignee"])
+	}
+	if fmt.Sprint(first["customfield_10010"]) != "map[value:High]" {
+		t.Errorf("Expected the option by value, got %v", first["customfield_10010"])
+	}
+	if fmt.Sprint(first["fixVersions"]) != "[map[name:1.0]]" {
+		t.Errorf("Expected the fix version by name, got %v", first["fixVersions"])
+	}
+	if _, ok := first["customfield_10020"]; ok {
+		t.Error("Expected the sprint not to be restored")
+	}
+	if fmt.Sprint(target.created[2]["parent"]) != "map[key:NEW-2]" {
+		t.Errorf("Expected the sub-task under the restored parent, got %v", target.created[2]["parent"])
+	}
+
+	if fmt.Sprint(target.transitions) != "[NEW-1:map[id:11]]" {
+		t.Errorf("Unexpected transitions %v", target.transitions)
+	}
+	if len(target.comments) != 2 || !strings.HasPrefix(target.comments[0], "NEW-1:_Originally by Jane Doe on 2021-03-01 10:00 UTC_\n\nHello") {
+		t.Errorf("Unexpected comments %q", target.comments)
+	}
+	if fmt.Sprint(target.worklogs) != "[NEW-1:3600:_Originally by unknown_\n\nWork]" {
+		t.Errorf("Unexpected worklogs %q", target.worklogs)
+	}
+	if fmt.Sprint(target.attachments) != "[NEW-1:log.txt:log content]" {
+		t.Errorf("Unexpected attachments %v", target.attachments)
+	}
+	if fmt.Sprint(target.links) != "[NEW-1 Blocks NEW-3]" {
+		t.Errorf("Expected the link once, got %v", target.links)
+	}
+
+	// TST-1 has labels, which are not on the create screen; TST-2 and TST-3 are in status To Do already
+	var problems []string
+	for _, problem := range report.Problems {
+		problems = append(problems, problem.Key+" "+problem.Item)
+	}
+	if strings.Join(problems, ",") != "TST-1 fields" {
+		t.Errorf("Unexpected problems %v", report.Problems)
+	}
+}
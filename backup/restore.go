@@ -0,0 +1,349 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	jira "github.com/perolo/jira-client"
+	"github.com/perolo/jira-client/internal/issuecopy"
+)
+
+// RestoreOptions specifies the optional parameters of Restore
+type RestoreOptions struct {
+	// ProjectKey is the existing project the issues are restored into. Default: the project of the backup.
+	ProjectKey string
+	// IssueTypes maps issue type names of the backup to issue type names of the target project.
+	// Unmapped issue types keep their name.
+	IssueTypes map[string]string
+	// Users maps users of the backup, by account id, name, key or email address, to the account ids
+	// (or names, see UserNames) of the target instance. Unmapped users keep their account id or name.
+	Users map[string]string
+	// UserNames identifies users of the target instance by name (Jira Server and Data Center) instead of account id
+	UserNames bool
+	// Fields maps custom field ids of the backup to custom field ids of the target instance.
+	// Unmapped custom fields keep their id; fields mapped to "" are not restored.
+	Fields map[string]string
+	// Attribute prefixes restored comments and worklogs with their original author and time,
+	// as they are created in the name of the restoring user
+	Attribute bool
+
+	SkipStatuses    bool
+	SkipComments    bool
+	SkipWorklogs    bool
+	SkipAttachments bool
+	SkipLinks       bool
+}
+
+// Problem is a part of a backup which could not be restored
+type Problem = issuecopy.Problem
+
+// RestoreReport is the result of a restore
+type RestoreReport struct {
+	// Issues maps the keys of the backup to the keys of the restored issues
+	Issues map[string]string
+	// Versions and Components are the names of the versions and components which were created
+	Versions   []string
+	Components []string
+	// Problems lists everything which could not be restored
+	Problems []Problem
+}
+
+// restorer holds the state of a restore
+type restorer struct {
+	client    *jira.Client
+	archive   *Archive
+	options   RestoreOptions
+	project   *jira.MetaProject
+	converter *issuecopy.Converter
+	sameSite  bool
+	report    *RestoreReport
+	keys      map[string]bool
+	restored  map[string]*IssueRecord
+	issueKeys []string
+}
+
+// RestoreWithContext recreates the content of a backup archive in an existing project: missing versions and components,
+// then the issues with their statuses, comments, worklogs and attachments, and finally the links between the issues.
+// Only fields available on the create screen of the target are restored. Issues are created by the restoring user,
+// so authors and creation times are lost unless RestoreOptions.Attribute is set.
+//
+// Parts which can not be restored are listed as problems in the report; an error is returned
+// only if the restore could not run at all.
+func RestoreWithContext(ctx context.Context, client *jira.Client, archive *Archive, options *RestoreOptions) (*RestoreReport, error) {
+	r := &restorer{
+		client:   client,
+		archive:  archive,
+		report:   &RestoreReport{Issues: make(map[string]string)},
+		keys:     make(map[string]bool),
+		restored: make(map[string]*IssueRecord),
+	}
+	if options != nil {
+		r.options = *options
+	}
+	if r.options.ProjectKey == "" {
+		r.options.ProjectKey = archive.Manifest.Project
+	}
+	baseURL := client.GetBaseURL()
+	r.sameSite = strings.TrimSuffix(baseURL.String(), "/") == strings.TrimSuffix(archive.Manifest.Source, "/")
+	r.converter = &issuecopy.Converter{
+		SourceFields: make(map[string]jira.Field),
+		Key:          r.parentKey,
+		Text:         r.text,
+		User:         r.user,
+		Field:        r.targetField,
+	}
+	for _, field := range archive.Fields {
+		r.converter.SourceFields[field.ID] = field
+	}
+	for _, record := range archive.Issues {
+		r.keys[record.Issue.Key] = true
+	}
+
+	project, _, err := client.Project.GetWithContext(ctx, r.options.ProjectKey)
+	if err != nil {
+		return nil, err
+	}
+	setup := &issuecopy.ProjectSetup{Client: client, Project: project}
+	setup.CreateVersionsWithContext(ctx, archive.Project.Versions)
+	setup.CreateComponentsWithContext(ctx, archive.Project.Components)
+	r.report.Versions = setup.Versions
+	r.report.Components = setup.Components
+	r.report.Problems = append(r.report.Problems, setup.Problems...)
+
+	meta, _, err := client.Issue.GetCreateMetaWithContext(ctx, project.Key)
+	if err != nil {
+		return nil, err
+	}
+	r.project = meta.GetProjectWithKey(project.Key)
+	if r.project == nil {
+		return nil, fmt.Errorf("no create permission in project %s", project.Key)
+	}
+
+	// Issues are created in passes, so that parents exist before their sub-tasks and children
+	pending := archive.Issues
+	for len(pending) > 0 {
+		var waiting []*IssueRecord
+		for _, record := range pending {
+			if parent := record.Issue.Fields.Parent; parent != nil && r.keys[parent.Key] && r.restored[parent.Key] == nil {
+				waiting = append(waiting, record)
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return r.report, err
+			}
+			r.restoreIssueWithContext(ctx, record)
+		}
+		if len(waiting) == len(pending) {
+			for _, record := range waiting {
+				r.problem(record.Issue.Key, "issue", fmt.Sprintf("parent %s was not restored", record.Issue.Fields.Parent.Key))
+			}
+			break
+		}
+		pending = waiting
+	}
+
+	if !r.options.SkipLinks {
+		for _, key := range r.issueKeys {
+			if err := ctx.Err(); err != nil {
+				return r.report, err
+			}
+			r.restoreLinksWithContext(ctx, r.restored[key])
+		}
+	}
+	return r.report, nil
+}
+
+// Restore wraps RestoreWithContext using the background context.
+func Restore(client *jira.Client, archive *Archive, options *RestoreOptions) (*RestoreReport, error) {
+	return RestoreWithContext(context.Background(), client, archive, options)
+}
+
+func (r *restorer) problem(key, item, message string) {
+	r.report.Problems = append(r.report.Problems, Problem{Key: key, Item: item, Message: message})
+}
+
+// restoreIssueWithContext creates an issue and adds its status, comments, worklogs and attachments
+func (r *restorer) restoreIssueWithContext(ctx context.Context, record *IssueRecord) {
+	source := record.Issue
+	typeName := source.Fields.Type.Name
+	if mapped, ok := r.options.IssueTypes[typeName]; ok {
+		typeName = mapped
+	}
+	issueType := r.project.GetIssueTypeWithName(typeName)
+	if issueType == nil {
+		r.problem(source.Key, "issue", fmt.Sprintf("issue type %q does not exist in project %s", typeName, r.project.Key))
+		return
+	}
+
+	fields, dropped, err := r.converter.Fields(source, r.project.Key, issueType)
+	if err != nil {
+		r.problem(source.Key, "issue", err.Error())
+		return
+	}
+	issue, resp, err := r.client.Issue.CreateWithContext(ctx, &jira.Issue{Fields: &jira.IssueFields{Unknowns: fields}})
+	if err != nil {
+		if resp != nil {
+			err = jira.NewJiraError(resp, err)
+		}
+		r.problem(source.Key, "issue", err.Error())
+		return
+	}
+	r.report.Issues[source.Key] = issue.Key
+	r.restored[source.Key] = record
+	r.issueKeys = append(r.issueKeys, source.Key)
+	if len(dropped) > 0 {
+		r.problem(source.Key, "fields", "not restored: "+strings.Join(dropped, ", "))
+	}
+
+	if !r.options.SkipStatuses && source.Fields.Status != nil {
+		if _, err := r.client.Issue.TransitionToStatusWithContext(ctx, issue.Key, source.Fields.Status.Name, 0); err != nil {
+			r.problem(source.Key, "status "+source.Fields.Status.Name, err.Error())
+		}
+	}
+
+	if !r.options.SkipComments {
+		for _, comment := range record.Comments {
+			body := comment.Body
+			if r.options.Attribute {
+				body = issuecopy.Attribution(&comment.Author, comment.Created) + body
+			}
+			_, _, err := r.client.Issue.AddCommentWithContext(ctx, issue.Key, &jira.Comment{Body: body, Visibility: comment.Visibility})
+			if err != nil {
+				r.problem(source.Key, "comment "+comment.ID, err.Error())
+			}
+		}
+	}
+
+	if !r.options.SkipWorklogs {
+		for _, worklog := range record.Worklogs {
+			comment := worklog.Comment
+			if r.options.Attribute {
+				comment = issuecopy.Attribution(worklog.Author, worklog.Created) + comment
+			}
+			_, _, err := r.client.Issue.AddWorklogRecordWithContext(ctx, issue.Key, &jira.WorklogRecord{
+				Comment:          comment,
+				Started:          worklog.Started,
+				TimeSpentSeconds: worklog.TimeSpentSeconds,
+			})
+			if err != nil {
+				r.problem(source.Key, "worklog "+worklog.ID, err.Error())
+			}
+		}
+	}
+
+	if !r.options.SkipAttachments {
+		for _, attachment := range source.Fields.Attachments {
+			if err := r.restoreAttachmentWithContext(ctx, issue.Key, attachment); err != nil {
+				r.problem(source.Key, "attachment "+attachment.Filename, err.Error())
+			}
+		}
+	}
+}
+
+func (r *restorer) restoreAttachmentWithContext(ctx context.Context, issueKey string, attachment *jira.Attachment) error {
+	content, err := r.archive.OpenAttachment(attachment.ID)
+	if err != nil {
+		return fmt.Errorf("not in the backup: %w", err)
+	}
+	defer content.Close()
+	_, _, err = r.client.Issue.PostAttachmentWithContext(ctx, issueKey, content, attachment.Filename)
+	return err
+}
+
+// restoreLinksWithContext creates the links of a restored issue. A link between two issues of the backup is stored
+// in both of them and restored from its source side. Links to issues outside of the backup are kept
+// if the backup is restored into the instance it was taken from.
+func (r *restorer) restoreLinksWithContext(ctx context.Context, record *IssueRecord) {
+	source := record.Issue
+	for _, link := range source.Fields.IssueLinks {
+		var other string
+		issueLink := &jira.IssueLink{Type: jira.IssueLinkType{Name: link.Type.Name}}
+		if link.OutwardIssue != nil {
+			other = link.OutwardIssue.Key
+			issueLink.InwardIssue = &jira.Issue{Key: r.report.Issues[source.Key]}
+			issueLink.OutwardIssue = &jira.Issue{Key: other}
+		} else if link.InwardIssue != nil {
+			other = link.InwardIssue.Key
+			if r.keys[other] {
+				continue
+			}
+			issueLink.InwardIssue = &jira.Issue{Key: other}
+			issueLink.OutwardIssue = &jira.Issue{Key: r.report.Issues[source.Key]}
+		} else {
+			continue
+		}
+
+		item := fmt.Sprintf("link %q to %s", link.Type.Name, other)
+		if r.keys[other] {
+			target, ok := r.report.Issues[other]
+			if !ok {
+				r.problem(source.Key, item, "the linked issue was not restored")
+				continue
+			}
+			if link.OutwardIssue != nil {
+				issueLink.OutwardIssue.Key = target
+			}
+		} else if !r.sameSite {
+			r.problem(source.Key, item, "the linked issue is not in the backup")
+			continue
+		}
+
+		if _, err := r.client.Issue.AddLinkWithContext(ctx, issueLink); err != nil {
+			r.problem(source.Key, item, err.Error())
+		}
+	}
+}
+
+// user returns the reference to the target user of a user of the backup, identified by its account id, name, key and email address
+func (r *restorer) user(user *jira.User) (map[string]string, error) {
+	for _, id := range []string{user.AccountID, user.Name, user.Key, user.EmailAddress} {
+		if id == "" {
+			continue
+		}
+		if target, ok := r.options.Users[id]; ok {
+			if target == "" {
+				return nil, nil
+			}
+			if r.options.UserNames {
+				return map[string]string{"name": target}, nil
+			}
+			return map[string]string{"accountId": target}, nil
+		}
+	}
+	if r.options.UserNames {
+		if user.Name == "" {
+			return nil, nil
+		}
+		return map[string]string{"name": user.Name}, nil
+	}
+	if user.AccountID == "" {
+		return nil, nil
+	}
+	return map[string]string{"accountId": user.AccountID}, nil
+}
+
+// parentKey returns the key of the parent of a restored issue: its restored copy,
+// or the parent itself if the backup is restored into the instance it was taken from
+func (r *restorer) parentKey(key string) (string, bool) {
+	if target, ok := r.report.Issues[key]; ok {
+		return target, true
+	}
+	return key, r.sameSite
+}
+
+// text replaces a text which is the key of a restored issue by the new key
+func (r *restorer) text(text string) string {
+	if key, ok := r.report.Issues[text]; ok {
+		return key
+	}
+	return text
+}
+
+// targetField returns the custom field of the target for a custom field of the backup
+func (r *restorer) targetField(id string) (string, bool) {
+	if mapped, ok := r.options.Fields[id]; ok {
+		return mapped, true
+	}
+	return id, true
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"time"
 )
 
 // BoardService handles Agile Boards for the Jira instance / API.
@@ -65,14 +64,15 @@ type SprintsList struct {
 
 // Sprint represents a sprint on Jira agile board
 type Sprint struct {
-	ID            int        `json:"id" structs:"id"`
-	Name          string     `json:"name" structs:"name"`
-	CompleteDate  *time.Time `json:"completeDate" structs:"completeDate"`
-	EndDate       *time.Time `json:"endDate" structs:"endDate"`
-	StartDate     *time.Time `json:"startDate" structs:"startDate"`
-	OriginBoardID int        `json:"originBoardId" structs:"originBoardId"`
-	Self          string     `json:"self" structs:"self"`
-	State         string     `json:"state" structs:"state"`
+	ID            int    `json:"id" structs:"id"`
+	Name          string `json:"name" structs:"name"`
+	CompleteDate  *Time  `json:"completeDate" structs:"completeDate"`
+	EndDate       *Time  `json:"endDate" structs:"endDate"`
+	StartDate     *Time  `json:"startDate" structs:"startDate"`
+	OriginBoardID int    `json:"originBoardId" structs:"originBoardId"`
+	Self          string `json:"self" structs:"self"`
+	State         string `json:"state" structs:"state"`
+	Goal          string `json:"goal" structs:"goal"`
 }
 
 // BoardConfiguration represents a boardConfiguration of a jira board
@@ -313,3 +313,515 @@ func (s *BoardService) GetBoardConfigurationWithContext(ctx context.Context, boa
 func (s *BoardService) GetBoardConfiguration(boardID int) (*BoardConfiguration, *Response, error) {
 	return s.GetBoardConfigurationWithContext(context.Background(), boardID)
 }
+
+// AgilePageOptions specifies the paging parameters of the agile list methods
+type AgilePageOptions struct {
+	// StartAt: The starting index of the returned values. Base index: 0.
+	StartAt int `url:"startAt,omitempty"`
+	// MaxResults: The maximum number of values to return per page. Default: 50.
+	MaxResults int `url:"maxResults,omitempty"`
+}
+
+// BoardEpicsOptions specifies the optional parameters to the BoardService.GetEpicsWithOptions
+type BoardEpicsOptions struct {
+	// Done filters the epics by their done state. Valid values: true, false. By default all epics are returned.
+	Done string `url:"done,omitempty"`
+
+	AgilePageOptions
+}
+
+// BoardVersionsOptions specifies the optional parameters to the BoardService.GetVersionsWithOptions
+type BoardVersionsOptions struct {
+	// Released filters the versions by their released state. Valid values: true, false. By default all versions are returned.
+	Released string `url:"released,omitempty"`
+
+	AgilePageOptions
+}
+
+// EpicsList reflects a list of agile epics
+type EpicsList struct {
+	MaxResults int    `json:"maxResults" structs:"maxResults"`
+	StartAt    int    `json:"startAt" structs:"startAt"`
+	Total      int    `json:"total" structs:"total"`
+	IsLast     bool   `json:"isLast" structs:"isLast"`
+	Values     []Epic `json:"values" structs:"values"`
+}
+
+// QuickFilter represents a quick filter of an agile board
+type QuickFilter struct {
+	ID          int    `json:"id,omitempty" structs:"id,omitempty"`
+	BoardID     int    `json:"boardId,omitempty" structs:"boardId,omitempty"`
+	Name        string `json:"name,omitempty" structs:"name,omitempty"`
+	JQL         string `json:"jql,omitempty" structs:"jql,omitempty"`
+	Description string `json:"description,omitempty" structs:"description,omitempty"`
+	Position    int    `json:"position,omitempty" structs:"position,omitempty"`
+}
+
+// QuickFiltersList reflects a list of quick filters of an agile board
+type QuickFiltersList struct {
+	MaxResults int           `json:"maxResults" structs:"maxResults"`
+	StartAt    int           `json:"startAt" structs:"startAt"`
+	Total      int           `json:"total" structs:"total"`
+	IsLast     bool          `json:"isLast" structs:"isLast"`
+	Values     []QuickFilter `json:"values" structs:"values"`
+}
+
+// BoardProjectsList reflects a list of the projects associated with an agile board
+type BoardProjectsList struct {
+	MaxResults int       `json:"maxResults" structs:"maxResults"`
+	StartAt    int       `json:"startAt" structs:"startAt"`
+	Total      int       `json:"total" structs:"total"`
+	IsLast     bool      `json:"isLast" structs:"isLast"`
+	Values     []Project `json:"values" structs:"values"`
+}
+
+// BoardVersionsList reflects a list of the versions of the projects associated with an agile board
+type BoardVersionsList struct {
+	MaxResults int       `json:"maxResults" structs:"maxResults"`
+	StartAt    int       `json:"startAt" structs:"startAt"`
+	Total      int       `json:"total" structs:"total"`
+	IsLast     bool      `json:"isLast" structs:"isLast"`
+	Values     []Version `json:"values" structs:"values"`
+}
+
+// getIssuesWithContext returns one page of issues of an agile issue endpoint
+func (s *BoardService) getIssuesWithContext(ctx context.Context, apiEndpoint string, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	url, err := addOptions(apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(IssuesInSprintResult)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+
+	return result, resp, nil
+}
+
+// getIssuesPagesWithContext calls f for every issue of an agile issue endpoint, reading all pages
+func (s *BoardService) getIssuesPagesWithContext(ctx context.Context, apiEndpoint string, options *AgileIssuesOptions, f func(Issue) error) error {
+	opt := AgileIssuesOptions{MaxResults: 50}
+	if options != nil {
+		opt = *options
+	}
+
+	for {
+		result, _, err := s.getIssuesWithContext(ctx, apiEndpoint, &opt)
+		if err != nil {
+			return err
+		}
+		for _, issue := range result.Issues {
+			if err = f(issue); err != nil {
+				return err
+			}
+		}
+		if len(result.Issues) == 0 || result.StartAt+len(result.Issues) >= result.Total {
+			return nil
+		}
+		opt.StartAt = result.StartAt + len(result.Issues)
+	}
+}
+
+// GetBacklogIssuesWithContext returns one page of the issues in the backlog of a board.
+// The backlog contains the incomplete issues that are not assigned to any future or active sprint.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-getIssuesForBacklog
+func (s *BoardService) GetBacklogIssuesWithContext(ctx context.Context, boardID int, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.getIssuesWithContext(ctx, fmt.Sprintf("rest/agile/1.0/board/%d/backlog", boardID), options)
+}
+
+// GetBacklogIssues wraps GetBacklogIssuesWithContext using the background context.
+func (s *BoardService) GetBacklogIssues(boardID int, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.GetBacklogIssuesWithContext(context.Background(), boardID, options)
+}
+
+// GetBacklogIssuesPagesWithContext calls f for every issue in the backlog of a board, reading all pages.
+func (s *BoardService) GetBacklogIssuesPagesWithContext(ctx context.Context, boardID int, options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.getIssuesPagesWithContext(ctx, fmt.Sprintf("rest/agile/1.0/board/%d/backlog", boardID), options, f)
+}
+
+// GetBacklogIssuesPages wraps GetBacklogIssuesPagesWithContext using the background context.
+func (s *BoardService) GetBacklogIssuesPages(boardID int, options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.GetBacklogIssuesPagesWithContext(context.Background(), boardID, options, f)
+}
+
+// GetIssuesWithContext returns one page of the issues of a board.
+// The issues can be filtered further with the JQL of the options.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-getIssuesForBoard
+func (s *BoardService) GetIssuesWithContext(ctx context.Context, boardID int, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.getIssuesWithContext(ctx, fmt.Sprintf("rest/agile/1.0/board/%d/issue", boardID), options)
+}
+
+// GetIssues wraps GetIssuesWithContext using the background context.
+func (s *BoardService) GetIssues(boardID int, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.GetIssuesWithContext(context.Background(), boardID, options)
+}
+
+// GetIssuesPagesWithContext calls f for every issue of a board, reading all pages.
+func (s *BoardService) GetIssuesPagesWithContext(ctx context.Context, boardID int, options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.getIssuesPagesWithContext(ctx, fmt.Sprintf("rest/agile/1.0/board/%d/issue", boardID), options, f)
+}
+
+// GetIssuesPages wraps GetIssuesPagesWithContext using the background context.
+func (s *BoardService) GetIssuesPages(boardID int, options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.GetIssuesPagesWithContext(context.Background(), boardID, options, f)
+}
+
+// GetIssuesWithoutEpicWithContext returns one page of the issues of a board that do not belong to an epic.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/epic-getIssuesWithoutEpic
+func (s *BoardService) GetIssuesWithoutEpicWithContext(ctx context.Context, boardID int, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.getIssuesWithContext(ctx, fmt.Sprintf("rest/agile/1.0/board/%d/epic/none/issue", boardID), options)
+}
+
+// GetIssuesWithoutEpic wraps GetIssuesWithoutEpicWithContext using the background context.
+func (s *BoardService) GetIssuesWithoutEpic(boardID int, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.GetIssuesWithoutEpicWithContext(context.Background(), boardID, options)
+}
+
+// GetIssuesWithoutEpicPagesWithContext calls f for every issue of a board that does not belong to an epic, reading all pages.
+func (s *BoardService) GetIssuesWithoutEpicPagesWithContext(ctx context.Context, boardID int, options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.getIssuesPagesWithContext(ctx, fmt.Sprintf("rest/agile/1.0/board/%d/epic/none/issue", boardID), options, f)
+}
+
+// GetIssuesWithoutEpicPages wraps GetIssuesWithoutEpicPagesWithContext using the background context.
+func (s *BoardService) GetIssuesWithoutEpicPages(boardID int, options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.GetIssuesWithoutEpicPagesWithContext(context.Background(), boardID, options, f)
+}
+
+// GetEpicsWithOptionsWithContext returns one page of the epics of a board.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/epic-getEpics
+func (s *BoardService) GetEpicsWithOptionsWithContext(ctx context.Context, boardID int, options *BoardEpicsOptions) (*EpicsList, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/epic", boardID)
+	url, err := addOptions(apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(EpicsList)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+
+	return result, resp, nil
+}
+
+// GetEpicsWithOptions wraps GetEpicsWithOptionsWithContext using the background context.
+func (s *BoardService) GetEpicsWithOptions(boardID int, options *BoardEpicsOptions) (*EpicsList, *Response, error) {
+	return s.GetEpicsWithOptionsWithContext(context.Background(), boardID, options)
+}
+
+// GetAllEpicsWithContext returns all epics of a board, reading all pages.
+func (s *BoardService) GetAllEpicsWithContext(ctx context.Context, boardID int, options *BoardEpicsOptions) ([]Epic, error) {
+	opt := BoardEpicsOptions{}
+	if options != nil {
+		opt = *options
+	}
+
+	var epics []Epic
+	for {
+		result, _, err := s.GetEpicsWithOptionsWithContext(ctx, boardID, &opt)
+		if err != nil {
+			return nil, err
+		}
+		epics = append(epics, result.Values...)
+		if result.IsLast || len(result.Values) == 0 {
+			return epics, nil
+		}
+		opt.StartAt = result.StartAt + len(result.Values)
+	}
+}
+
+// GetAllEpics wraps GetAllEpicsWithContext using the background context.
+func (s *BoardService) GetAllEpics(boardID int, options *BoardEpicsOptions) ([]Epic, error) {
+	return s.GetAllEpicsWithContext(context.Background(), boardID, options)
+}
+
+// GetQuickFiltersWithOptionsWithContext returns one page of the quick filters of a board.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/quickfilter-getAllQuickFilters
+func (s *BoardService) GetQuickFiltersWithOptionsWithContext(ctx context.Context, boardID int, options *AgilePageOptions) (*QuickFiltersList, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/quickfilter", boardID)
+	url, err := addOptions(apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(QuickFiltersList)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+
+	return result, resp, nil
+}
+
+// GetQuickFiltersWithOptions wraps GetQuickFiltersWithOptionsWithContext using the background context.
+func (s *BoardService) GetQuickFiltersWithOptions(boardID int, options *AgilePageOptions) (*QuickFiltersList, *Response, error) {
+	return s.GetQuickFiltersWithOptionsWithContext(context.Background(), boardID, options)
+}
+
+// GetAllQuickFiltersWithContext returns all quick filters of a board, reading all pages.
+func (s *BoardService) GetAllQuickFiltersWithContext(ctx context.Context, boardID int) ([]QuickFilter, error) {
+	opt := AgilePageOptions{}
+
+	var filters []QuickFilter
+	for {
+		result, _, err := s.GetQuickFiltersWithOptionsWithContext(ctx, boardID, &opt)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, result.Values...)
+		if result.IsLast || len(result.Values) == 0 {
+			return filters, nil
+		}
+		opt.StartAt = result.StartAt + len(result.Values)
+	}
+}
+
+// GetAllQuickFilters wraps GetAllQuickFiltersWithContext using the background context.
+func (s *BoardService) GetAllQuickFilters(boardID int) ([]QuickFilter, error) {
+	return s.GetAllQuickFiltersWithContext(context.Background(), boardID)
+}
+
+// GetQuickFilterWithContext returns a single quick filter of a board.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board/{boardId}/quickfilter-getQuickFilter
+func (s *BoardService) GetQuickFilterWithContext(ctx context.Context, boardID, quickFilterID int) (*QuickFilter, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/quickfilter/%d", boardID, quickFilterID)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter := new(QuickFilter)
+	resp, err := s.client.Do(req, filter)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+
+	return filter, resp, nil
+}
+
+// GetQuickFilter wraps GetQuickFilterWithContext using the background context.
+func (s *BoardService) GetQuickFilter(boardID, quickFilterID int) (*QuickFilter, *Response, error) {
+	return s.GetQuickFilterWithContext(context.Background(), boardID, quickFilterID)
+}
+
+// GetProjectsWithOptionsWithContext returns one page of the projects that are associated with a board.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-getProjects
+func (s *BoardService) GetProjectsWithOptionsWithContext(ctx context.Context, boardID int, options *AgilePageOptions) (*BoardProjectsList, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/project", boardID)
+	url, err := addOptions(apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(BoardProjectsList)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+
+	return result, resp, nil
+}
+
+// GetProjectsWithOptions wraps GetProjectsWithOptionsWithContext using the background context.
+func (s *BoardService) GetProjectsWithOptions(boardID int, options *AgilePageOptions) (*BoardProjectsList, *Response, error) {
+	return s.GetProjectsWithOptionsWithContext(context.Background(), boardID, options)
+}
+
+// GetAllProjectsWithContext returns all projects that are associated with a board, reading all pages.
+func (s *BoardService) GetAllProjectsWithContext(ctx context.Context, boardID int) ([]Project, error) {
+	opt := AgilePageOptions{}
+
+	var projects []Project
+	for {
+		result, _, err := s.GetProjectsWithOptionsWithContext(ctx, boardID, &opt)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, result.Values...)
+		if result.IsLast || len(result.Values) == 0 {
+			return projects, nil
+		}
+		opt.StartAt = result.StartAt + len(result.Values)
+	}
+}
+
+// GetAllProjects wraps GetAllProjectsWithContext using the background context.
+func (s *BoardService) GetAllProjects(boardID int) ([]Project, error) {
+	return s.GetAllProjectsWithContext(context.Background(), boardID)
+}
+
+// GetVersionsWithOptionsWithContext returns one page of the versions of the projects that are associated with a board.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-getAllVersions
+func (s *BoardService) GetVersionsWithOptionsWithContext(ctx context.Context, boardID int, options *BoardVersionsOptions) (*BoardVersionsList, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/version", boardID)
+	url, err := addOptions(apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := s.client.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(BoardVersionsList)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+
+	return result, resp, nil
+}
+
+// GetVersionsWithOptions wraps GetVersionsWithOptionsWithContext using the background context.
+func (s *BoardService) GetVersionsWithOptions(boardID int, options *BoardVersionsOptions) (*BoardVersionsList, *Response, error) {
+	return s.GetVersionsWithOptionsWithContext(context.Background(), boardID, options)
+}
+
+// GetAllVersionsWithContext returns all versions of the projects that are associated with a board, reading all pages.
+func (s *BoardService) GetAllVersionsWithContext(ctx context.Context, boardID int, options *BoardVersionsOptions) ([]Version, error) {
+	opt := BoardVersionsOptions{}
+	if options != nil {
+		opt = *options
+	}
+
+	var versions []Version
+	for {
+		result, _, err := s.GetVersionsWithOptionsWithContext(ctx, boardID, &opt)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, result.Values...)
+		if result.IsLast || len(result.Values) == 0 {
+			return versions, nil
+		}
+		opt.StartAt = result.StartAt + len(result.Values)
+	}
+}
+
+// GetAllVersions wraps GetAllVersionsWithContext using the background context.
+func (s *BoardService) GetAllVersions(boardID int, options *BoardVersionsOptions) ([]Version, error) {
+	return s.GetAllVersionsWithContext(context.Background(), boardID, options)
+}
+
+// GetPropertyKeysWithContext returns the keys of all properties of a board.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-getBoardPropertyKeys
+func (s *BoardService) GetPropertyKeysWithContext(ctx context.Context, boardID int) (*PropertyKeys, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/properties", boardID)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := new(PropertyKeys)
+	resp, err := s.client.Do(req, keys)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+
+	return keys, resp, nil
+}
+
+// GetPropertyKeys wraps GetPropertyKeysWithContext using the background context.
+func (s *BoardService) GetPropertyKeys(boardID int) (*PropertyKeys, *Response, error) {
+	return s.GetPropertyKeysWithContext(context.Background(), boardID)
+}
+
+// GetPropertyWithContext returns the value of a board property.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-getBoardProperty
+func (s *BoardService) GetPropertyWithContext(ctx context.Context, boardID int, propertyKey string) (*EntityProperty, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/properties/%s", boardID, propertyKey)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	property := new(EntityProperty)
+	resp, err := s.client.Do(req, property)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+
+	return property, resp, nil
+}
+
+// GetProperty wraps GetPropertyWithContext using the background context.
+func (s *BoardService) GetProperty(boardID int, propertyKey string) (*EntityProperty, *Response, error) {
+	return s.GetPropertyWithContext(context.Background(), boardID, propertyKey)
+}
+
+// SetPropertyWithContext sets the value of a board property. The value is sent as JSON.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-setBoardProperty
+// Caller must close resp.Body
+func (s *BoardService) SetPropertyWithContext(ctx context.Context, boardID int, propertyKey string, value interface{}) (*Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/properties/%s", boardID, propertyKey)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", apiEndpoint, value)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		err = NewJiraError(resp, err)
+	}
+	return resp, err
+}
+
+// SetProperty wraps SetPropertyWithContext using the background context.
+// Caller must close resp.Body
+func (s *BoardService) SetProperty(boardID int, propertyKey string, value interface{}) (*Response, error) {
+	return s.SetPropertyWithContext(context.Background(), boardID, propertyKey, value)
+}
+
+// DeletePropertyWithContext removes a property from a board.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/board-deleteBoardProperty
+// Caller must close resp.Body
+func (s *BoardService) DeletePropertyWithContext(ctx context.Context, boardID int, propertyKey string) (*Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/board/%d/properties/%s", boardID, propertyKey)
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", apiEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		err = NewJiraError(resp, err)
+	}
+	return resp, err
+}
+
+// DeleteProperty wraps DeletePropertyWithContext using the background context.
+// Caller must close resp.Body
+func (s *BoardService) DeleteProperty(boardID int, propertyKey string) (*Response, error) {
+	return s.DeletePropertyWithContext(context.Background(), boardID, propertyKey)
+}
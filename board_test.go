@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -264,3 +266,235 @@ func TestBoardService_GetBoardConfigoration(t *testing.T) {
 		t.Errorf("Expected a max of 0 issues in progress. Got %d", inProgressColumn.Max)
 	}
 }
+
+func TestBoardService_GetBacklogIssuesPages(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/backlog"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		if r.URL.Query().Get("jql") != "priority = High" {
+			t.Errorf("Expected the JQL to be passed, got %q", r.URL.Query().Get("jql"))
+		}
+		switch r.URL.Query().Get("startAt") {
+		case "":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":1,"total":2,"issues":[{"key":"TST-1"}]}`)
+		case "1":
+			fmt.Fprint(w, `{"startAt":1,"maxResults":1,"total":2,"issues":[{"key":"TST-2"}]}`)
+		default:
+			t.Errorf("Unexpected startAt %s", r.URL.Query().Get("startAt"))
+		}
+	})
+
+	var keys []string
+	err := testClient.Board.GetBacklogIssuesPages(1, &AgileIssuesOptions{MaxResults: 1, JQL: "priority = High"}, func(issue Issue) error {
+		keys = append(keys, issue.Key)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"TST-1", "TST-2"}) {
+		t.Errorf("Expected issues TST-1 and TST-2, got %v", keys)
+	}
+}
+
+func TestBoardService_GetIssues(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/issue"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		testRequestParams(t, r, map[string]string{"fields": "summary,status"})
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":1,"issues":[{"key":"TST-1","fields":{"summary":"First"}}]}`)
+	})
+
+	result, _, err := testClient.Board.GetIssues(1, &AgileIssuesOptions{Fields: "summary,status"})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if result == nil || len(result.Issues) != 1 || result.Issues[0].Fields.Summary != "First" {
+		t.Errorf("Expected one issue, got %+v", result)
+	}
+}
+
+func TestBoardService_GetIssuesWithoutEpic(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/epic/none/issue"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":1,"issues":[{"key":"TST-3"}]}`)
+	})
+
+	result, _, err := testClient.Board.GetIssuesWithoutEpic(1, nil)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if result == nil || len(result.Issues) != 1 || result.Issues[0].Key != "TST-3" {
+		t.Errorf("Expected issue TST-3, got %+v", result)
+	}
+}
+
+func TestBoardService_GetAllEpics(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/epic"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		if r.URL.Query().Get("done") != "false" {
+			t.Errorf("Expected done=false, got %q", r.URL.Query().Get("done"))
+		}
+		if r.URL.Query().Get("startAt") == "" {
+			fmt.Fprint(w, `{"maxResults":1,"startAt":0,"isLast":false,"values":[{"id":37,"key":"TST-10","name":"First epic","done":false}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"maxResults":1,"startAt":1,"isLast":true,"values":[{"id":38,"key":"TST-11","name":"Second epic","done":false}]}`)
+	})
+
+	epics, err := testClient.Board.GetAllEpics(1, &BoardEpicsOptions{Done: "false"})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(epics) != 2 || epics[1].Key != "TST-11" {
+		t.Errorf("Expected 2 epics, got %+v", epics)
+	}
+}
+
+func TestBoardService_GetAllQuickFilters(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/quickfilter"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, `{"maxResults":50,"startAt":0,"isLast":true,"values":[{"id":1,"boardId":1,"name":"Only my issues","jql":"assignee = currentUser()","position":0}]}`)
+	})
+
+	filters, err := testClient.Board.GetAllQuickFilters(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(filters) != 1 || filters[0].JQL != "assignee = currentUser()" {
+		t.Errorf("Expected one quick filter, got %+v", filters)
+	}
+}
+
+func TestBoardService_GetQuickFilter(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/quickfilter/2"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, `{"id":2,"boardId":1,"name":"Bugs","jql":"type = Bug","position":1}`)
+	})
+
+	filter, _, err := testClient.Board.GetQuickFilter(1, 2)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if filter == nil || filter.Name != "Bugs" {
+		t.Errorf("Expected quick filter Bugs, got %+v", filter)
+	}
+}
+
+func TestBoardService_GetAllProjects(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/project"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, `{"maxResults":50,"startAt":0,"isLast":true,"values":[{"id":"10000","key":"TST","name":"Test"}]}`)
+	})
+
+	projects, err := testClient.Board.GetAllProjects(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(projects) != 1 || projects[0].Key != "TST" {
+		t.Errorf("Expected project TST, got %+v", projects)
+	}
+}
+
+func TestBoardService_GetAllVersions(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/board/1/version"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		testRequestParams(t, r, map[string]string{"released": "true"})
+		fmt.Fprint(w, `{"maxResults":50,"startAt":0,"isLast":true,"values":[{"id":"10100","name":"1.0","released":true}]}`)
+	})
+
+	versions, err := testClient.Board.GetAllVersions(1, &BoardVersionsOptions{Released: "true"})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(versions) != 1 || versions[0].Name != "1.0" {
+		t.Errorf("Expected version 1.0, got %+v", versions)
+	}
+}
+
+func TestBoardService_Properties(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/board/1/properties", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"keys":[{"self":"http://www.example.com/jira/rest/agile/1.0/board/1/properties/team","key":"team"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/1/properties/team", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"key":"team","value":{"name":"Core"}}`)
+		case "PUT":
+			body, _ := ioutil.ReadAll(r.Body)
+			if strings.TrimSpace(string(body)) != `{"name":"Core"}` {
+				t.Errorf("Unexpected property value %s", body)
+			}
+			w.WriteHeader(http.StatusOK)
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	})
+
+	keys, _, err := testClient.Board.GetPropertyKeys(1)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if keys == nil || len(keys.Keys) != 1 || keys.Keys[0].Key != "team" {
+		t.Errorf("Expected property key team, got %+v", keys)
+	}
+
+	property, _, err := testClient.Board.GetProperty(1, "team")
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if property == nil || property.Value.(map[string]interface{})["name"] != "Core" {
+		t.Errorf("Expected property value Core, got %+v", property)
+	}
+
+	if _, err := testClient.Board.SetProperty(1, "team", map[string]string{"name": "Core"}); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if _, err := testClient.Board.DeleteProperty(1, "team"); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
@@ -0,0 +1,251 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// maxIssuesPerBulkCreate is the maximum number of issues Jira creates with one bulk request
+const maxIssuesPerBulkCreate = 50
+
+// defaultBulkEditConcurrency is the number of parallel updates of BulkEdit if no concurrency is given
+const defaultBulkEditConcurrency = 4
+
+// BulkCreateResult holds the result of a bulk create, with one item per issue to create in the same order
+type BulkCreateResult struct {
+	Items []BulkCreateItem
+}
+
+// BulkCreateItem is the result of creating one issue of a bulk create.
+// Index is the position of the issue in the input, Issue is set if it was created and Error if it failed.
+type BulkCreateItem struct {
+	Index int
+	Issue *Issue
+	Error *Error
+}
+
+// Created returns the issues which were created, in input order
+func (r *BulkCreateResult) Created() []Issue {
+	var issues []Issue
+	for _, item := range r.Items {
+		if item.Issue != nil {
+			issues = append(issues, *item.Issue)
+		}
+	}
+	return issues
+}
+
+// Failed returns the items of the issues which could not be created
+func (r *BulkCreateResult) Failed() []BulkCreateItem {
+	var failed []BulkCreateItem
+	for _, item := range r.Items {
+		if item.Error != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// bulkCreateRequest is the payload of the bulk create call
+type bulkCreateRequest struct {
+	IssueUpdates []*Issue `json:"issueUpdates"`
+}
+
+// bulkCreateResponse is the response of the bulk create call
+type bulkCreateResponse struct {
+	Issues []Issue `json:"issues"`
+	Errors []struct {
+		Status              int   `json:"status"`
+		ElementErrors       Error `json:"elementErrors"`
+		FailedElementNumber int   `json:"failedElementNumber"`
+	} `json:"errors"`
+}
+
+// BulkCreateWithContext creates many issues with the bulk API.
+// Jira creates at most 50 issues per request; longer lists are created in chunks.
+// Issues are created independently, so some may fail while others are created.
+// The result maps every created issue and every error back to the position of the issue in the input.
+// If any issue could not be created, the result is returned together with an error.
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/issue-createIssues
+func (s *IssueService) BulkCreateWithContext(ctx context.Context, issues []*Issue) (*BulkCreateResult, error) {
+	result := &BulkCreateResult{Items: make([]BulkCreateItem, len(issues))}
+	for i := range result.Items {
+		result.Items[i].Index = i
+	}
+
+	for offset := 0; offset < len(issues); offset += maxIssuesPerBulkCreate {
+		end := offset + maxIssuesPerBulkCreate
+		if end > len(issues) {
+			end = len(issues)
+		}
+
+		response, err := s.bulkCreateWithContext(ctx, issues[offset:end])
+		if err != nil {
+			return result, err
+		}
+
+		failed := make(map[int]bool, len(response.Errors))
+		for _, element := range response.Errors {
+			index := offset + element.FailedElementNumber
+			if index < offset || index >= end {
+				continue
+			}
+			jerr := element.ElementErrors
+			jerr.HTTPError = fmt.Errorf("request failed. Status code: %d", element.Status)
+			result.Items[index].Error = &jerr
+			failed[element.FailedElementNumber] = true
+		}
+
+		// The created issues are returned in the order of the request, without the failed ones
+		created := response.Issues
+		for i := 0; i < end-offset && len(created) > 0; i++ {
+			if failed[i] {
+				continue
+			}
+			issue := created[0]
+			created = created[1:]
+			result.Items[offset+i].Issue = &issue
+		}
+	}
+
+	if failed := result.Failed(); len(failed) > 0 {
+		return result, fmt.Errorf("bulk create failed for %d of %d issues", len(failed), len(issues))
+	}
+	return result, nil
+}
+
+// BulkCreate wraps BulkCreateWithContext using the background context.
+func (s *IssueService) BulkCreate(issues []*Issue) (*BulkCreateResult, error) {
+	return s.BulkCreateWithContext(context.Background(), issues)
+}
+
+// bulkCreateWithContext sends one bulk create request.
+// Jira answers with 400 if none of the issues could be created, with the same error details as for a partial failure.
+func (s *IssueService) bulkCreateWithContext(ctx context.Context, issues []*Issue) (*bulkCreateResponse, error) {
+	apiEndpoint := "rest/api/2/issue/bulk"
+	req, err := s.client.NewRequestWithContext(ctx, "POST", apiEndpoint, bulkCreateRequest{IssueUpdates: issues})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if resp == nil {
+		return nil, NewJiraError(resp, err)
+	}
+
+	data, readErr := ioutil.ReadAll(resp.Body)
+	Cleanup(resp)
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	response := new(bulkCreateResponse)
+	decodeErr := json.Unmarshal(data, response)
+	if err != nil {
+		if resp.StatusCode == http.StatusBadRequest && decodeErr == nil && len(response.Errors) > 0 {
+			return response, nil
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+		return nil, NewJiraError(resp, err)
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("could not unmarshall the data into struct")
+	}
+	return response, nil
+}
+
+// BulkEditOptions specifies the optional parameters for IssueService.BulkEdit
+type BulkEditOptions struct {
+	// Concurrency is the number of issues updated in parallel. Default: 4.
+	Concurrency int
+	// Progress is called after every updated (or failed) issue with the number of processed issues and the total
+	Progress func(done, total int)
+}
+
+// BulkEditResult holds the result of a bulk edit
+type BulkEditResult struct {
+	// Updated are the keys of the updated issues
+	Updated []string
+	// Errors holds the errors by issue key of the issues which could not be updated
+	Errors map[string]error
+}
+
+// BulkEditWithContext applies the same changes to all issues matching the JQL query.
+// data is sent to the edit issue API as is, e.g. {"fields": {"labels": ["triaged"]}} or {"update": {...}}.
+// Issues are updated with bounded parallelism; failed issues do not stop the others.
+// If the context is cancelled, the remaining issues are not updated and the context error is returned.
+func (s *IssueService) BulkEditWithContext(ctx context.Context, jql string, data map[string]interface{}, options *BulkEditOptions) (*BulkEditResult, error) {
+	concurrency := defaultBulkEditConcurrency
+	var progress func(done, total int)
+	if options != nil {
+		if options.Concurrency > 0 {
+			concurrency = options.Concurrency
+		}
+		progress = options.Progress
+	}
+
+	var keys []string
+	err := s.SearchPagesWithContext(ctx, jql, &SearchOptions{MaxResults: 100, Fields: []string{"key"}}, func(issue Issue) error {
+		keys = append(keys, issue.Key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkEditResult{Errors: make(map[string]error)}
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan string)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range queue {
+				resp, err := s.UpdateIssueWithContext(ctx, key, data)
+				if err != nil {
+					err = NewJiraError(resp, err)
+				} else {
+					Cleanup(resp)
+				}
+
+				mutex.Lock()
+				if err != nil {
+					result.Errors[key] = err
+				} else {
+					result.Updated = append(result.Updated, key)
+				}
+				if progress != nil {
+					progress(len(result.Updated)+len(result.Errors), len(keys))
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- key
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// BulkEdit wraps BulkEditWithContext using the background context.
+func (s *IssueService) BulkEdit(jql string, data map[string]interface{}, options *BulkEditOptions) (*BulkEditResult, error) {
+	return s.BulkEditWithContext(context.Background(), jql, data, options)
+}
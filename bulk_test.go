@@ -0,0 +1,168 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestIssueService_BulkCreate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	testMux.HandleFunc("/rest/api/2/issue/bulk", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		calls++
+		request := struct {
+			IssueUpdates []json.RawMessage `json:"issueUpdates"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if calls == 1 {
+			if len(request.IssueUpdates) != 50 {
+				t.Errorf("Expected 50 issues in the first chunk, got %d", len(request.IssueUpdates))
+			}
+			issues := make([]string, 0, 49)
+			for i := 0; i < 50; i++ {
+				if i != 1 {
+					issues = append(issues, fmt.Sprintf(`{"id":"%d","key":"TST-%d"}`, 10000+i, i))
+				}
+			}
+			fmt.Fprintf(w, `{"issues":[%s],"errors":[{"status":400,"elementErrors":{"errors":{"summary":"You must specify a summary of the issue."}},"failedElementNumber":1}]}`, strings.Join(issues, ","))
+			return
+		}
+		if len(request.IssueUpdates) != 2 {
+			t.Errorf("Expected 2 issues in the second chunk, got %d", len(request.IssueUpdates))
+		}
+		fmt.Fprint(w, `{"issues":[{"id":"10050","key":"TST-50"},{"id":"10051","key":"TST-51"}],"errors":[]}`)
+	})
+
+	issues := make([]*Issue, 52)
+	for i := range issues {
+		issues[i] = &Issue{Fields: &IssueFields{Summary: fmt.Sprintf("Issue %d", i), Project: Project{Key: "TST"}}}
+	}
+
+	result, err := testClient.Issue.BulkCreate(issues)
+	if err == nil {
+		t.Error("Expected an error for the failed issue")
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 requests, got %d", calls)
+	}
+	if len(result.Created()) != 51 {
+		t.Errorf("Expected 51 created issues, got %d", len(result.Created()))
+	}
+	failed := result.Failed()
+	if len(failed) != 1 || failed[0].Index != 1 || failed[0].Error.Errors["summary"] == "" {
+		t.Errorf("Expected the second issue to fail, got %+v", failed)
+	}
+	if result.Items[2].Issue == nil || result.Items[2].Issue.Key != "TST-2" {
+		t.Errorf("Expected the third issue to be TST-2, got %+v", result.Items[2])
+	}
+	if result.Items[51].Issue == nil || result.Items[51].Issue.Key != "TST-51" {
+		t.Errorf("Expected the last issue to be TST-51, got %+v", result.Items[51])
+	}
+}
+
+func TestIssueService_BulkCreate_AllFailed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/issue/bulk", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"issues":[],"errors":[{"status":400,"elementErrors":{"errors":{"project":"project is required"}},"failedElementNumber":0}]}`)
+	})
+
+	result, err := testClient.Issue.BulkCreate([]*Issue{{Fields: &IssueFields{Summary: "No project"}}})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	if result == nil || len(result.Failed()) != 1 || result.Failed()[0].Error.Errors["project"] != "project is required" {
+		t.Errorf("Expected the issue to fail with the project error, got %+v", result)
+	}
+}
+
+func TestIssueService_BulkEdit(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if jql := r.URL.Query().Get("jql"); jql != "project = TST" {
+			t.Errorf("Unexpected JQL %q", jql)
+		}
+		fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":3,"issues":[{"key":"TST-1"},{"key":"TST-2"},{"key":"TST-3"}]}`)
+	})
+	var mutex sync.Mutex
+	var updated []string
+	testMux.HandleFunc("/rest/api/2/issue/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		if key == "TST-2" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errorMessages":["Field 'labels' cannot be set."],"errors":{}}`)
+			return
+		}
+		mutex.Lock()
+		updated = append(updated, key)
+		mutex.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	var progress []int
+	data := map[string]interface{}{"fields": map[string]interface{}{"labels": []string{"triaged"}}}
+	result, err := testClient.Issue.BulkEdit("project = TST", data, &BulkEditOptions{
+		Concurrency: 2,
+		Progress: func(done, total int) {
+			if total != 3 {
+				t.Errorf("Expected a total of 3, got %d", total)
+			}
+			progress = append(progress, done)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+
+	sort.Strings(result.Updated)
+	if fmt.Sprint(result.Updated) != "[TST-1 TST-3]" || fmt.Sprint(updated) == "" {
+		t.Errorf("Expected TST-1 and TST-3 to be updated, got %v", result.Updated)
+	}
+	if result.Errors["TST-2"] == nil {
+		t.Errorf("Expected an error for TST-2, got %v", result.Errors)
+	}
+	if fmt.Sprint(progress) != "[1 2 3]" {
+		t.Errorf("Expected progress 1, 2, 3, got %v", progress)
+	}
+}
+
+func TestIssueService_BulkEdit_Cancelled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":1,"issues":[{"key":"TST-1"}]}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	testMux.HandleFunc("/rest/api/2/issue/TST-1", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no update after the context was cancelled")
+	})
+	cancel()
+
+	if _, err := testClient.Issue.BulkEditWithContext(ctx, "project = TST", map[string]interface{}{}, nil); err == nil {
+		t.Error("Expected an error for the cancelled context")
+	}
+}
@@ -0,0 +1,94 @@
+package jira
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fieldChange is a single change of one issue field, taken from the changelog of an issue.
+type fieldChange struct {
+	At         time.Time
+	From       string
+	FromString string
+	To         string
+	ToString   string
+}
+
+// fieldChanges returns the changes of the field with the given name (case insensitive),
+// ordered from the oldest to the newest change.
+// History entries with a creation time that can not be parsed are skipped.
+func fieldChanges(issue *Issue, field string) []fieldChange {
+	if issue == nil || issue.Changelog == nil {
+		return nil
+	}
+
+	var changes []fieldChange
+	for _, history := range issue.Changelog.Histories {
+		created, err := history.CreatedTime()
+		if err != nil || created.IsZero() {
+			continue
+		}
+		for _, item := range history.Items {
+			if !strings.EqualFold(item.Field, field) {
+				continue
+			}
+			changes = append(changes, fieldChange{
+				At:         created,
+				From:       changelogValue(item.From),
+				FromString: item.FromString,
+				To:         changelogValue(item.To),
+				ToString:   item.ToString,
+			})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].At.Before(changes[j].At)
+	})
+	return changes
+}
+
+// changelogValue converts the raw from / to value of a changelog item into a string.
+// Jira returns ids as strings, but some fields (and some Jira versions) return numbers or null.
+func changelogValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// statusAt returns the id of the status the issue was in at the given point in time.
+// An empty string is returned if the issue did not exist at that time.
+func statusAt(issue *Issue, changes []fieldChange, at time.Time) string {
+	if issue.Fields == nil {
+		return ""
+	}
+	created := time.Time(issue.Fields.Created)
+	if !created.IsZero() && created.After(at) {
+		return ""
+	}
+
+	status := ""
+	if issue.Fields.Status != nil {
+		status = issue.Fields.Status.ID
+	}
+	if len(changes) > 0 {
+		status = changes[0].From
+	}
+	for _, change := range changes {
+		if change.At.After(at) {
+			break
+		}
+		status = change.To
+	}
+	return status
+}
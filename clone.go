@@ -0,0 +1,312 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultCloneLinkType is the link type used to link a clone back to its source
+const defaultCloneLinkType = "Cloners"
+
+// cloneSkippedCustomFields are the custom field types which can not be copied with the create issue API
+var cloneSkippedCustomFields = map[string]bool{
+	"com.pyxis.greenhopper.jira:gh-sprint":      true,
+	"com.pyxis.greenhopper.jira:gh-lexo-rank":   true,
+	"com.pyxis.greenhopper.jira:gh-global-rank": true,
+}
+
+// CloneOptions specifies the optional parameters for IssueService.Clone.
+// By default the clone is created in the project and with the issue type of the source,
+// together with copies of its sub-tasks, issue links, remote links and attachments.
+type CloneOptions struct {
+	// ProjectKey is the key of the project to create the clone in
+	ProjectKey string
+	// IssueType is the name of the issue type of the clone
+	IssueType string
+	// SummaryPrefix is put in front of the summary of the clone (and its sub-tasks), e.g. "CLONE - "
+	SummaryPrefix string
+
+	SkipSubtasks    bool
+	SkipLinks       bool
+	SkipRemoteLinks bool
+	SkipAttachments bool
+	// Comments copies the comments of the source. The clone's comments are authored by the current user.
+	Comments bool
+
+	// LinkType is the name of the link type used to link the clone back to the source. Default: "Cloners".
+	LinkType string
+	// SkipBackLink disables linking the clone back to the source
+	SkipBackLink bool
+}
+
+// CloneWithContext creates a copy of an issue, optionally in another project or with another issue type.
+// Only the fields which are available on the create screen of the target (according to createmeta) are copied;
+// components and versions are matched by name when the clone is created in another project.
+// Sub-tasks, links, remote links, attachments (streamed from the source to the clone) and, if requested, comments are copied as well.
+//
+// If copying any part fails after the clone was created, the clone is returned together with the error.
+func (s *IssueService) CloneWithContext(ctx context.Context, issueKey string, options *CloneOptions) (*Issue, error) {
+	if options == nil {
+		options = &CloneOptions{}
+	}
+
+	source, _, err := s.GetWithContext(ctx, issueKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	if source.Fields == nil {
+		return nil, fmt.Errorf("issue %s has no fields", issueKey)
+	}
+
+	projectKey := options.ProjectKey
+	if projectKey == "" {
+		projectKey = source.Fields.Project.Key
+	}
+	issueType := options.IssueType
+	if issueType == "" {
+		issueType = source.Fields.Type.Name
+	}
+
+	meta, _, err := s.GetCreateMetaWithContext(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	project := meta.GetProjectWithKey(projectKey)
+	if project == nil {
+		return nil, fmt.Errorf("project %s is not available for creating issues", projectKey)
+	}
+
+	var parentKey string
+	if source.Fields.Type.Subtask && source.Fields.Parent != nil {
+		parentKey = source.Fields.Parent.Key
+	}
+	clone, err := s.cloneIssueWithContext(ctx, source, project, issueType, parentKey, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.SkipSubtasks {
+		for _, subtask := range source.Fields.Subtasks {
+			sourceSubtask, _, err := s.GetWithContext(ctx, subtask.Key, nil)
+			if err != nil {
+				return clone, err
+			}
+			if _, err := s.cloneIssueWithContext(ctx, sourceSubtask, project, sourceSubtask.Fields.Type.Name, clone.Key, options); err != nil {
+				return clone, err
+			}
+		}
+	}
+
+	if !options.SkipBackLink {
+		linkType := options.LinkType
+		if linkType == "" {
+			linkType = defaultCloneLinkType
+		}
+		resp, err := s.AddLinkWithContext(ctx, &IssueLink{
+			Type:         IssueLinkType{Name: linkType},
+			InwardIssue:  &Issue{Key: clone.Key},
+			OutwardIssue: &Issue{Key: source.Key},
+		})
+		if err != nil {
+			return clone, err
+		}
+		Cleanup(resp)
+	}
+
+	return clone, nil
+}
+
+// Clone wraps CloneWithContext using the background context.
+func (s *IssueService) Clone(issueKey string, options *CloneOptions) (*Issue, error) {
+	return s.CloneWithContext(context.Background(), issueKey, options)
+}
+
+// cloneIssueWithContext creates the copy of a single issue and copies its links, attachments and comments
+func (s *IssueService) cloneIssueWithContext(ctx context.Context, source *Issue, project *MetaProject, issueTypeName, parentKey string, options *CloneOptions) (*Issue, error) {
+	issueType := project.GetIssueTypeWithName(issueTypeName)
+	if issueType == nil {
+		return nil, fmt.Errorf("issue type %q is not available in project %s", issueTypeName, project.Key)
+	}
+
+	fields := cloneFields(source, project, issueType, options.SummaryPrefix)
+	if parentKey != "" {
+		fields["parent"] = map[string]string{"key": parentKey}
+	}
+
+	clone, _, err := s.CreateWithContext(ctx, &Issue{Fields: &IssueFields{Unknowns: fields}})
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.SkipLinks {
+		for _, link := range source.Fields.IssueLinks {
+			// Jira's create link API uses inwardIssue for the source side of the link,
+			// which is the issue that shows the other one as outwardIssue.
+			issueLink := &IssueLink{Type: IssueLinkType{Name: link.Type.Name}}
+			if link.OutwardIssue != nil {
+				issueLink.InwardIssue = &Issue{Key: clone.Key}
+				issueLink.OutwardIssue = &Issue{Key: link.OutwardIssue.Key}
+			} else if link.InwardIssue != nil {
+				issueLink.InwardIssue = &Issue{Key: link.InwardIssue.Key}
+				issueLink.OutwardIssue = &Issue{Key: clone.Key}
+			} else {
+				continue
+			}
+			resp, err := s.AddLinkWithContext(ctx, issueLink)
+			if err != nil {
+				return clone, err
+			}
+			Cleanup(resp)
+		}
+	}
+
+	if !options.SkipRemoteLinks {
+		remoteLinks, _, err := s.GetRemoteLinksWithContext(ctx, source.Key)
+		if err != nil {
+			return clone, err
+		}
+		for _, remoteLink := range *remoteLinks {
+			remoteLink.ID = 0
+			remoteLink.Self = ""
+			if _, _, err := s.AddRemoteLinkWithContext(ctx, clone.Key, &remoteLink); err != nil {
+				return clone, err
+			}
+		}
+	}
+
+	if !options.SkipAttachments {
+		for _, attachment := range source.Fields.Attachments {
+			if err := s.copyAttachmentWithContext(ctx, attachment, clone.Key); err != nil {
+				return clone, err
+			}
+		}
+	}
+
+	if options.Comments {
+		// the comments embedded in the issue may be a partial page
+		comments, err := s.GetAllCommentsWithContext(ctx, source.Key)
+		if err != nil {
+			return clone, err
+		}
+		for _, comment := range comments {
+			_, _, err := s.AddCommentWithContext(ctx, clone.Key, &Comment{Body: comment.Body, Visibility: comment.Visibility})
+			if err != nil {
+				return clone, err
+			}
+		}
+	}
+
+	return clone, nil
+}
+
+// copyAttachmentWithContext streams an attachment from its issue to another issue
+func (s *IssueService) copyAttachmentWithContext(ctx context.Context, attachment *Attachment, issueKey string) error {
+	resp, err := s.DownloadAttachmentWithContext(ctx, attachment.ID)
+	if err != nil {
+		return err
+	}
+	defer Cleanup(resp)
+
+	_, _, err = s.PostAttachmentWithContext(ctx, issueKey, resp.Body, attachment.Filename)
+	return err
+}
+
+// cloneFields returns the fields of the create request for a copy of source,
+// limited to the fields which are available for the target issue type.
+func cloneFields(source *Issue, project *MetaProject, issueType *MetaIssueType, summaryPrefix string) map[string]interface{} {
+	available := func(key string) bool {
+		_, ok := issueType.Fields[key]
+		return ok
+	}
+	sameProject := strings.EqualFold(source.Fields.Project.Key, project.Key)
+
+	fields := map[string]interface{}{
+		"project":   map[string]string{"key": project.Key},
+		"issuetype": map[string]string{"id": issueType.Id},
+		"summary":   summaryPrefix + source.Fields.Summary,
+	}
+	if source.Fields.Description != "" && available("description") {
+		fields["description"] = source.Fields.Description
+	}
+	if source.Fields.Environment != "" && available("environment") {
+		fields["environment"] = source.Fields.Environment
+	}
+	if source.Fields.Priority != nil && available("priority") {
+		fields["priority"] = map[string]string{"id": source.Fields.Priority.ID}
+	}
+	if len(source.Fields.Labels) > 0 && available("labels") {
+		fields["labels"] = source.Fields.Labels
+	}
+	if due := time.Time(source.Fields.Duedate); !due.IsZero() && available("duedate") {
+		fields["duedate"] = due.Format("2006-01-02")
+	}
+
+	if available("components") {
+		var names []string
+		for _, component := range source.Fields.Components {
+			names = append(names, component.Name)
+		}
+		if components := metaNamedValues(issueType, "components", names, sameProject); len(components) > 0 {
+			fields["components"] = components
+		}
+	}
+	if available("fixVersions") {
+		var names []string
+		for _, version := range source.Fields.FixVersions {
+			names = append(names, version.Name)
+		}
+		if versions := metaNamedValues(issueType, "fixVersions", names, sameProject); len(versions) > 0 {
+			fields["fixVersions"] = versions
+		}
+	}
+	if available("versions") {
+		var names []string
+		for _, version := range source.Fields.AffectsVersions {
+			names = append(names, version.Name)
+		}
+		if versions := metaNamedValues(issueType, "versions", names, sameProject); len(versions) > 0 {
+			fields["versions"] = versions
+		}
+	}
+
+	for key, value := range source.Fields.Unknowns {
+		if !strings.HasPrefix(key, "customfield_") || value == nil || !available(key) {
+			continue
+		}
+		if custom, err := issueType.Fields.String(key + "/schema/custom"); err == nil && cloneSkippedCustomFields[custom] {
+			continue
+		}
+		fields[key] = value
+	}
+
+	return fields
+}
+
+// metaNamedValues returns the values with the given names, as {"name": ...} objects for the create request.
+// Unless all names are known to be valid (same project), only names which are allowed by the createmeta are kept.
+func metaNamedValues(issueType *MetaIssueType, key string, names []string, allValid bool) []map[string]string {
+	allowed := make(map[string]bool)
+	if !allValid {
+		values, err := issueType.Fields.Array(key + "/allowedValues")
+		if err != nil {
+			return nil
+		}
+		for _, value := range values {
+			if object, ok := value.(map[string]interface{}); ok {
+				if name, ok := object["name"].(string); ok {
+					allowed[name] = true
+				}
+			}
+		}
+	}
+
+	var result []map[string]string
+	for _, name := range names {
+		if allValid || allowed[name] {
+			result = append(result, map[string]string{"name": name})
+		}
+	}
+	return result
+}
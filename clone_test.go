@@ -0,0 +1,165 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testCloneCreateMeta = `{"projects":[{"id":"10100","key":"OTHER","issuetypes":[
+	{"id":"1","name":"Task","fields":{
+		"summary":{"name":"Summary"},"description":{"name":"Description"},"labels":{"name":"Labels"},
+		"components":{"name":"Components","allowedValues":[{"id":"200","name":"Backend"}]},
+		"customfield_10001":{"name":"Team","schema":{"custom":"com.atlassian.jira.plugin.system.customfieldtypes:select"}},
+		"customfield_10002":{"name":"Sprint","schema":{"custom":"com.pyxis.greenhopper.jira:gh-sprint"}}}},
+	{"id":"5","name":"Sub-task","subtask":true,"fields":{"summary":{"name":"Summary"},"parent":{"name":"Parent"}}}]}]}`
+
+func TestIssueService_Clone(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var created []map[string]interface{}
+	var links []string
+	var attachments, comments, remoteLinks int
+
+	testMux.HandleFunc("/rest/api/2/issue/createmeta", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestParams(t, r, map[string]string{"projectKeys": "OTHER", "expand": "projects.issuetypes.fields"})
+		fmt.Fprint(w, testCloneCreateMeta)
+	})
+	testMux.HandleFunc("/rest/api/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		payload := struct {
+			Fields map[string]interface{} `json:"fields"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+		created = append(created, payload.Fields)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":"%d","key":"OTHER-%d"}`, 20000+len(created), len(created))
+	})
+	testMux.HandleFunc("/rest/api/2/issueLink", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		link := IssueLink{}
+		if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+			t.Fatal(err)
+		}
+		links = append(links, fmt.Sprintf("%s %s %s", link.InwardIssue.Key, link.Type.Name, link.OutwardIssue.Key))
+		w.WriteHeader(http.StatusCreated)
+	})
+	testMux.HandleFunc("/secure/attachment/10000/", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, "checklist content")
+	})
+	testMux.HandleFunc("/rest/api/2/issue/", func(w http.ResponseWriter, r *http.Request) {
+		switch path := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"); {
+		case path == "TST-1":
+			fmt.Fprint(w, `{"key":"TST-1","fields":{
+				"project":{"key":"TST"},"issuetype":{"name":"Task"},"summary":"Release checklist","description":"Steps",
+				"labels":["release"],"components":[{"name":"Backend"},{"name":"Frontend"}],
+				"customfield_10001":{"id":"300","value":"Core"},"customfield_10002":[{"id":1}],"customfield_10003":"not on screen",
+				"subtasks":[{"id":"10002","key":"TST-2"}],
+				"issuelinks":[{"id":"1","type":{"name":"Blocks"},"outwardIssue":{"key":"TST-9"}}],
+				"attachment":[{"id":"10000","filename":"checklist.txt"}],
+				"comment":{"total":2,"comments":[{"body":"Remember the changelog"}]}}}`)
+		case path == "TST-1/comment":
+			testMethod(t, r, "GET")
+			fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":2,"comments":[{"body":"Remember the changelog"},{"body":"And the release notes"}]}`)
+		case strings.HasSuffix(path, "/comment") && r.Method == "GET":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":0,"comments":[]}`)
+		case path == "TST-2":
+			fmt.Fprint(w, `{"key":"TST-2","fields":{"project":{"key":"TST"},"issuetype":{"name":"Sub-task","subtask":true},"summary":"Tag the release","parent":{"key":"TST-1"}}}`)
+		case strings.HasSuffix(path, "/remotelink") && r.Method == "GET":
+			if path == "TST-1/remotelink" {
+				fmt.Fprint(w, `[{"id":1,"self":"http://www.example.com/remotelink/1","object":{"url":"http://www.example.com/wiki","title":"Wiki"}}]`)
+				return
+			}
+			fmt.Fprint(w, `[]`)
+		case path == "OTHER-1/remotelink":
+			testMethod(t, r, "POST")
+			body, _ := ioutil.ReadAll(r.Body)
+			if strings.Contains(string(body), `"id"`) {
+				t.Errorf("Expected the remote link id to be cleared, got %s", body)
+			}
+			remoteLinks++
+			fmt.Fprint(w, `{"id":2}`)
+		case path == "OTHER-1/attachments":
+			testMethod(t, r, "POST")
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				t.Fatal(err)
+			}
+			content, _ := ioutil.ReadAll(file)
+			if header.Filename != "checklist.txt" || string(content) != "checklist content" {
+				t.Errorf("Unexpected attachment %s: %s", header.Filename, content)
+			}
+			attachments++
+			fmt.Fprint(w, `[{"id":"10001","filename":"checklist.txt"}]`)
+		case path == "OTHER-1/comment":
+			testMethod(t, r, "POST")
+			comments++
+			fmt.Fprint(w, `{"id":"1"}`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	clone, err := testClient.Issue.Clone("TST-1", &CloneOptions{ProjectKey: "OTHER", SummaryPrefix: "CLONE - ", Comments: true})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if clone.Key != "OTHER-1" {
+		t.Errorf("Expected clone OTHER-1, got %s", clone.Key)
+	}
+
+	if len(created) != 2 {
+		t.Fatalf("Expected 2 created issues, got %d", len(created))
+	}
+	fields := created[0]
+	if fields["summary"] != "CLONE - Release checklist" || fields["description"] != "Steps" {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+	if fmt.Sprint(fields["components"]) != "[map[name:Backend]]" {
+		t.Errorf("Expected only the Backend component, got %v", fields["components"])
+	}
+	if _, ok := fields["customfield_10001"]; !ok {
+		t.Error("Expected the Team field to be copied")
+	}
+	if _, ok := fields["customfield_10002"]; ok {
+		t.Error("Expected the Sprint field not to be copied")
+	}
+	if _, ok := fields["customfield_10003"]; ok {
+		t.Error("Expected fields which are not on the create screen not to be copied")
+	}
+	if parent, _ := created[1]["parent"].(map[string]interface{}); parent["key"] != "OTHER-1" {
+		t.Errorf("Expected the sub-task clone below OTHER-1, got %v", created[1])
+	}
+
+	expectedLinks := "[OTHER-1 Blocks TST-9 OTHER-1 Cloners TST-1]"
+	if fmt.Sprint(links) != expectedLinks {
+		t.Errorf("Expected links %s, got %v", expectedLinks, links)
+	}
+	if attachments != 1 || comments != 2 || remoteLinks != 1 {
+		t.Errorf("Expected 1 attachment, 2 comments and 1 remote link, got %d, %d and %d", attachments, comments, remoteLinks)
+	}
+}
+
+func TestIssueService_Clone_UnknownIssueType(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/issue/TST-1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"key":"TST-1","fields":{"project":{"key":"OTHER"},"issuetype":{"name":"Story"},"summary":"Story"}}`)
+	})
+	testMux.HandleFunc("/rest/api/2/issue/createmeta", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testCloneCreateMeta)
+	})
+
+	if _, err := testClient.Issue.Clone("TST-1", nil); err == nil {
+		t.Error("Expected an error for an issue type which is not available")
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+)
+
+func runBoard(e *env, args []string) error {
+	return e.subcommand("board", args, map[string]func(*env, []string) error{
+		"list": boardList,
+	})
+}
+
+func boardList(e *env, args []string) error {
+	flags := e.flagSet("board list", "[-type scrum|kanban] [-project KEY] [-name TEXT] [-json]")
+	boardType := flags.String("type", "", "only boards of the `type` scrum or kanban")
+	project := flags.String("project", "", "only boards of the project with the `key`")
+	name := flags.String("name", "", "only boards whose name contains the `text`")
+	asJSON := flags.Bool("json", false, "print the boards as JSON")
+	if err := parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	options := &jira.BoardListOptions{BoardType: *boardType, Name: *name, ProjectKeyOrID: *project}
+	boards := make([]jira.Board, 0)
+	for {
+		list, _, err := e.client.Board.GetAllBoardsWithContext(e.ctx, options)
+		if err != nil {
+			return err
+		}
+		boards = append(boards, list.Values...)
+		if list.IsLast || len(list.Values) == 0 {
+			break
+		}
+		options.StartAt += len(list.Values)
+	}
+	if *asJSON {
+		return printJSON(e.stdout, boards)
+	}
+
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tNAME")
+	for _, board := range boards {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", board.ID, board.Type, board.Name)
+	}
+	return w.Flush()
+}
+
+func runSprint(e *env, args []string) error {
+	return e.subcommand("sprint", args, map[string]func(*env, []string) error{
+		"list":   sprintList,
+		"issues": sprintIssues,
+	})
+}
+
+func sprintList(e *env, args []string) error {
+	flags := e.flagSet("sprint list", "[-state list] [-json] BOARD")
+	state := flags.String("state", "", "comma separated `states`: future, active and closed (default all)")
+	asJSON := flags.Bool("json", false, "print the sprints as JSON")
+	if err := parse(flags, args, 1, 1); err != nil {
+		return err
+	}
+	boardID, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid board id %q", flags.Arg(0))
+	}
+
+	options := &jira.GetAllSprintsOptions{State: *state}
+	sprints := make([]jira.Sprint, 0)
+	for {
+		list, _, err := e.client.Board.GetAllSprintsWithOptionsWithContext(e.ctx, boardID, options)
+		if err != nil {
+			return err
+		}
+		sprints = append(sprints, list.Values...)
+		if list.IsLast || len(list.Values) == 0 {
+			break
+		}
+		options.StartAt += len(list.Values)
+	}
+	if *asJSON {
+		return printJSON(e.stdout, sprints)
+	}
+
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATE\tSTART\tEND\tNAME")
+	for _, sprint := range sprints {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", sprint.ID, sprint.State, date(sprint.StartDate), date(sprint.EndDate), sprint.Name)
+	}
+	return w.Flush()
+}
+
+func sprintIssues(e *env, args []string) error {
+	flags := e.flagSet("sprint issues", "[-jql JQL] [-max N] [-json] SPRINT")
+	jql := flags.String("jql", "", "only issues matching the `query`")
+	max := flags.Int("max", 0, "maximum `number` of issues, 0 for all")
+	asJSON := flags.Bool("json", false, "print the issues as JSON")
+	if err := parse(flags, args, 1, 1); err != nil {
+		return err
+	}
+	sprintID, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid sprint id %q", flags.Arg(0))
+	}
+
+	options := &jira.AgileIssuesOptions{JQL: *jql}
+	if !*asJSON {
+		options.Fields = "issuetype,status,assignee,summary"
+	}
+	issues := make([]jira.Issue, 0)
+	err = e.client.Sprint.GetIssuesForSprintPagesWithContext(e.ctx, sprintID, options, func(issue jira.Issue) error {
+		issues = append(issues, issue)
+		if *max > 0 && len(issues) >= *max {
+			return errStop
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStop) {
+		return err
+	}
+	if *asJSON {
+		return printJSON(e.stdout, issues)
+	}
+	return printIssues(e.stdout, issues)
+}
+
+// date formats an optional date of a sprint
+func date(t *jira.Time) string {
+	if t == nil {
+		return ""
+	}
+	return time.Time(*t).Format("2006-01-02")
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	jira "github.com/perolo/jira-client"
+)
+
+func runAPI(e *env, args []string) error {
+	flags := e.flagSet("api", "[-X METHOD] [-d DATA] PATH (e.g. rest/api/2/project)")
+	method := flags.String("X", "GET", "the HTTP `method`")
+	data := flags.String("d", "", "the JSON request body, @file to read it from a file or - to read it from stdin")
+	if err := parse(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	var body interface{}
+	if *data != "" {
+		raw, err := e.data(*data)
+		if err != nil {
+			return err
+		}
+		body = json.RawMessage(raw)
+	}
+	req, err := e.client.NewRequestWithContext(e.ctx, strings.ToUpper(*method), flags.Arg(0), body)
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Do(req, nil)
+	if err != nil {
+		return jira.NewJiraError(resp, err)
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(e.stdout, resp.Body)
+	return err
+}
+
+// data returns the argument, the content of the file named by @file, or stdin if the argument is -
+func (e *env) data(arg string) ([]byte, error) {
+	switch {
+	case arg == "-":
+		return ioutil.ReadAll(e.stdin)
+	case strings.HasPrefix(arg, "@"):
+		return ioutil.ReadFile(arg[1:])
+	}
+	return []byte(arg), nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	jira "github.com/perolo/jira-client"
+)
+
+// Config is the configuration file of the CLI, a JSON object with named profiles:
+//
+//	{
+//	  "default": "cloud",
+//	  "profiles": {
+//	    "cloud":  {"url": "https://example.atlassian.net", "auth": "basic", "username": "me@example.com", "token": "$JIRA_API_TOKEN"},
+//	    "server": {"url": "https://jira.example.com", "auth": "pat", "token": "$JIRA_PAT"}
+//	  }
+//	}
+//
+// Values of the profiles may reference environment variables as $NAME or ${NAME}, to keep secrets out of the file.
+type Config struct {
+	Default  string              `json:"default"`
+	Profiles map[string]*Profile `json:"profiles"`
+}
+
+// Profile is a Jira instance with the credentials to access it
+type Profile struct {
+	URL string `json:"url"`
+	// Auth selects the transport of the library: basic, bearer, pat, cookie, jwt or none. Default: basic if a username is given, otherwise none.
+	Auth     string `json:"auth"`
+	Username string `json:"username"`
+	// Password is the password of basic and cookie authentication; Token is used instead if it is empty (API tokens of Jira Cloud)
+	Password string `json:"password"`
+	// Token is the token of bearer and personal access token authentication, or the API token of basic authentication
+	Token string `json:"token"`
+	// Secret and Issuer are the shared secret and the key of the app for JWT authentication
+	Secret string `json:"secret"`
+	Issuer string `json:"issuer"`
+}
+
+// defaultConfigFile returns $JIRA_CONFIG or jira-client/config.json in the user config directory
+func defaultConfigFile() (string, error) {
+	if file := os.Getenv("JIRA_CONFIG"); file != "" {
+		return file, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "jira-client", "config.json"), nil
+}
+
+// LoadConfig reads the configuration file, or the default file if file is empty
+func LoadConfig(file string) (*Config, error) {
+	if file == "" {
+		var err error
+		if file, err = defaultConfigFile(); err != nil {
+			return nil, err
+		}
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration: %w", err)
+	}
+	config := new(Config)
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid configuration %s: %w", file, err)
+	}
+	return config, nil
+}
+
+// Profile returns the profile with the given name, or if name is empty the profile named by $JIRA_PROFILE,
+// the default of the configuration, or the only profile
+func (c *Config) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv("JIRA_PROFILE")
+	}
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" && len(c.Profiles) == 1 {
+		for only := range c.Profiles {
+			name = only
+		}
+	}
+	profile, ok := c.Profiles[name]
+	if !ok || profile == nil {
+		return nil, fmt.Errorf("no profile %q in the configuration", name)
+	}
+
+	expanded := *profile
+	for _, value := range []*string{&expanded.URL, &expanded.Auth, &expanded.Username, &expanded.Password, &expanded.Token, &expanded.Secret, &expanded.Issuer} {
+		*value = os.ExpandEnv(*value)
+	}
+	if expanded.URL == "" {
+		return nil, fmt.Errorf("profile %q has no url", name)
+	}
+	return &expanded, nil
+}
+
+// NewClient returns a client for the instance of the profile, authenticated with the transport of the profile
+func (p *Profile) NewClient() (*jira.Client, error) {
+	auth := strings.ToLower(p.Auth)
+	if auth == "" && p.Username != "" {
+		auth = "basic"
+	}
+	password := p.Password
+	if password == "" {
+		password = p.Token
+	}
+
+	var httpClient *http.Client
+	switch auth {
+	case "", "none":
+	case "basic":
+		httpClient = (&jira.BasicAuthTransport{Username: p.Username, Password: password}).Client()
+	case "bearer":
+		httpClient = (&jira.BearerAuthTransport{Token: p.Token}).Client()
+	case "pat":
+		httpClient = (&jira.PATAuthTransport{Token: p.Token}).Client()
+	case "cookie":
+		authURL := strings.TrimSuffix(p.URL, "/") + "/rest/auth/1/session"
+		httpClient = (&jira.CookieAuthTransport{Username: p.Username, Password: password, AuthURL: authURL}).Client()
+	case "jwt":
+		httpClient = (&jira.JWTAuthTransport{Secret: []byte(p.Secret), Issuer: p.Issuer}).Client()
+	default:
+		return nil, fmt.Errorf("unknown authentication %q", p.Auth)
+	}
+	return jira.NewClient(httpClient, p.URL)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	jira "github.com/perolo/jira-client"
+)
+
+func runFilter(e *env, args []string) error {
+	return e.subcommand("filter", args, map[string]func(*env, []string) error{
+		"list":   filterList,
+		"get":    filterGet,
+		"create": filterCreate,
+		"update": filterUpdate,
+		"delete": filterDelete,
+	})
+}
+
+func filterList(e *env, args []string) error {
+	flags := e.flagSet("filter list", "[-json] (lists the favourite filters)")
+	asJSON := flags.Bool("json", false, "print the filters as JSON")
+	if err := parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	filters, _, err := e.client.Filter.GetFavouriteListWithContext(e.ctx)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(e.stdout, filters)
+	}
+
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tJQL")
+	for _, filter := range filters {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", filter.ID, filter.Name, filter.Jql)
+	}
+	return w.Flush()
+}
+
+func filterGet(e *env, args []string) error {
+	flags := e.flagSet("filter get", "ID")
+	if err := parse(flags, args, 1, 1); err != nil {
+		return err
+	}
+	id, err := filterID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	filter, _, err := e.client.Filter.GetWithContext(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	return printJSON(e.stdout, filter)
+}
+
+// newFilterOptions defines the flags of filter create and filter update, and returns the options set by them
+func newFilterOptions(flags *flag.FlagSet) func() *jira.FilterOptions {
+	name := flags.String("name", "", "the `name` of the filter")
+	description := flags.String("description", "", "the `description` of the filter")
+	jql := flags.String("jql", "", "the `query` of the filter")
+	favourite := flags.Bool("favourite", false, "mark the filter as favourite, -favourite=false to unmark it")
+	return func() *jira.FilterOptions {
+		options := &jira.FilterOptions{Name: *name, Description: *description, Jql: *jql}
+		flags.Visit(func(f *flag.Flag) {
+			if f.Name == "favourite" {
+				options.Favourite = favourite
+			}
+		})
+		return options
+	}
+}
+
+func filterCreate(e *env, args []string) error {
+	flags := e.flagSet("filter create", "-name NAME -jql JQL [-description TEXT] [-favourite]")
+	options := newFilterOptions(flags)
+	if err := parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	filter := options()
+	if filter.Name == "" || filter.Jql == "" {
+		flags.Usage()
+		return errUsage
+	}
+	created, _, err := e.client.Filter.CreateWithContext(e.ctx, filter)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(e.stdout, created.ID)
+	return nil
+}
+
+func filterUpdate(e *env, args []string) error {
+	flags := e.flagSet("filter update", "[-name NAME] [-jql JQL] [-description TEXT] [-favourite] ID")
+	options := newFilterOptions(flags)
+	if err := parse(flags, args, 1, 1); err != nil {
+		return err
+	}
+	id, err := filterID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if flags.NFlag() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+	_, _, err = e.client.Filter.UpdateWithContext(e.ctx, id, options())
+	return err
+}
+
+func filterDelete(e *env, args []string) error {
+	flags := e.flagSet("filter delete", "ID")
+	if err := parse(flags, args, 1, 1); err != nil {
+		return err
+	}
+	id, err := filterID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Filter.DeleteWithContext(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	jira.Cleanup(resp)
+	return nil
+}
+
+// filterID parses the id of a filter
+func filterID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid filter id %q", arg)
+	}
+	return id, nil
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+)
+
+func runIssue(e *env, args []string) error {
+	return e.subcommand("issue", args, map[string]func(*env, []string) error{
+		"get":        issueGet,
+		"create":     issueCreate,
+		"edit":       issueEdit,
+		"transition": issueTransition,
+		"comment":    issueComment,
+		"assign":     issueAssign,
+		"watch":      issueWatch,
+		"attach":     issueAttach,
+	})
+}
+
+func issueGet(e *env, args []string) error {
+	flags := e.flagSet("issue get", "[-json] [-fields list] KEY")
+	asJSON := flags.Bool("json", false, "print the issue as JSON")
+	fields := flags.String("fields", "", "comma separated `fields` to load (default all navigable fields)")
+	if err := parse(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	issue, _, err := e.client.Issue.GetWithContext(e.ctx, flags.Arg(0), &jira.GetQueryOptions{Fields: *fields})
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(e.stdout, issue)
+	}
+
+	f := issue.Fields
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Key:\t%s\n", issue.Key)
+	if f == nil {
+		return w.Flush()
+	}
+	fmt.Fprintf(w, "Summary:\t%s\n", f.Summary)
+	fmt.Fprintf(w, "Type:\t%s\n", f.Type.Name)
+	if f.Status != nil {
+		fmt.Fprintf(w, "Status:\t%s\n", f.Status.Name)
+	}
+	if f.Priority != nil {
+		fmt.Fprintf(w, "Priority:\t%s\n", f.Priority.Name)
+	}
+	fmt.Fprintf(w, "Assignee:\t%s\n", userName(f.Assignee))
+	fmt.Fprintf(w, "Reporter:\t%s\n", userName(f.Reporter))
+	if len(f.Labels) > 0 {
+		fmt.Fprintf(w, "Labels:\t%s\n", strings.Join(f.Labels, ", "))
+	}
+	if created := time.Time(f.Created); !created.IsZero() {
+		fmt.Fprintf(w, "Created:\t%s\n", created.Format("2006-01-02 15:04"))
+	}
+	if updated := time.Time(f.Updated); !updated.IsZero() {
+		fmt.Fprintf(w, "Updated:\t%s\n", updated.Format("2006-01-02 15:04"))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if f.Description != "" {
+		fmt.Fprintf(e.stdout, "\n%s\n", f.Description)
+	}
+	return nil
+}
+
+// issueFieldFlags are the flags of issue create and issue edit
+type issueFieldFlags struct {
+	summary     *string
+	description *string
+	assignee    *string
+	labels      *string
+	fields      stringList
+}
+
+func newIssueFieldFlags(flags *flag.FlagSet) *issueFieldFlags {
+	f := &issueFieldFlags{
+		summary:     flags.String("summary", "", "the `summary`"),
+		description: flags.String("description", "", "the `description`, - to read it from stdin"),
+		assignee:    flags.String("assignee", "", "the `user` name (Jira Server) or account id (Jira Cloud) of the assignee"),
+		labels:      flags.String("labels", "", "comma separated `labels`"),
+	}
+	flags.Var(&f.fields, "field", "set a field by id or name, as `name=value`; the value is parsed as JSON if possible (repeatable)")
+	return f
+}
+
+// values returns the fields set by the flags
+func (f *issueFieldFlags) values(e *env) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if *f.summary != "" {
+		values["summary"] = *f.summary
+	}
+	if *f.description != "" {
+		description, err := e.text(*f.description)
+		if err != nil {
+			return nil, err
+		}
+		values["description"] = description
+	}
+	if *f.assignee != "" {
+		values["assignee"] = userRef(*f.assignee)
+	}
+	if *f.labels != "" {
+		values["labels"] = splitList(*f.labels)
+	}
+	if len(f.fields) == 0 {
+		return values, nil
+	}
+
+	var fields []jira.Field
+	for _, field := range f.fields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid field %q, expected name=value", field)
+		}
+		id := name
+		if !isFieldID(name) {
+			if fields == nil {
+				var err error
+				if fields, _, err = e.client.Field.GetListWithContext(e.ctx); err != nil {
+					return nil, err
+				}
+			}
+			found := jira.FindFieldByName(fields, name)
+			if found == nil {
+				return nil, fmt.Errorf("unknown field %q", name)
+			}
+			id = found.ID
+		}
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+			parsed = value
+		}
+		values[id] = parsed
+	}
+	return values, nil
+}
+
+// isFieldID reports whether a field name is an id, like summary or customfield_10001, rather than a display name
+func isFieldID(name string) bool {
+	return strings.HasPrefix(name, "customfield_") || (strings.ToLower(name) == name && !strings.Contains(name, " "))
+}
+
+func issueCreate(e *env, args []string) error {
+	flags := e.flagSet("issue create", "-project KEY -summary TEXT [flags]")
+	fieldFlags := newIssueFieldFlags(flags)
+	project := flags.String("project", "", "the `key` of the project")
+	issueType := flags.String("type", "Task", "the `name` of the issue type")
+	parent := flags.String("parent", "", "the `key` of the parent of a sub-task")
+	if err := parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	if *project == "" || *fieldFlags.summary == "" {
+		flags.Usage()
+		return errUsage
+	}
+
+	values, err := fieldFlags.values(e)
+	if err != nil {
+		return err
+	}
+	values["project"] = map[string]string{"key": *project}
+	values["issuetype"] = map[string]string{"name": *issueType}
+	if *parent != "" {
+		values["parent"] = map[string]string{"key": *parent}
+	}
+	issue, resp, err := e.client.Issue.CreateWithContext(e.ctx, &jira.Issue{Fields: &jira.IssueFields{Unknowns: values}})
+	if err != nil {
+		return jira.NewJiraError(resp, err)
+	}
+	fmt.Fprintln(e.stdout, issue.Key)
+	return nil
+}
+
+func issueEdit(e *env, args []string) error {
+	flags := e.flagSet("issue edit", "[flags] KEY")
+	fieldFlags := newIssueFieldFlags(flags)
+	if err := parse(flags, args, 1, 1); err != nil {
+		return err
+	}
+	values, err := fieldFlags.values(e)
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		flags.Usage()
+		return errUsage
+	}
+	resp, err := e.client.Issue.UpdateIssueWithContext(e.ctx, flags.Arg(0), map[string]interface{}{"fields": values})
+	if err != nil {
+		return jira.NewJiraError(resp, err)
+	}
+	jira.Cleanup(resp)
+	return nil
+}
+
+func issueTransition(e *env, args []string) error {
+	flags := e.flagSet("issue transition", "KEY [TRANSITION or STATUS] (without a transition, the available transitions are listed)")
+	if err := parse(flags, args, 1, 2); err != nil {
+		return err
+	}
+	key := flags.Arg(0)
+
+	transitions, _, err := e.client.Issue.GetTransitionsWithContext(e.ctx, key)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() == 1 {
+		w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTRANSITION\tSTATUS")
+		for _, transition := range transitions {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", transition.ID, transition.Name, transition.To.Name)
+		}
+		return w.Flush()
+	}
+
+	name := flags.Arg(1)
+	for _, transition := range transitions {
+		if transition.ID == name || strings.EqualFold(transition.Name, name) || strings.EqualFold(transition.To.Name, name) {
+			resp, err := e.client.Issue.DoTransitionWithContext(e.ctx, key, transition.ID)
+			if err != nil {
+				return err
+			}
+			jira.Cleanup(resp)
+			return nil
+		}
+	}
+	return fmt.Errorf("no transition %q available for %s", name, key)
+}
+
+func issueComment(e *env, args []string) error {
+	flags := e.flagSet("issue comment", "KEY TEXT (- to read the text from stdin)")
+	if err := parse(flags, args, 2, 2); err != nil {
+		return err
+	}
+	body, err := e.text(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	comment, _, err := e.client.Issue.AddCommentWithContext(e.ctx, flags.Arg(0), &jira.Comment{Body: body})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(e.stdout, comment.ID)
+	return nil
+}
+
+func issueAssign(e *env, args []string) error {
+	flags := e.flagSet("issue assign", "KEY USER (user name on Jira Server, account id on Jira Cloud; - to unassign)")
+	if err := parse(flags, args, 2, 2); err != nil {
+		return err
+	}
+	user := &jira.User{}
+	if id := flags.Arg(1); id != "-" {
+		if isAccountID(id) {
+			user.AccountID = id
+		} else {
+			user.Name = id
+		}
+	}
+	resp, err := e.client.Issue.UpdateAssigneeWithContext(e.ctx, flags.Arg(0), user)
+	if err != nil {
+		return err
+	}
+	jira.Cleanup(resp)
+	return nil
+}
+
+func issueWatch(e *env, args []string) error {
+	flags := e.flagSet("issue watch", "[-remove] KEY [USER]")
+	remove := flags.Bool("remove", false, "stop watching the issue")
+	if err := parse(flags, args, 1, 2); err != nil {
+		return err
+	}
+	user := flags.Arg(1)
+	if user == "" {
+		self, _, err := e.client.User.GetSelfWithContext(e.ctx)
+		if err != nil {
+			return err
+		}
+		user = self.AccountID
+		if user == "" {
+			user = self.Name
+		}
+	}
+
+	watch := e.client.Issue.AddWatcherWithContext
+	if *remove {
+		watch = e.client.Issue.RemoveWatcherWithContext
+	}
+	resp, err := watch(e.ctx, flags.Arg(0), user)
+	if err != nil {
+		return err
+	}
+	jira.Cleanup(resp)
+	return nil
+}
+
+func issueAttach(e *env, args []string) error {
+	flags := e.flagSet("issue attach", "KEY FILE...")
+	if err := parse(flags, args, 2, -1); err != nil {
+		return err
+	}
+	for _, file := range flags.Args()[1:] {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		attachments, _, err := e.client.Issue.PostAttachmentWithContext(e.ctx, flags.Arg(0), f, filepath.Base(file))
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("attaching %s: %w", file, err)
+		}
+		for _, attachment := range *attachments {
+			fmt.Fprintf(e.stdout, "%s\t%s\n", attachment.ID, attachment.Filename)
+		}
+	}
+	return nil
+}
+
+// text returns the argument, or stdin if the argument is -
+func (e *env) text(arg string) (string, error) {
+	if arg != "-" {
+		return arg, nil
+	}
+	data, err := ioutil.ReadAll(e.stdin)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// isAccountID reports whether a user id is an account id of Jira Cloud, e.g. 5b10a2844c20165700ede21a
+// or 557058:f58131cb-b67d-43c7-b30d-6b58d40bd077, rather than a user name
+func isAccountID(id string) bool {
+	if strings.Contains(id, ":") {
+		return true
+	}
+	if len(id) != 24 {
+		return false
+	}
+	return strings.Trim(strings.ToLower(id), "0123456789abcdef") == ""
+}
+
+// userRef returns the reference to a user in a create or edit request
+func userRef(id string) map[string]string {
+	if isAccountID(id) {
+		return map[string]string{"accountId": id}
+	}
+	return map[string]string{"name": id}
+}
+
+// userName returns the display name of a user, or "Unassigned"
+func userName(user *jira.User) string {
+	if user == nil {
+		return "Unassigned"
+	}
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	if user.Name != "" {
+		return user.Name
+	}
+	return user.AccountID
+}
+
+// printJSON writes v as indented JSON
+func printJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,186 @@
+// Command jira is a command-line client for Jira built on the jira-client library.
+//
+// Usage:
+//
+//	jira [-config file] [-profile name] <command> [arguments]
+//
+// The commands are:
+//
+//	issue     get, create, edit, transition, comment, assign, watch and attach issues
+//	search    search issues with JQL, printed as a table, JSON or CSV
+//	board     list agile boards
+//	sprint    list the sprints of a board and the issues of a sprint
+//	filter    list, show, create, update and delete filters
+//	user      show the current user and find users
+//	group     list the members of a group
+//	api       send a request to any REST endpoint, like examples/do
+//
+// The Jira instance and the credentials are read from a profile of the configuration file,
+// see Config. Run "jira <command> -h" for the arguments of a command.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	jira "github.com/perolo/jira-client"
+)
+
+// command is a subcommand of the CLI
+type command struct {
+	summary string
+	run     func(e *env, args []string) error
+}
+
+var commands = map[string]command{
+	"issue":  {"get, create, edit, transition, comment, assign, watch and attach issues", runIssue},
+	"search": {"search issues with JQL", runSearch},
+	"board":  {"list agile boards", runBoard},
+	"sprint": {"list sprints and sprint issues", runSprint},
+	"filter": {"manage filters", runFilter},
+	"user":   {"show the current user and find users", runUser},
+	"group":  {"list the members of a group", runGroup},
+	"api":    {"send a raw request to the REST API", runAPI},
+}
+
+// env is the environment of a command
+type env struct {
+	ctx     context.Context
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+	profile *Profile
+	client  *jira.Client
+}
+
+// errUsage is returned after the usage of a command was printed
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	err := run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "jira:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the global flags, loads the profile and runs the command
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("jira", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configFile := flags.String("config", "", "configuration `file` (default $JIRA_CONFIG or jira-client/config.json in the user config directory)")
+	profileName := flags.String("profile", "", "`name` of the profile (default $JIRA_PROFILE or the default profile of the configuration)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: jira [-config file] [-profile name] <command> [arguments]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Commands:")
+		var names []string
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(stderr, "  %-8s %s\n", name, commands[name].summary)
+		}
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Flags:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "jira: unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return errUsage
+	}
+
+	config, err := LoadConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	profile, err := config.Profile(*profileName)
+	if err != nil {
+		return err
+	}
+	client, err := profile.NewClient()
+	if err != nil {
+		return err
+	}
+	e := &env{ctx: ctx, stdin: stdin, stdout: stdout, stderr: stderr, profile: profile, client: client}
+	return cmd.run(e, flags.Args()[1:])
+}
+
+// flagSet returns the flags of a command, printing errors and the usage to stderr
+func (e *env) flagSet(name, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(e.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: jira %s %s\n", name, usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parse parses the flags of a command and checks the number of its arguments
+func parse(flags *flag.FlagSet, args []string, min, max int) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() < min || (max >= 0 && flags.NArg() > max) {
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// subcommand runs the subcommand named by the first argument
+func (e *env) subcommand(name string, args []string, subcommands map[string]func(*env, []string) error) error {
+	var names []string
+	for subcommand := range subcommands {
+		names = append(names, subcommand)
+	}
+	sort.Strings(names)
+	if len(args) == 0 || subcommands[args[0]] == nil {
+		fmt.Fprintf(e.stderr, "Usage: jira %s <%s> [arguments]\n", name, strings.Join(names, "|"))
+		return errUsage
+	}
+	return subcommands[args[0]](e, args[1:])
+}
+
+// stringList is a flag which can be repeated
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// splitList splits a comma separated flag value
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testIssues = `{"startAt": 0, "maxResults": 50, "total": 2, "issues": [
+	{"id": "10001", "key": "TST-1", "fields": {"summary": "First", "issuetype": {"name": "Bug"}, "status": {"name": "Open"},
+		"assignee": {"name": "jdoe", "displayName": "Jane Doe"}}},
+	{"id": "10002", "key": "TST-2", "fields": {"summary": "Second", "issuetype": {"name": "Task"}, "status": {"name": "Done"}}}
+]}`
+
+// testRun runs the CLI against a test server with a profile for it, and returns stdout
+func testRun(t *testing.T, mux *http.ServeMux, args ...string) (string, error) {
+	t.Helper()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	config := fmt.Sprintf(`{"profiles": {"test": {"url": %q, "username": "admin", "token": "$TEST_JIRA_TOKEN"}}}`, server.URL)
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := ioutil.WriteFile(file, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JIRA_PROFILE", "")
+	t.Setenv("TEST_JIRA_TOKEN", "secret")
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), append([]string{"-config", file}, args...), strings.NewReader(""), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestConfig_Profile(t *testing.T) {
+	t.Setenv("JIRA_PROFILE", "")
+	t.Setenv("TEST_JIRA_URL", "https://jira.example.com")
+	config := &Config{
+		Default: "server",
+		Profiles: map[string]*Profile{
+			"server": {URL: "${TEST_JIRA_URL}", Auth: "pat", Token: "token"},
+			"cloud":  {URL: "https://example.atlassian.net", Username: "me@example.com", Token: "token"},
+		},
+	}
+
+	profile, err := config.Profile("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.URL != "https://jira.example.com" {
+		t.Errorf("Expected the default profile with the expanded url, got %s", profile.URL)
+	}
+	if config.Profiles["server"].URL != "${TEST_JIRA_URL}" {
+		t.Errorf("Expected the configuration to be unchanged, got %s", config.Profiles["server"].URL)
+	}
+
+	t.Setenv("JIRA_PROFILE", "cloud")
+	if profile, err = config.Profile(""); err != nil {
+		t.Fatal(err)
+	}
+	if profile.Username != "me@example.com" {
+		t.Errorf("Expected the profile of $JIRA_PROFILE, got %+v", profile)
+	}
+	if _, err = profile.NewClient(); err != nil {
+		t.Errorf("Expected a client, got %s", err)
+	}
+
+	if _, err := config.Profile("missing"); err == nil {
+		t.Error("Expected an error for a missing profile")
+	}
+	if _, err := (&Profile{URL: "https://jira.example.com", Auth: "kerberos"}).NewClient(); err == nil {
+		t.Error("Expected an error for an unknown authentication")
+	}
+}
+
+func TestRun_issueGet(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/issue/TST-1", func(w http.ResponseWriter, r *http.Request) {
+		if user, password, _ := r.BasicAuth(); user != "admin" || password != "secret" {
+			t.Errorf("Expected basic authentication with the token, got %s:%s", user, password)
+		}
+		fmt.Fprint(w, `{"id": "10001", "key": "TST-1", "fields": {"summary": "First", "issuetype": {"name": "Bug"}, "status": {"name": "Open"}, "description": "Details"}}`)
+	})
+
+	out, err := testRun(t, mux, "issue", "get", "TST-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"TST-1", "First", "Bug", "Open", "Unassigned", "Details"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output, got\n%s", expected, out)
+		}
+	}
+}
+
+func TestRun_search(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		if jql := r.URL.Query().Get("jql"); jql != "project = TST" {
+			t.Errorf("Expected jql project = TST, got %s", jql)
+		}
+		fmt.Fprint(w, testIssues)
+	})
+	mux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "summary", "name": "Summary", "schema": {"type": "string", "system": "summary"}}]`)
+	})
+
+	out, err := testRun(t, mux, "search", "project = TST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "KEY") || !strings.Contains(lines[1], "Jane Doe") || !strings.Contains(lines[2], "Unassigned") {
+		t.Errorf("Unexpected table\n%s", out)
+	}
+
+	out, err = testRun(t, mux, "search", "-format", "json", "-max", "1", "project = TST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var issues []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &issues); err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0]["key"] != "TST-1" {
+		t.Errorf("Expected only TST-1, got %v", issues)
+	}
+
+	out, err = testRun(t, mux, "search", "-format", "csv", "-columns", "key,summary", "project = TST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "key,Summary\nTST-1,First\nTST-2,Second\n"; out != expected {
+		t.Errorf("Expected csv\n%s\ngot\n%s", expected, out)
+	}
+
+	if _, err = testRun(t, mux, "search", "-format", "xml", "project = TST"); err == nil || !strings.Contains(err.Error(), "xml") {
+		t.Errorf("Expected an error for an unknown format, got %v", err)
+	}
+}
+
+func TestRun_api(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/filter", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "{\"name\":\"Mine\"}\n" {
+			t.Errorf("Unexpected body %s", body)
+		}
+		fmt.Fprint(w, `{"id": "10000"}`)
+	})
+	mux.HandleFunc("/rest/api/2/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errorMessages": ["Not found"]}`)
+	})
+
+	out, err := testRun(t, mux, "api", "-X", "post", "-d", `{"name": "Mine"}`, "rest/api/2/filter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != `{"id": "10000"}` {
+		t.Errorf("Expected the response body, got %s", out)
+	}
+
+	if _, err = testRun(t, mux, "api", "rest/api/2/missing"); err == nil || !strings.Contains(err.Error(), "Not found") {
+		t.Errorf("Expected the error of the response, got %v", err)
+	}
+}
+
+func TestRun_filterUpdate(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/2/filter/10010", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"id": "10010", "name": "Open bugs", "jql": "type = Bug"}`)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if expected := `{"name":"Open bugs","jql":"type = Bug","favourite":true}`; strings.TrimSpace(string(body)) != expected {
+			t.Errorf("Expected the name and query of the filter with the favourite flag, got %s", body)
+		}
+		fmt.Fprint(w, `{"id": "10010"}`)
+	})
+
+	if _, err := testRun(t, mux, "filter", "update", "-favourite", "10010"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun_unknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), []string{"unknown"}, strings.NewReader(""), &stdout, &stderr)
+	if !errors.Is(err, errUsage) {
+		t.Errorf("Expected a usage error, got %v", err)
+	}
+	if !strings.Contains(stderr.String(), "Commands:") {
+		t.Errorf("Expected the usage, got %s", stderr.String())
+	}
+}
+
+func TestIsAccountID(t *testing.T) {
+	for id, expected := range map[string]bool{
+		"5b10a2844c20165700ede21g":                    false,
+		"5b10a2844c20165700ede21a":                    true,
+		"557058:f58131cb-b67d-43c7-b30d-6b58d40bd077": true,
+		"jdoe": false,
+	} {
+		if got := isAccountID(id); got != expected {
+			t.Errorf("isAccountID(%q) = %t, expected %t", id, got, expected)
+		}
+	}
+}
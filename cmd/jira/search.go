@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	jira "github.com/perolo/jira-client"
+	"github.com/perolo/jira-client/export"
+)
+
+// errStop ends a paged search after the requested number of issues
+var errStop = errors.New("stop")
+
+func runSearch(e *env, args []string) error {
+	flags := e.flagSet("search", "[-format table|json|csv] [-max N] [-columns list] JQL")
+	format := flags.String("format", "table", "output `format`: table, json or csv")
+	max := flags.Int("max", 50, "maximum `number` of issues for table and json, 0 for all; csv exports all issues")
+	columns := flags.String("columns", "", "comma separated field names or ids of the csv `columns` (default key, summary, type, status, assignee, created, updated)")
+	if err := parse(flags, args, 1, 1); err != nil {
+		return err
+	}
+	jql := flags.Arg(0)
+
+	switch *format {
+	case "csv":
+		_, err := export.ExportWithContext(e.ctx, e.client, jql, e.stdout, export.CSV, &export.Options{Columns: splitList(*columns)})
+		return err
+	case "json":
+		issues, err := e.search(jql, *max, nil)
+		if err != nil {
+			return err
+		}
+		return printJSON(e.stdout, issues)
+	case "table":
+		issues, err := e.search(jql, *max, []string{"issuetype", "status", "assignee", "summary"})
+		if err != nil {
+			return err
+		}
+		return printIssues(e.stdout, issues)
+	}
+	return fmt.Errorf("unknown format %q, expected table, json or csv", *format)
+}
+
+// search returns the first max issues matching the query, or all if max is 0
+func (e *env) search(jql string, max int, fields []string) ([]jira.Issue, error) {
+	pageSize := 100
+	if max > 0 && max < pageSize {
+		pageSize = max
+	}
+	issues := make([]jira.Issue, 0)
+	err := e.client.Issue.SearchPagesWithContext(e.ctx, jql, &jira.SearchOptions{MaxResults: pageSize, Fields: fields}, func(issue jira.Issue) error {
+		issues = append(issues, issue)
+		if max > 0 && len(issues) >= max {
+			return errStop
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStop) {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// printIssues writes a table with the key, type, status, assignee and summary of the issues
+func printIssues(w io.Writer, issues []jira.Issue) error {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "KEY\tTYPE\tSTATUS\tASSIGNEE\tSUMMARY")
+	for _, issue := range issues {
+		f := issue.Fields
+		if f == nil {
+			fmt.Fprintf(table, "%s\t\t\t\t\n", issue.Key)
+			continue
+		}
+		status := ""
+		if f.Status != nil {
+			status = f.Status.Name
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", issue.Key, f.Type.Name, status, userName(f.Assignee), f.Summary)
+	}
+	return table.Flush()
+}
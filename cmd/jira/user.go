@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"text/tabwriter"
+
+	jira "github.com/perolo/jira-client"
+)
+
+func runUser(e *env, args []string) error {
+	return e.subcommand("user", args, map[string]func(*env, []string) error{
+		"me":   userMe,
+		"find": userFind,
+	})
+}
+
+func userMe(e *env, args []string) error {
+	flags := e.flagSet("user me", "")
+	if err := parse(flags, args, 0, 0); err != nil {
+		return err
+	}
+	user, _, err := e.client.User.GetSelfWithContext(e.ctx)
+	if err != nil {
+		return err
+	}
+	return printJSON(e.stdout, user)
+}
+
+func userFind(e *env, args []string) error {
+	flags := e.flagSet("user find", "[-username] [-json] QUERY")
+	username := flags.Bool("username", false, "search by user name, as required by Jira Server")
+	asJSON := flags.Bool("json", false, "print the users as JSON")
+	if err := parse(flags, args, 1, 1); err != nil {
+		return err
+	}
+	query := url.QueryEscape(flags.Arg(0))
+	var tweaks []jira.UserSearchF
+	if *username {
+		tweaks = append(tweaks, jira.WithUsername(query))
+	}
+	users, _, err := e.client.User.FindWithContext(e.ctx, query, tweaks...)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(e.stdout, users)
+	}
+
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tEMAIL")
+	for _, user := range users {
+		id := user.AccountID
+		if id == "" {
+			id = user.Name
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", id, user.DisplayName, user.EmailAddress)
+	}
+	return w.Flush()
+}
+
+func runGroup(e *env, args []string) error {
+	return e.subcommand("group", args, map[string]func(*env, []string) error{
+		"members": groupMembers,
+	})
+}
+
+func groupMembers(e *env, args []string) error {
+	flags := e.flagSet("group members", "[-inactive] [-json] GROUP")
+	inactive := flags.Bool("inactive", false, "include inactive users")
+	asJSON := flags.Bool("json", false, "print the members as JSON")
+	if err := parse(flags, args, 1, 1); err != nil {
+		return err
+	}
+
+	options := &jira.GroupSearchOptions{MaxResults: 50, IncludeInactiveUsers: *inactive}
+	members := make([]jira.GroupMember, 0)
+	for {
+		page, _, err := e.client.Group.GetWithOptionsWithContext(e.ctx, flags.Arg(0), options)
+		if err != nil {
+			return err
+		}
+		members = append(members, page...)
+		if len(page) < options.MaxResults {
+			break
+		}
+		options.StartAt += len(page)
+	}
+	if *asJSON {
+		return printJSON(e.stdout, members)
+	}
+
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tEMAIL\tACTIVE")
+	for _, member := range members {
+		id := member.AccountID
+		if id == "" {
+			id = member.Name
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", id, member.DisplayName, member.EmailAddress, member.Active)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,192 @@
+package jira
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CumulativeFlowOptions specifies the parameters for BoardService.GetCumulativeFlow
+type CumulativeFlowOptions struct {
+	// From is the first day of the report. Only the date part is used.
+	From time.Time
+	// To is the last day of the report. Only the date part is used.
+	To time.Time
+	// JQL is an optional clause that is added (with AND) to the board query,
+	// e.g. to exclude issues that have been resolved long before the reporting period.
+	JQL string
+}
+
+// CumulativeFlow represents the daily number of issues per board column for a date range.
+type CumulativeFlow struct {
+	BoardID    int                    `json:"boardId"`
+	Columns    []CumulativeFlowColumn `json:"columns"`
+	Days       []CumulativeFlowDay    `json:"days"`
+	Violations []WIPViolation         `json:"violations,omitempty"`
+}
+
+// CumulativeFlowColumn is a board column together with its WIP limits.
+// A limit of 0 means that no limit is configured.
+type CumulativeFlowColumn struct {
+	Name string `json:"name"`
+	Min  int    `json:"min,omitempty"`
+	Max  int    `json:"max,omitempty"`
+}
+
+// CumulativeFlowDay holds the number of issues per column at the end of one day.
+// Counts has the same order as CumulativeFlow.Columns.
+type CumulativeFlowDay struct {
+	Date   time.Time `json:"date"`
+	Counts []int     `json:"counts"`
+}
+
+// WIPViolation is a day on which a column was below its minimum or above its maximum WIP limit.
+type WIPViolation struct {
+	Date   time.Time `json:"date"`
+	Column string    `json:"column"`
+	Count  int       `json:"count"`
+	Min    int       `json:"min,omitempty"`
+	Max    int       `json:"max,omitempty"`
+}
+
+// GetCumulativeFlowWithContext reconstructs the cumulative flow of a board for the given date range.
+// The issues of the board are loaded (with their changelog) through the board filter,
+// and the status of every issue is replayed day by day and mapped to the board columns.
+// Columns that are outside their WIP limits at the end of a day are reported as violations.
+func (s *BoardService) GetCumulativeFlowWithContext(ctx context.Context, boardID int, opt *CumulativeFlowOptions) (*CumulativeFlow, error) {
+	if opt == nil || opt.From.IsZero() || opt.To.IsZero() {
+		return nil, fmt.Errorf("a date range is required for the cumulative flow of board %d", boardID)
+	}
+
+	config, _, err := s.GetBoardConfigurationWithContext(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	jql := fmt.Sprintf("filter = %s", config.Filter.ID)
+	if config.SubQuery.Query != "" {
+		jql += fmt.Sprintf(" AND (%s)", config.SubQuery.Query)
+	}
+	if opt.JQL != "" {
+		jql += fmt.Sprintf(" AND (%s)", opt.JQL)
+	}
+
+	var issues []Issue
+	options := &SearchOptions{
+		MaxResults: 100,
+		Expand:     "changelog",
+		Fields:     []string{"status", "created", "issuetype"},
+	}
+	err = s.client.Issue.SearchPagesWithContext(ctx, jql, options, func(issue Issue) error {
+		issues = append(issues, issue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	flow := NewCumulativeFlow(config, issues, opt.From, opt.To)
+	flow.BoardID = boardID
+	return flow, nil
+}
+
+// GetCumulativeFlow wraps GetCumulativeFlowWithContext using the background context.
+func (s *BoardService) GetCumulativeFlow(boardID int, opt *CumulativeFlowOptions) (*CumulativeFlow, error) {
+	return s.GetCumulativeFlowWithContext(context.Background(), boardID, opt)
+}
+
+// NewCumulativeFlow calculates the cumulative flow from a board configuration and issues including their changelog.
+// The issues are counted at the end of every day between from and to (both inclusive), in the location of from.
+// Issues in statuses that are not mapped to a column are ignored.
+func NewCumulativeFlow(config *BoardConfiguration, issues []Issue, from, to time.Time) *CumulativeFlow {
+	flow := &CumulativeFlow{BoardID: config.ID}
+
+	columnOfStatus := make(map[string]int)
+	for i, column := range config.ColumnConfig.Columns {
+		flow.Columns = append(flow.Columns, CumulativeFlowColumn{Name: column.Name, Min: column.Min, Max: column.Max})
+		for _, status := range column.Status {
+			columnOfStatus[status.ID] = i
+		}
+	}
+
+	changes := make([][]fieldChange, len(issues))
+	for i := range issues {
+		changes[i] = fieldChanges(&issues[i], "status")
+	}
+
+	checkWIP := config.ColumnConfig.ConstraintType != "" && config.ColumnConfig.ConstraintType != "none"
+	excludeSubtasks := config.ColumnConfig.ConstraintType == "issueCountExclSubs"
+
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	last := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, from.Location())
+	for !day.After(last) {
+		endOfDay := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		counts := make([]int, len(flow.Columns))
+		wip := make([]int, len(flow.Columns))
+		for i := range issues {
+			column, ok := columnOfStatus[statusAt(&issues[i], changes[i], endOfDay)]
+			if !ok {
+				continue
+			}
+			counts[column]++
+			if !excludeSubtasks || !issues[i].Fields.Type.Subtask {
+				wip[column]++
+			}
+		}
+		flow.Days = append(flow.Days, CumulativeFlowDay{Date: day, Counts: counts})
+
+		if checkWIP {
+			for i, column := range flow.Columns {
+				if (column.Max > 0 && wip[i] > column.Max) || (column.Min > 0 && wip[i] < column.Min) {
+					flow.Violations = append(flow.Violations, WIPViolation{
+						Date:   day,
+						Column: column.Name,
+						Count:  wip[i],
+						Min:    column.Min,
+						Max:    column.Max,
+					})
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return flow
+}
+
+// WriteCSV writes the cumulative flow as CSV, with one row per day and one column per board column.
+func (f *CumulativeFlow) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"date"}
+	for _, column := range f.Columns {
+		header = append(header, column.Name)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, day := range f.Days {
+		record := []string{day.Date.Format("2006-01-02")}
+		for _, count := range day.Counts {
+			record = append(record, strconv.Itoa(count))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the cumulative flow, including the WIP violations, as JSON.
+func (f *CumulativeFlow) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(f)
+}
@@ -0,0 +1,162 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testCumulativeFlowIssues = `{"startAt":0,"maxResults":100,"total":2,"issues":[
+{"key":"TST-1","fields":{"created":"2023-01-01T08:00:00.000+0000","status":{"id":"10601","name":"Done"},"issuetype":{"name":"Story"}},
+ "changelog":{"histories":[
+  {"id":"1","created":"2023-01-02T10:00:00.000+0000","items":[{"field":"status","fieldtype":"jira","from":"10005","fromString":"Backlog","to":"10602","toString":"In Progress"}]},
+  {"id":"2","created":"2023-01-03T12:00:00.000+0000","items":[{"field":"status","fieldtype":"jira","from":"10602","fromString":"In Progress","to":"10601","toString":"Done"}]}
+ ]}},
+{"key":"TST-2","fields":{"created":"2023-01-02T09:00:00.000+0000","status":{"id":"10603","name":"Selected"},"issuetype":{"name":"Story"}},
+ "changelog":{"histories":[]}}
+]}`
+
+func testCumulativeFlowConfiguration(t *testing.T) *BoardConfiguration {
+	raw, err := ioutil.ReadFile("./mocks/board_configuration.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	config := new(BoardConfiguration)
+	if err := json.Unmarshal(raw, config); err != nil {
+		t.Fatal(err.Error())
+	}
+	return config
+}
+
+func TestNewCumulativeFlow(t *testing.T) {
+	config := testCumulativeFlowConfiguration(t)
+
+	result := new(SearchResult)
+	if err := json.Unmarshal([]byte(testCumulativeFlowIssues), result); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)
+	flow := NewCumulativeFlow(config, result.Issues, from, to)
+
+	if len(flow.Columns) != 6 {
+		t.Fatalf("Expected 6 columns, got %d", len(flow.Columns))
+	}
+	expected := [][]int{
+		{1, 0, 0, 0, 0, 0},
+		{0, 1, 1, 0, 0, 0},
+		{0, 1, 0, 0, 0, 1},
+	}
+	if len(flow.Days) != len(expected) {
+		t.Fatalf("Expected %d days, got %d", len(expected), len(flow.Days))
+	}
+	for i, day := range flow.Days {
+		if !reflect.DeepEqual(day.Counts, expected[i]) {
+			t.Errorf("Day %s: expected counts %v, got %v", day.Date.Format("2006-01-02"), expected[i], day.Counts)
+		}
+	}
+
+	// The backlog column has a minimum of 5 issues, which is never reached
+	if len(flow.Violations) != 3 {
+		t.Fatalf("Expected 3 WIP violations, got %d", len(flow.Violations))
+	}
+	for _, violation := range flow.Violations {
+		if violation.Column != "Backlog" || violation.Min != 5 {
+			t.Errorf("Unexpected violation %+v", violation)
+		}
+	}
+}
+
+func TestNewCumulativeFlow_NoConstraint(t *testing.T) {
+	config := testCumulativeFlowConfiguration(t)
+	config.ColumnConfig.ConstraintType = "none"
+
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	flow := NewCumulativeFlow(config, nil, from, from)
+	if len(flow.Violations) != 0 {
+		t.Errorf("Expected no WIP violations without constraint, got %d", len(flow.Violations))
+	}
+}
+
+func TestCumulativeFlow_WriteCSV(t *testing.T) {
+	flow := &CumulativeFlow{
+		Columns: []CumulativeFlowColumn{{Name: "To Do"}, {Name: "Done"}},
+		Days: []CumulativeFlowDay{
+			{Date: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), Counts: []int{2, 0}},
+			{Date: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), Counts: []int{1, 1}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := flow.WriteCSV(&buf); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	expected := "date,To Do,Done\n2023-01-01,2,0\n2023-01-02,1,1\n"
+	if buf.String() != expected {
+		t.Errorf("Expected CSV %q, got %q", expected, buf.String())
+	}
+}
+
+func TestBoardService_GetCumulativeFlow(t *testing.T) {
+	setup()
+	defer teardown()
+
+	raw, err := ioutil.ReadFile("./mocks/board_configuration.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc("/rest/agile/1.0/board/35/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, string(raw))
+	})
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		jql := r.URL.Query().Get("jql")
+		if !strings.HasPrefix(jql, "filter = 12116 AND (fixVersion in unreleasedVersions()") {
+			t.Errorf("Unexpected JQL %q", jql)
+		}
+		if got := r.URL.Query().Get("expand"); got != "changelog" {
+			t.Errorf("Expected changelog to be expanded, got %q", got)
+		}
+		fmt.Fprint(w, testCumulativeFlowIssues)
+	})
+
+	flow, err := testClient.Board.GetCumulativeFlow(35, &CumulativeFlowOptions{
+		From: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if flow.BoardID != 35 {
+		t.Errorf("Expected board 35, got %d", flow.BoardID)
+	}
+	if len(flow.Days) != 3 {
+		t.Errorf("Expected 3 days, got %d", len(flow.Days))
+	}
+
+	var buf bytes.Buffer
+	if err := flow.WriteJSON(&buf); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if !strings.Contains(buf.String(), `"column": "Backlog"`) {
+		t.Errorf("Expected WIP violations in JSON output, got %s", buf.String())
+	}
+}
+
+func TestBoardService_GetCumulativeFlow_NoRange(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := testClient.Board.GetCumulativeFlow(35, nil)
+	if err == nil {
+		t.Error("Expected an error without a date range")
+	}
+}
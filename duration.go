@@ -0,0 +1,292 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Duration is a Jira duration like "1w 2d 3h 30m", stored in seconds.
+// Weeks and days depend on the working hours of the instance, see TimeTrackingConfiguration.
+// In JSON a duration is the number of seconds, like the seconds fields of TimeTracking and WorklogRecord;
+// as text it is the duration string.
+type Duration int
+
+// TimeTrackingConfiguration holds the time tracking settings of the Jira instance which define the length of a day and a week
+type TimeTrackingConfiguration struct {
+	WorkingHoursPerDay float64 `json:"workingHoursPerDay" structs:"workingHoursPerDay"`
+	WorkingDaysPerWeek float64 `json:"workingDaysPerWeek" structs:"workingDaysPerWeek"`
+	// TimeFormat is one of "pretty", "days" or "hours"
+	TimeFormat string `json:"timeFormat" structs:"timeFormat"`
+	// DefaultUnit is the unit of a duration without unit: "minute", "hour", "day" or "week"
+	DefaultUnit string `json:"defaultUnit" structs:"defaultUnit"`
+}
+
+// DefaultTimeTrackingConfiguration is the default time tracking configuration of Jira: 8 hours per day, 5 days per week
+var DefaultTimeTrackingConfiguration = TimeTrackingConfiguration{
+	WorkingHoursPerDay: 8,
+	WorkingDaysPerWeek: 5,
+	TimeFormat:         "pretty",
+	DefaultUnit:        "minute",
+}
+
+// durationPattern matches one component of a duration, e.g. "3h" or "1.5d"
+var durationPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([wdhm]?)`)
+
+// GetTimeTrackingConfigurationWithContext returns the time tracking settings of the Jira instance.
+// Jira Server and Data Center do not have the time tracking options resource of Jira Cloud;
+// their settings are read from the global configuration instead.
+//
+// Jira API docs: https://developer.atlassian.com/cloud/jira/platform/rest/v2/api-group-time-tracking/#api-rest-api-2-configuration-timetracking-options-get
+// and https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/configuration-getConfiguration
+func (s *IssueService) GetTimeTrackingConfigurationWithContext(ctx context.Context) (*TimeTrackingConfiguration, *Response, error) {
+	apiEndpoint := "rest/api/2/configuration/timetracking/options"
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configuration := new(TimeTrackingConfiguration)
+	resp, err := s.client.Do(req, configuration)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusNotFound {
+		Cleanup(resp)
+		return s.getServerTimeTrackingConfigurationWithContext(ctx)
+	}
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+	return configuration, resp, nil
+}
+
+// getServerTimeTrackingConfigurationWithContext reads the time tracking settings from the global configuration
+func (s *IssueService) getServerTimeTrackingConfigurationWithContext(ctx context.Context) (*TimeTrackingConfiguration, *Response, error) {
+	apiEndpoint := "rest/api/2/configuration"
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := new(struct {
+		TimeTrackingEnabled       bool                       `json:"timeTrackingEnabled"`
+		TimeTrackingConfiguration *TimeTrackingConfiguration `json:"timeTrackingConfiguration"`
+	})
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+	if result.TimeTrackingConfiguration == nil {
+		return nil, resp, fmt.Errorf("time tracking is not enabled")
+	}
+	return result.TimeTrackingConfiguration, resp, nil
+}
+
+// GetTimeTrackingConfiguration wraps GetTimeTrackingConfigurationWithContext using the background context.
+func (s *IssueService) GetTimeTrackingConfiguration() (*TimeTrackingConfiguration, *Response, error) {
+	return s.GetTimeTrackingConfigurationWithContext(context.Background())
+}
+
+// unitSeconds returns the number of seconds of a unit (w, d, h or m).
+// Settings which are not set fall back to DefaultTimeTrackingConfiguration.
+func (c *TimeTrackingConfiguration) unitSeconds(unit string) float64 {
+	hoursPerDay, daysPerWeek := DefaultTimeTrackingConfiguration.WorkingHoursPerDay, DefaultTimeTrackingConfiguration.WorkingDaysPerWeek
+	if c != nil && c.WorkingHoursPerDay > 0 {
+		hoursPerDay = c.WorkingHoursPerDay
+	}
+	if c != nil && c.WorkingDaysPerWeek > 0 {
+		daysPerWeek = c.WorkingDaysPerWeek
+	}
+	switch unit {
+	case "w":
+		return daysPerWeek * hoursPerDay * 3600
+	case "d":
+		return hoursPerDay * 3600
+	case "h":
+		return 3600
+	}
+	return 60
+}
+
+// defaultUnit returns the short unit used for a number without unit
+func (c *TimeTrackingConfiguration) defaultUnit() string {
+	if c == nil || c.DefaultUnit == "" {
+		return "m"
+	}
+	return c.DefaultUnit[:1]
+}
+
+// ParseDuration parses a Jira duration like "1w 2d 3h 30m", "1h30m" or "1.5d".
+// A number without unit is in the default unit of the configuration.
+// A nil configuration uses DefaultTimeTrackingConfiguration.
+func ParseDuration(s string, configuration *TimeTrackingConfiguration) (Duration, error) {
+	rest := strings.TrimSpace(s)
+	if rest == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	seconds := 0.0
+	for rest != "" {
+		match := durationPattern.FindStringSubmatch(strings.ToLower(rest))
+		if match == nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		value, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		unit := match[2]
+		if unit == "" {
+			unit = configuration.defaultUnit()
+		}
+		seconds += value * configuration.unitSeconds(unit)
+		rest = strings.TrimSpace(rest[len(match[0]):])
+	}
+	return Duration(math.Round(seconds)), nil
+}
+
+// Format returns the duration in the pretty Jira format, e.g. "1w 2d 3h 30m".
+// Seconds below a full minute are dropped; a duration below one minute is "0m".
+// A nil configuration uses DefaultTimeTrackingConfiguration.
+func (d Duration) Format(configuration *TimeTrackingConfiguration) string {
+	remaining := float64(d)
+	var parts []string
+	for _, unit := range []string{"w", "d", "h", "m"} {
+		size := configuration.unitSeconds(unit)
+		if count := math.Floor(remaining / size); count > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", int(count), unit))
+			remaining -= count * size
+		}
+	}
+	if len(parts) == 0 {
+		return "0m"
+	}
+	return strings.Join(parts, " ")
+}
+
+// String returns the duration formatted with DefaultTimeTrackingConfiguration
+func (d Duration) String() string {
+	return d.Format(nil)
+}
+
+// Seconds returns the duration in seconds
+func (d Duration) Seconds() int {
+	return int(d)
+}
+
+// MarshalText formats the duration with DefaultTimeTrackingConfiguration
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses the duration with DefaultTimeTrackingConfiguration
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := ParseDuration(string(text), nil)
+	if err != nil {
+		return err
+	}
+	*d = duration
+	return nil
+}
+
+// MarshalJSON writes the duration as number of seconds
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(d))), nil
+}
+
+// UnmarshalJSON reads a number of seconds, or a duration string parsed with DefaultTimeTrackingConfiguration
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return d.UnmarshalText([]byte(s))
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	seconds, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid duration %s: %w", data, err)
+	}
+	*d = Duration(math.Round(seconds))
+	return nil
+}
+
+// durationOf returns the seconds if they are set, otherwise the parsed duration string
+func durationOf(seconds Duration, s string, configuration *TimeTrackingConfiguration) (Duration, error) {
+	if seconds != 0 || s == "" {
+		return seconds, nil
+	}
+	return ParseDuration(s, configuration)
+}
+
+// OriginalEstimateDuration returns the original estimate, from the seconds or else the duration string
+func (t *TimeTracking) OriginalEstimateDuration(configuration *TimeTrackingConfiguration) (Duration, error) {
+	return durationOf(t.OriginalEstimateSeconds, t.OriginalEstimate, configuration)
+}
+
+// RemainingEstimateDuration returns the remaining estimate, from the seconds or else the duration string
+func (t *TimeTracking) RemainingEstimateDuration(configuration *TimeTrackingConfiguration) (Duration, error) {
+	return durationOf(t.RemainingEstimateSeconds, t.RemainingEstimate, configuration)
+}
+
+// TimeSpentDuration returns the time spent, from the seconds or else the duration string
+func (t *TimeTracking) TimeSpentDuration(configuration *TimeTrackingConfiguration) (Duration, error) {
+	return durationOf(t.TimeSpentSeconds, t.TimeSpent, configuration)
+}
+
+// SetOriginalEstimate sets the original estimate string and seconds
+func (t *TimeTracking) SetOriginalEstimate(d Duration, configuration *TimeTrackingConfiguration) {
+	t.OriginalEstimate, t.OriginalEstimateSeconds = d.Format(configuration), d
+}
+
+// SetRemainingEstimate sets the remaining estimate string and seconds
+func (t *TimeTracking) SetRemainingEstimate(d Duration, configuration *TimeTrackingConfiguration) {
+	t.RemainingEstimate, t.RemainingEstimateSeconds = d.Format(configuration), d
+}
+
+// TimeSpentDuration returns the time spent of the worklog, from the seconds or else the duration string
+func (w *WorklogRecord) TimeSpentDuration(configuration *TimeTrackingConfiguration) (Duration, error) {
+	return durationOf(w.TimeSpentSeconds, w.TimeSpent, configuration)
+}
+
+// SetTimeSpent sets the time spent string and seconds of the worklog
+func (w *WorklogRecord) SetTimeSpent(d Duration, configuration *TimeTrackingConfiguration) {
+	w.TimeSpent, w.TimeSpentSeconds = d.Format(configuration), d
+}
+
+// SetEstimatesWithContext sets the original and remaining estimate of an issue.
+// A nil duration leaves the estimate unchanged. The durations are formatted with the given configuration,
+// which should be the configuration of the instance (see GetTimeTrackingConfiguration).
+// Caller must close resp.Body
+func (s *IssueService) SetEstimatesWithContext(ctx context.Context, issueID string, original, remaining *Duration, configuration *TimeTrackingConfiguration) (*Response, error) {
+	edit := map[string]string{}
+	if original != nil {
+		edit["originalEstimate"] = original.Format(configuration)
+	}
+	if remaining != nil {
+		edit["remainingEstimate"] = remaining.Format(configuration)
+	}
+	data := map[string]interface{}{
+		"update": map[string]interface{}{
+			"timetracking": []interface{}{map[string]interface{}{"edit": edit}},
+		},
+	}
+
+	resp, err := s.UpdateIssueWithContext(ctx, issueID, data)
+	if err != nil {
+		return resp, NewJiraError(resp, err)
+	}
+	return resp, nil
+}
+
+// SetEstimates wraps SetEstimatesWithContext using the background context.
+// Caller must close resp.Body
+func (s *IssueService) SetEstimates(issueID string, original, remaining *Duration, configuration *TimeTrackingConfiguration) (*Response, error) {
+	return s.SetEstimatesWithContext(context.Background(), issueID, original, remaining, configuration)
+}
@@ -0,0 +1,155 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestParseDuration(t *testing.T) {
+	sixHourDays := &TimeTrackingConfiguration{WorkingHoursPerDay: 6, WorkingDaysPerWeek: 4, DefaultUnit: "hour"}
+
+	tt := []struct {
+		input         string
+		configuration *TimeTrackingConfiguration
+		seconds       int
+	}{
+		{"1w 2d 3h 30m", nil, (5*8+2*8+3)*3600 + 30*60},
+		{"1h30m", nil, 5400},
+		{"1.5d", nil, 12 * 3600},
+		{"45", nil, 45 * 60},
+		{"1W 1D", nil, 6 * 8 * 3600},
+		{"1w 1d", sixHourDays, 5 * 6 * 3600},
+		{"2", sixHourDays, 2 * 3600},
+	}
+	for _, tc := range tt {
+		d, err := ParseDuration(tc.input, tc.configuration)
+		if err != nil {
+			t.Errorf("%q: Error given: %s", tc.input, err)
+			continue
+		}
+		if d.Seconds() != tc.seconds {
+			t.Errorf("%q: Expected %d seconds, got %d", tc.input, tc.seconds, d.Seconds())
+		}
+	}
+
+	for _, input := range []string{"", "h", "1x", "1h foo"} {
+		if _, err := ParseDuration(input, nil); err == nil {
+			t.Errorf("%q: Expected an error", input)
+		}
+	}
+}
+
+func TestDuration_Format(t *testing.T) {
+	d := Duration((5*8+2*8+3)*3600 + 30*60 + 15)
+	if d.String() != "1w 2d 3h 30m" {
+		t.Errorf("Unexpected format %s", d)
+	}
+	if got := d.Format(&TimeTrackingConfiguration{WorkingHoursPerDay: 24, WorkingDaysPerWeek: 7}); got != "2d 11h 30m" {
+		t.Errorf("Unexpected format %s", got)
+	}
+	if Duration(30).String() != "0m" {
+		t.Errorf("Unexpected format %s", Duration(30))
+	}
+
+	data, err := json.Marshal(struct{ D Duration }{Duration(5400)})
+	if err != nil || string(data) != `{"D":5400}` {
+		t.Errorf("Unexpected JSON %s (%v)", data, err)
+	}
+	if text, _ := Duration(5400).MarshalText(); string(text) != "1h 30m" {
+		t.Errorf("Unexpected text %s", text)
+	}
+
+	var tracking TimeTracking
+	if err := json.Unmarshal([]byte(`{"timeSpent":"1h","timeSpentSeconds":3600,"originalEstimateSeconds":"2h"}`), &tracking); err != nil {
+		t.Fatal(err)
+	}
+	if tracking.TimeSpentSeconds != 3600 || tracking.OriginalEstimateSeconds != 7200 {
+		t.Errorf("Unexpected time tracking %+v", tracking)
+	}
+}
+
+func TestTimeTracking_Durations(t *testing.T) {
+	tracking := &TimeTracking{RemainingEstimate: "1d", TimeSpentSeconds: 600}
+	tracking.SetOriginalEstimate(Duration(2*3600), nil)
+	if tracking.OriginalEstimate != "2h" || tracking.OriginalEstimateSeconds != 7200 {
+		t.Errorf("Unexpected original estimate %+v", tracking)
+	}
+	if d, err := tracking.RemainingEstimateDuration(nil); err != nil || d != Duration(8*3600) {
+		t.Errorf("Unexpected remaining estimate %d (%v)", d, err)
+	}
+	if d, err := tracking.TimeSpentDuration(nil); err != nil || d != Duration(600) {
+		t.Errorf("Unexpected time spent %d (%v)", d, err)
+	}
+
+	record := &WorklogRecord{}
+	record.SetTimeSpent(Duration(90*60), nil)
+	if record.TimeSpent != "1h 30m" || record.TimeSpentSeconds != 5400 {
+		t.Errorf("Unexpected time spent %+v", record)
+	}
+}
+
+func TestIssueService_GetTimeTrackingConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/configuration/timetracking/options", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"workingHoursPerDay":7.5,"workingDaysPerWeek":5.0,"timeFormat":"pretty","defaultUnit":"hour"}`)
+	})
+
+	configuration, _, err := testClient.Issue.GetTimeTrackingConfiguration()
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if configuration.WorkingHoursPerDay != 7.5 || configuration.DefaultUnit != "hour" {
+		t.Errorf("Unexpected configuration %+v", configuration)
+	}
+	if d, _ := ParseDuration("1d", configuration); d.Seconds() != 27000 {
+		t.Errorf("Expected a day of 7.5 hours, got %d seconds", d.Seconds())
+	}
+}
+
+func TestIssueService_GetTimeTrackingConfiguration_Server(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/configuration/timetracking/options", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	testMux.HandleFunc("/rest/api/2/configuration", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"timeTrackingEnabled":true,"timeTrackingConfiguration":{"workingHoursPerDay":6.0,"workingDaysPerWeek":4.0,"timeFormat":"pretty","defaultUnit":"day"}}`)
+	})
+
+	configuration, _, err := testClient.Issue.GetTimeTrackingConfiguration()
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if configuration.WorkingHoursPerDay != 6 || configuration.WorkingDaysPerWeek != 4 || configuration.DefaultUnit != "day" {
+		t.Errorf("Unexpected configuration %+v", configuration)
+	}
+}
+
+func TestIssueService_SetEstimates(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/issue/TST-1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		payload := map[string]map[string][]map[string]map[string]string{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+		edit := payload["update"]["timetracking"][0]["edit"]
+		if len(edit) != 1 || edit["remainingEstimate"] != "1d 4h" {
+			t.Errorf("Unexpected edit %v", edit)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	remaining := Duration(12 * 3600)
+	resp, err := testClient.Issue.SetEstimates("TST-1", nil, &remaining, nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	Cleanup(resp)
+}
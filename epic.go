@@ -0,0 +1,312 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// EpicService handles epics of Jira Software for the Jira instance / API.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/epic
+type EpicService struct {
+	client *Client
+}
+
+// epicLinkFieldName is the name of the custom field linking issues to epics in company-managed (classic) projects
+const epicLinkFieldName = "Epic Link"
+
+// EpicUpdate holds the values of an epic to change with EpicService.Update.
+// Only the values which are set are updated.
+type EpicUpdate struct {
+	Name    string     `json:"name,omitempty" structs:"name,omitempty"`
+	Summary string     `json:"summary,omitempty" structs:"summary,omitempty"`
+	Color   *EpicColor `json:"color,omitempty" structs:"color,omitempty"`
+	Done    *bool      `json:"done,omitempty" structs:"done,omitempty"`
+}
+
+// EpicRankOptions specifies where an epic is ranked to.
+// Exactly one of RankBeforeEpic and RankAfterEpic has to be set.
+type EpicRankOptions struct {
+	// RankBeforeEpic is the id or key of the epic the epic is ranked before
+	RankBeforeEpic string `json:"rankBeforeEpic,omitempty"`
+	// RankAfterEpic is the id or key of the epic the epic is ranked after
+	RankAfterEpic string `json:"rankAfterEpic,omitempty"`
+	// RankCustomFieldID is the id of the rank field to use. By default the global rank field is used.
+	RankCustomFieldID int `json:"rankCustomFieldId,omitempty"`
+}
+
+// GetWithContext returns the epic for a given epic id or key.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/epic-getEpic
+func (s *EpicService) GetWithContext(ctx context.Context, epicIDOrKey string) (*Epic, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/epic/%s", epicIDOrKey)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	epic := new(Epic)
+	resp, err := s.client.Do(req, epic)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+
+	return epic, resp, nil
+}
+
+// Get wraps GetWithContext using the background context.
+func (s *EpicService) Get(epicIDOrKey string) (*Epic, *Response, error) {
+	return s.GetWithContext(context.Background(), epicIDOrKey)
+}
+
+// UpdateWithContext partially updates an epic: its name, summary, color or done state.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/epic-partiallyUpdateEpic
+func (s *EpicService) UpdateWithContext(ctx context.Context, epicIDOrKey string, update *EpicUpdate) (*Epic, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/epic/%s", epicIDOrKey)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", apiEndpoint, update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	epic := new(Epic)
+	resp, err := s.client.Do(req, epic)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+
+	return epic, resp, nil
+}
+
+// Update wraps UpdateWithContext using the background context.
+func (s *EpicService) Update(epicIDOrKey string, update *EpicUpdate) (*Epic, *Response, error) {
+	return s.UpdateWithContext(context.Background(), epicIDOrKey, update)
+}
+
+// SetNameWithContext changes the name of an epic.
+func (s *EpicService) SetNameWithContext(ctx context.Context, epicIDOrKey, name string) (*Epic, *Response, error) {
+	return s.UpdateWithContext(ctx, epicIDOrKey, &EpicUpdate{Name: name})
+}
+
+// SetName wraps SetNameWithContext using the background context.
+func (s *EpicService) SetName(epicIDOrKey, name string) (*Epic, *Response, error) {
+	return s.SetNameWithContext(context.Background(), epicIDOrKey, name)
+}
+
+// SetColorWithContext changes the color of an epic, e.g. to "color_4".
+func (s *EpicService) SetColorWithContext(ctx context.Context, epicIDOrKey, color string) (*Epic, *Response, error) {
+	return s.UpdateWithContext(ctx, epicIDOrKey, &EpicUpdate{Color: &EpicColor{Key: color}})
+}
+
+// SetColor wraps SetColorWithContext using the background context.
+func (s *EpicService) SetColor(epicIDOrKey, color string) (*Epic, *Response, error) {
+	return s.SetColorWithContext(context.Background(), epicIDOrKey, color)
+}
+
+// SetDoneWithContext marks an epic as done or not done.
+func (s *EpicService) SetDoneWithContext(ctx context.Context, epicIDOrKey string, done bool) (*Epic, *Response, error) {
+	return s.UpdateWithContext(ctx, epicIDOrKey, &EpicUpdate{Done: &done})
+}
+
+// SetDone wraps SetDoneWithContext using the background context.
+func (s *EpicService) SetDone(epicIDOrKey string, done bool) (*Epic, *Response, error) {
+	return s.SetDoneWithContext(context.Background(), epicIDOrKey, done)
+}
+
+// GetIssuesWithContext returns one page of the issues of an epic.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/epic-getIssuesForEpic
+func (s *EpicService) GetIssuesWithContext(ctx context.Context, epicIDOrKey string, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.client.Board.getIssuesWithContext(ctx, fmt.Sprintf("rest/agile/1.0/epic/%s/issue", epicIDOrKey), options)
+}
+
+// GetIssues wraps GetIssuesWithContext using the background context.
+func (s *EpicService) GetIssues(epicIDOrKey string, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.GetIssuesWithContext(context.Background(), epicIDOrKey, options)
+}
+
+// GetIssuesPagesWithContext calls f for every issue of an epic, reading all pages.
+func (s *EpicService) GetIssuesPagesWithContext(ctx context.Context, epicIDOrKey string, options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.client.Board.getIssuesPagesWithContext(ctx, fmt.Sprintf("rest/agile/1.0/epic/%s/issue", epicIDOrKey), options, f)
+}
+
+// GetIssuesPages wraps GetIssuesPagesWithContext using the background context.
+func (s *EpicService) GetIssuesPages(epicIDOrKey string, options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.GetIssuesPagesWithContext(context.Background(), epicIDOrKey, options, f)
+}
+
+// GetIssuesWithoutEpicWithContext returns one page of the issues that do not belong to any epic.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/epic-getIssuesWithoutEpic
+func (s *EpicService) GetIssuesWithoutEpicWithContext(ctx context.Context, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.client.Board.getIssuesWithContext(ctx, "rest/agile/1.0/epic/none/issue", options)
+}
+
+// GetIssuesWithoutEpic wraps GetIssuesWithoutEpicWithContext using the background context.
+func (s *EpicService) GetIssuesWithoutEpic(options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.GetIssuesWithoutEpicWithContext(context.Background(), options)
+}
+
+// GetIssuesWithoutEpicPagesWithContext calls f for every issue that does not belong to any epic, reading all pages.
+func (s *EpicService) GetIssuesWithoutEpicPagesWithContext(ctx context.Context, options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.client.Board.getIssuesPagesWithContext(ctx, "rest/agile/1.0/epic/none/issue", options, f)
+}
+
+// GetIssuesWithoutEpicPages wraps GetIssuesWithoutEpicPagesWithContext using the background context.
+func (s *EpicService) GetIssuesWithoutEpicPages(options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.GetIssuesWithoutEpicPagesWithContext(context.Background(), options, f)
+}
+
+// MoveIssuesToEpicWithContext moves issues to an epic.
+// Jira accepts at most 50 issues per request; longer lists are moved in chunks.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/epic-moveIssuesToEpic
+// Caller must close resp.Body
+func (s *EpicService) MoveIssuesToEpicWithContext(ctx context.Context, epicIDOrKey string, issueIDs []string) (*Response, error) {
+	return s.moveIssuesWithContext(ctx, fmt.Sprintf("rest/agile/1.0/epic/%s/issue", epicIDOrKey), issueIDs)
+}
+
+// MoveIssuesToEpic wraps MoveIssuesToEpicWithContext using the background context.
+// Caller must close resp.Body
+func (s *EpicService) MoveIssuesToEpic(epicIDOrKey string, issueIDs []string) (*Response, error) {
+	return s.MoveIssuesToEpicWithContext(context.Background(), epicIDOrKey, issueIDs)
+}
+
+// RemoveIssuesFromEpicWithContext removes issues from the epics they belong to.
+// Jira accepts at most 50 issues per request; longer lists are moved in chunks.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/epic-removeIssuesFromEpic
+// Caller must close resp.Body
+func (s *EpicService) RemoveIssuesFromEpicWithContext(ctx context.Context, issueIDs []string) (*Response, error) {
+	return s.moveIssuesWithContext(ctx, "rest/agile/1.0/epic/none/issue", issueIDs)
+}
+
+// RemoveIssuesFromEpic wraps RemoveIssuesFromEpicWithContext using the background context.
+// Caller must close resp.Body
+func (s *EpicService) RemoveIssuesFromEpic(issueIDs []string) (*Response, error) {
+	return s.RemoveIssuesFromEpicWithContext(context.Background(), issueIDs)
+}
+
+func (s *EpicService) moveIssuesWithContext(ctx context.Context, apiEndpoint string, issueIDs []string) (*Response, error) {
+	var resp *Response
+	for _, chunk := range chunkStrings(issueIDs, maxIssuesPerAgileRequest) {
+		req, err := s.client.NewRequestWithContext(ctx, "POST", apiEndpoint, IssuesWrapper{Issues: chunk})
+		if err != nil {
+			return nil, err
+		}
+
+		if resp != nil {
+			// only the response of the last chunk is returned to the caller
+			Cleanup(resp)
+		}
+		resp, err = s.client.Do(req, nil)
+		if err != nil {
+			return resp, NewJiraError(resp, err)
+		}
+	}
+	return resp, nil
+}
+
+// RankWithContext moves an epic before or after another epic.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/epic-rankEpics
+// Caller must close resp.Body
+func (s *EpicService) RankWithContext(ctx context.Context, epicIDOrKey string, options *EpicRankOptions) (*Response, error) {
+	if options == nil || (options.RankBeforeEpic == "") == (options.RankAfterEpic == "") {
+		return nil, fmt.Errorf("exactly one of RankBeforeEpic and RankAfterEpic has to be set")
+	}
+
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/epic/%s/rank", epicIDOrKey)
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", apiEndpoint, options)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		err = NewJiraError(resp, err)
+	}
+	return resp, err
+}
+
+// Rank wraps RankWithContext using the background context.
+// Caller must close resp.Body
+func (s *EpicService) Rank(epicIDOrKey string, options *EpicRankOptions) (*Response, error) {
+	return s.RankWithContext(context.Background(), epicIDOrKey, options)
+}
+
+// GetEpicLinkFieldWithContext returns the "Epic Link" custom field of company-managed (classic) projects.
+// Nil is returned if the instance has no such field, i.e. epics are only linked through the parent hierarchy.
+func (s *EpicService) GetEpicLinkFieldWithContext(ctx context.Context) (*Field, error) {
+	fields, _, err := s.client.Field.GetListWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range fields {
+		if fields[i].Custom && strings.EqualFold(fields[i].Name, epicLinkFieldName) {
+			return &fields[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// GetEpicLinkField wraps GetEpicLinkFieldWithContext using the background context.
+func (s *EpicService) GetEpicLinkField() (*Field, error) {
+	return s.GetEpicLinkFieldWithContext(context.Background())
+}
+
+// SetIssueEpicWithContext links an issue to an epic by updating the issue itself.
+// The "Epic Link" custom field is used if epicLinkFieldID is given (company-managed projects),
+// otherwise the parent of the issue is set (team-managed projects and the unified hierarchy).
+// An empty epicKey removes the issue from its epic.
+//
+// Caller must close resp.Body
+func (s *EpicService) SetIssueEpicWithContext(ctx context.Context, issueKey, epicKey, epicLinkFieldID string) (*Response, error) {
+	var value interface{}
+	if epicLinkFieldID != "" {
+		if epicKey != "" {
+			value = epicKey
+		}
+		return s.client.Issue.UpdateIssueWithContext(ctx, issueKey, map[string]interface{}{
+			"fields": map[string]interface{}{epicLinkFieldID: value},
+		})
+	}
+
+	if epicKey != "" {
+		value = Parent{Key: epicKey}
+	}
+	return s.client.Issue.UpdateIssueWithContext(ctx, issueKey, map[string]interface{}{
+		"fields": map[string]interface{}{"parent": value},
+	})
+}
+
+// SetIssueEpic wraps SetIssueEpicWithContext using the background context.
+// Caller must close resp.Body
+func (s *EpicService) SetIssueEpic(issueKey, epicKey, epicLinkFieldID string) (*Response, error) {
+	return s.SetIssueEpicWithContext(context.Background(), issueKey, epicKey, epicLinkFieldID)
+}
+
+// EpicKeyOf returns the key of the epic an issue belongs to, or an empty string.
+// The epic is taken from the agile "epic" field if present, then from the "Epic Link" custom field
+// given by epicLinkFieldID (may be empty), and finally from the parent of the issue,
+// which is the epic of a standard issue in team-managed projects.
+// Epics and sub-tasks never get their parent as epic.
+func EpicKeyOf(issue *Issue, epicLinkFieldID string) string {
+	if issue == nil || issue.Fields == nil {
+		return ""
+	}
+	if issue.Fields.Epic != nil && issue.Fields.Epic.Key != "" {
+		return issue.Fields.Epic.Key
+	}
+	if epicLinkFieldID != "" {
+		if key, ok := issue.Fields.Unknowns[epicLinkFieldID].(string); ok && key != "" {
+			return key
+		}
+	}
+	if issue.Fields.Parent != nil && !issue.Fields.Type.Subtask && !strings.EqualFold(issue.Fields.Type.Name, "Epic") {
+		// The parent of a sub-task is a standard issue, the parent of an epic is an initiative or another higher-level issue
+		return issue.Fields.Parent.Key
+	}
+	return ""
+}
@@ -0,0 +1,217 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestEpicService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/epic/TST-10"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, `{"id":37,"key":"TST-10","name":"Checkout","summary":"Checkout flow","color":{"key":"color_4"},"done":false}`)
+	})
+
+	epic, _, err := testClient.Epic.Get("TST-10")
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if epic == nil || epic.Name != "Checkout" || epic.Color == nil || epic.Color.Key != "color_4" {
+		t.Errorf("Unexpected epic %+v", epic)
+	}
+}
+
+func TestEpicService_SetDone(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/epic/TST-10"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		update := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			t.Fatal(err)
+		}
+		if len(update) != 1 || update["done"] != true {
+			t.Errorf("Expected only done to be updated, got %v", update)
+		}
+		fmt.Fprint(w, `{"id":37,"key":"TST-10","done":true}`)
+	})
+
+	epic, _, err := testClient.Epic.SetDone("TST-10", true)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if epic == nil || !epic.Done {
+		t.Errorf("Expected the epic to be done, got %+v", epic)
+	}
+}
+
+func TestEpicService_GetIssuesPages(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/epic/TST-10/issue"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":2,"issues":[{"key":"TST-11"},{"key":"TST-12"}]}`)
+	})
+
+	count := 0
+	err := testClient.Epic.GetIssuesPages("TST-10", nil, func(issue Issue) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 issues, got %d", count)
+	}
+}
+
+func TestEpicService_MoveIssuesToEpic(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/epic/TST-10/issue"
+
+	calls := 0
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		calls++
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	issues := make([]string, 51)
+	for i := range issues {
+		issues[i] = fmt.Sprintf("TST-%d", 100+i)
+	}
+	if _, err := testClient.Epic.MoveIssuesToEpic("TST-10", issues); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 requests for 51 issues, got %d", calls)
+	}
+}
+
+func TestEpicService_RemoveIssuesFromEpic(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/epic/none/issue"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := testClient.Epic.RemoveIssuesFromEpic([]string{"TST-11"}); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestEpicService_Rank(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/epic/TST-10/rank"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		options := EpicRankOptions{}
+		if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+			t.Fatal(err)
+		}
+		if options.RankBeforeEpic != "TST-20" {
+			t.Errorf("Expected to rank before TST-20, got %+v", options)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := testClient.Epic.Rank("TST-10", &EpicRankOptions{RankBeforeEpic: "TST-20"}); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if _, err := testClient.Epic.Rank("TST-10", &EpicRankOptions{}); err == nil {
+		t.Error("Expected an error without a rank target")
+	}
+}
+
+func TestEpicService_SetIssueEpic(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var update map[string]map[string]interface{}
+	testMux.HandleFunc("/rest/api/2/issue/TST-11", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := testClient.Epic.SetIssueEpic("TST-11", "TST-10", "customfield_10008"); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if update["fields"]["customfield_10008"] != "TST-10" {
+		t.Errorf("Expected the epic link field to be set, got %v", update)
+	}
+
+	if _, err := testClient.Epic.SetIssueEpic("TST-11", "TST-10", ""); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	parent, ok := update["fields"]["parent"].(map[string]interface{})
+	if !ok || parent["key"] != "TST-10" {
+		t.Errorf("Expected the parent to be set, got %v", update)
+	}
+
+	if _, err := testClient.Epic.SetIssueEpic("TST-11", "", ""); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if value, ok := update["fields"]["parent"]; !ok || value != nil {
+		t.Errorf("Expected the parent to be cleared, got %v", update)
+	}
+}
+
+func TestEpicService_GetEpicLinkField(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id":"summary","name":"Summary"},{"id":"customfield_10008","name":"Epic Link","custom":true}]`)
+	})
+
+	field, err := testClient.Epic.GetEpicLinkField()
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if field == nil || field.ID != "customfield_10008" {
+		t.Errorf("Expected the epic link field customfield_10008, got %+v", field)
+	}
+}
+
+func TestEpicKeyOf(t *testing.T) {
+	tests := []struct {
+		issue    *Issue
+		expected string
+	}{
+		{&Issue{Fields: &IssueFields{Epic: &Epic{Key: "TST-1"}}}, "TST-1"},
+		{&Issue{Fields: &IssueFields{Unknowns: map[string]interface{}{"customfield_10008": "TST-2"}}}, "TST-2"},
+		{&Issue{Fields: &IssueFields{Parent: &Parent{Key: "TST-3"}}}, "TST-3"},
+		{&Issue{Fields: &IssueFields{Parent: &Parent{Key: "TST-4"}, Type: IssueType{Subtask: true}}}, ""},
+		{&Issue{Fields: &IssueFields{Parent: &Parent{Key: "TST-5"}, Type: IssueType{Name: "Epic"}}}, ""},
+		{&Issue{Fields: &IssueFields{Parent: &Parent{Key: "TST-6"}, Type: IssueType{Name: "Story"}}}, "TST-6"},
+		{&Issue{Fields: &IssueFields{}}, ""},
+	}
+
+	for _, test := range tests {
+		if key := EpicKeyOf(test.issue, "customfield_10008"); key != test.expected {
+			t.Errorf("Expected epic %q, got %q", test.expected, key)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	jira "github.com/perolo/jira-client"
+	"github.com/perolo/jira-client/jql"
 )
 
 func main() {
@@ -11,9 +12,25 @@ func main() {
 
 	// Running JQL query
 
-	jql := "project = Mesos and type = Bug and Status NOT IN (Resolved)"
-	fmt.Printf("Usecase: Running a JQL query '%s'\n", jql)
-	issues, resp, err := jiraClient.Issue.Search(jql, nil)
+	search := "project = Mesos and type = Bug and Status NOT IN (Resolved)"
+	fmt.Printf("Usecase: Running a JQL query '%s'\n", search)
+	issues, resp, err := jiraClient.Issue.Search(search, nil)
+	if err != nil {
+		panic(err)
+	}
+	outputResponse(issues.Issues, resp)
+
+	fmt.Println("")
+	fmt.Println("")
+
+	// Building the same JQL query from typed clauses, which takes care of quoting
+	query := jql.Where(jql.And(
+		jql.Field("project").Eq(jql.String("Mesos")),
+		jql.Field("type").Eq(jql.String("Bug")),
+		jql.Field("status").NotIn(jql.Strings("Resolved")...),
+	))
+	fmt.Printf("Usecase: Running a JQL query built with the jql package '%s'\n", query)
+	issues, resp, err = jiraClient.Issue.Search(query.String(), nil)
 	if err != nil {
 		panic(err)
 	}
@@ -23,9 +40,9 @@ func main() {
 	fmt.Println("")
 
 	// Running an empty JQL query to get all tickets
-	jql = ""
+	search = ""
 	fmt.Printf("Usecase: Running an empty JQL query to get all tickets\n")
-	issues, resp, err = jiraClient.Issue.Search(jql, nil)
+	issues, resp, err = jiraClient.Issue.Search(search, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,217 @@
+// Package export writes the results of a JQL search to CSV, JSON Lines or XLSX files.
+//
+// The selected system and custom fields are resolved by name and flattened into columns:
+// users, versions, components, options and other objects become their names,
+// multi-value fields are joined (or JSON arrays in JSON Lines), and dates are formatted.
+// Issues are written while the search pages are fetched, so exports of any size need little memory.
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+)
+
+// Format is the file format of an export
+type Format string
+
+// The supported export formats
+const (
+	CSV       Format = "csv"
+	JSONLines Format = "jsonl"
+	XLSX      Format = "xlsx"
+)
+
+// UserFormat selects the attribute used for user fields
+type UserFormat string
+
+// The attributes used for user fields
+const (
+	UserDisplayName UserFormat = "displayName"
+	UserEmail       UserFormat = "emailAddress"
+	UserAccountID   UserFormat = "accountId"
+	UserName        UserFormat = "name"
+)
+
+// Default formatting of the options
+const (
+	DefaultSeparator  = ", "
+	DefaultTimeFormat = "2006-01-02 15:04"
+	DefaultDateFormat = "2006-01-02"
+)
+
+// defaultColumns are exported if the options have no columns
+var defaultColumns = []string{"key", "summary", "issuetype", "status", "assignee", "created", "updated"}
+
+// Options specifies the columns and the formatting of an export
+type Options struct {
+	// Columns are the names or ids of the exported fields, e.g. "key", "Summary", "Fix Version/s" or "customfield_10001".
+	// Default: key, summary, issuetype, status, assignee, created and updated.
+	Columns []string
+	// Fields are the fields of the instance used to resolve the column names, as returned by FieldService.GetList.
+	// If nil, the fields are loaded from Jira.
+	Fields []jira.Field
+	// Separator joins the values of multi-value fields in CSV and XLSX. Default: ", ".
+	Separator string
+	// TimeFormat is the layout of timestamps. Default: "2006-01-02 15:04".
+	TimeFormat string
+	// DateFormat is the layout of dates. Default: "2006-01-02".
+	DateFormat string
+	// Location is the time zone of timestamps. Default: the time zone of the timestamps returned by Jira.
+	Location *time.Location
+	// Users selects the attribute of user fields. Default: UserDisplayName.
+	Users UserFormat
+	// PageSize is the number of issues fetched with one search request. Default: 100.
+	PageSize int
+}
+
+// Column is an exported field
+type Column struct {
+	// Header is the name of the column, the name of the field if it is known
+	Header string
+	// FieldID is the id of the field, e.g. "summary" or "customfield_10001", or "key" / "id" for the issue key / id
+	FieldID string
+	// Schema is the schema of the field, if it is known
+	Schema jira.FieldSchema
+}
+
+// systemSchemas are the schema types of the date fields, for exports without field list
+var systemSchemas = map[string]string{
+	"created":        "datetime",
+	"updated":        "datetime",
+	"resolutiondate": "datetime",
+	"lastViewed":     "datetime",
+	"duedate":        "date",
+}
+
+// ResolveColumns resolves column names to the fields of the instance.
+// "key" and "id" are the issue key and id. Names which are no field name or id are an error,
+// unless fields is empty; then all names are used as field ids.
+func ResolveColumns(names []string, fields []jira.Field) ([]Column, error) {
+	columns := make([]Column, 0, len(names))
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		if lower == "key" || lower == "id" {
+			columns = append(columns, Column{Header: lower, FieldID: lower})
+			continue
+		}
+		if len(fields) == 0 {
+			columns = append(columns, Column{Header: name, FieldID: name, Schema: jira.FieldSchema{Type: systemSchemas[name]}})
+			continue
+		}
+		field := jira.FindFieldByName(fields, name)
+		if field == nil {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		columns = append(columns, Column{Header: field.Name, FieldID: field.ID, Schema: field.Schema})
+	}
+	return columns, nil
+}
+
+// rowWriter writes the rows of one export format
+type rowWriter interface {
+	writeHeader(columns []Column) error
+	writeRow(values []interface{}) error
+	close() error
+}
+
+func newRowWriter(w io.Writer, format Format, options *Options) (rowWriter, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w, options), nil
+	case JSONLines:
+		return newJSONLinesWriter(w, options), nil
+	case XLSX:
+		return newXLSXWriter(w, options)
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// withDefaults returns a copy of the options with the defaults set
+func (o *Options) withDefaults() *Options {
+	options := Options{}
+	if o != nil {
+		options = *o
+	}
+	if len(options.Columns) == 0 {
+		options.Columns = defaultColumns
+	}
+	if options.Separator == "" {
+		options.Separator = DefaultSeparator
+	}
+	if options.TimeFormat == "" {
+		options.TimeFormat = DefaultTimeFormat
+	}
+	if options.DateFormat == "" {
+		options.DateFormat = DefaultDateFormat
+	}
+	if options.Users == "" {
+		options.Users = UserDisplayName
+	}
+	if options.PageSize <= 0 {
+		options.PageSize = 100
+	}
+	return &options
+}
+
+// ExportWithContext runs a JQL search and writes the issues to w in the given format.
+// It returns the number of exported issues. The issues are written as the search pages arrive.
+func ExportWithContext(ctx context.Context, client *jira.Client, jql string, w io.Writer, format Format, options *Options) (int, error) {
+	options = options.withDefaults()
+	if options.Fields == nil {
+		fields, _, err := client.Field.GetListWithContext(ctx)
+		if err != nil {
+			return 0, err
+		}
+		options.Fields = fields
+	}
+
+	columns, err := ResolveColumns(options.Columns, options.Fields)
+	if err != nil {
+		return 0, err
+	}
+	fieldIDs := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column.FieldID != "key" && column.FieldID != "id" {
+			fieldIDs = append(fieldIDs, column.FieldID)
+		}
+	}
+	if len(fieldIDs) == 0 {
+		fieldIDs = []string{"key"}
+	}
+
+	writer, err := newRowWriter(w, format, options)
+	if err != nil {
+		return 0, err
+	}
+	if err := writer.writeHeader(columns); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	searchOptions := &jira.SearchOptions{MaxResults: options.PageSize, Fields: fieldIDs}
+	err = client.Issue.SearchPagesWithContext(ctx, jql, searchOptions, func(issue jira.Issue) error {
+		values, err := Row(&issue, columns, options)
+		if err != nil {
+			return err
+		}
+		if err := writer.writeRow(values); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, writer.close()
+}
+
+// Export wraps ExportWithContext using the background context.
+func Export(client *jira.Client, jql string, w io.Writer, format Format, options *Options) (int, error) {
+	return ExportWithContext(context.Background(), client, jql, w, format, options)
+}
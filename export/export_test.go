@@ -0,0 +1,205 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+)
+
+const testFields = `[
+	{"id": "summary", "name": "Summary", "schema": {"type": "string", "system": "summary"}},
+	{"id": "status", "name": "Status", "schema": {"type": "status", "system": "status"}},
+	{"id": "assignee", "name": "Assignee", "schema": {"type": "user", "system": "assignee"}},
+	{"id": "created", "name": "Created", "schema": {"type": "datetime", "system": "created"}},
+	{"id": "duedate", "name": "Due Date", "schema": {"type": "date", "system": "duedate"}},
+	{"id": "fixVersions", "name": "Fix Version/s", "schema": {"type": "array", "items": "version", "system": "fixVersions"}},
+	{"id": "labels", "name": "Labels", "schema": {"type": "array", "items": "string", "system": "labels"}},
+	{"id": "customfield_10001", "name": "Story Points", "custom": true, "schema": {"type": "number"}},
+	{"id": "customfield_10002", "name": "Sprint", "custom": true, "schema": {"type": "array", "items": "string"}},
+	{"id": "customfield_10003", "name": "Platform", "custom": true, "schema": {"type": "option-with-child"}}
+]`
+
+const testIssues = `{"startAt": 0, "maxResults": 100, "total": 2, "issues": [
+	{"id": "10001", "key": "TST-1", "fields": {
+		"summary": "First, \"quoted\"",
+		"status": {"name": "In Progress", "id": "3"},
+		"assignee": {"accountId": "5b10a2844c20165700ede21a", "displayName": "Jane Doe", "emailAddress": "jane@example.com"},
+		"created": "2021-03-01T23:30:00.000+0000",
+		"duedate": "2021-03-05",
+		"fixVersions": [{"name": "1.0"}, {"name": "1.1"}],
+		"labels": ["a", "b"],
+		"customfield_10001": 3.5,
+		"customfield_10002": ["com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=1,rapidViewId=2,state=CLOSED,name=Sprint 1,startDate=<null>]"],
+		"customfield_10003": {"value": "Linux", "child": {"value": "Debian"}}
+	}},
+	{"id": "10002", "key": "TST-2", "fields": {"summary": "Second", "status": {"name": "Done"}}}
+]}`
+
+var testColumns = []string{"key", "Summary", "Status", "Assignee", "Created", "Due Date", "Fix Version/s", "Labels", "Story Points", "Sprint", "Platform"}
+
+func testServer(t *testing.T) (*jira.Client, func()) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testFields)
+	})
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		expected := "summary,status,assignee,created,duedate,fixVersions,labels,customfield_10001,customfield_10002,customfield_10003"
+		if got := r.URL.Query().Get("fields"); got != expected {
+			t.Errorf("Expected fields %s, got %s", expected, got)
+		}
+		_, _ = fmt.Fprint(w, testIssues)
+	})
+
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server.Close
+}
+
+func TestExport_CSV(t *testing.T) {
+	client, closeServer := testServer(t)
+	defer closeServer()
+
+	var buf bytes.Buffer
+	loc := time.FixedZone("CET", 3600)
+	count, err := Export(client, "project = TST", &buf, CSV, &Options{Columns: testColumns, Location: loc, Separator: "|"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 issues, got %d", count)
+	}
+	expected := "key,Summary,Status,Assignee,Created,Due Date,Fix Version/s,Labels,Story Points,Sprint,Platform\n" +
+		`TST-1,"First, ""quoted""",In Progress,Jane Doe,2021-03-02 00:30,2021-03-05,1.0|1.1,a|b,3.5,Sprint 1,Linux / Debian` + "\n" +
+		"TST-2,Second,Done,,,,,,,,\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestExport_JSONLines(t *testing.T) {
+	client, closeServer := testServer(t)
+	defer closeServer()
+
+	var buf bytes.Buffer
+	_, err := Export(client, "project = TST", &buf, JSONLines, &Options{Columns: testColumns, Users: UserEmail, DateFormat: "02.01.2006"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %q", buf.String())
+	}
+	var first map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if first["Assignee"] != "jane@example.com" || first["Due Date"] != "05.03.2021" || first["Story Points"] != 3.5 ||
+		fmt.Sprint(first["Fix Version/s"]) != "[1.0 1.1]" || first["Created"] != "2021-03-01 23:30" {
+		t.Errorf("Unexpected object %s", lines[0])
+	}
+	if lines[1] != `{"Assignee":null,"Created":null,"Due Date":null,"Fix Version/s":null,"Labels":null,"Platform":null,"Sprint":null,"Status":"Done","Story Points":null,"Summary":"Second","key":"TST-2"}` {
+		t.Errorf("Unexpected object %s", lines[1])
+	}
+}
+
+func TestExport_XLSX(t *testing.T) {
+	client, closeServer := testServer(t)
+	defer closeServer()
+
+	var buf bytes.Buffer
+	if _, err := Export(client, "project = TST", &buf, XLSX, &Options{Columns: testColumns}); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sheet string
+	for _, f := range archive.File {
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sheet = string(data)
+	}
+	if len(archive.File) != 5 {
+		t.Errorf("Expected 5 parts, got %d", len(archive.File))
+	}
+	for _, expected := range []string{
+		`<row r="1"><c r="A1" t="inlineStr"><is><t xml:space="preserve">key</t></is></c>`,
+		`<c r="B2" t="inlineStr"><is><t xml:space="preserve">First, &#34;quoted&#34;</t></is></c>`,
+		`<c r="I2"><v>3.5</v></c>`,
+		`<row r="3"><c r="A3" t="inlineStr"><is><t xml:space="preserve">TST-2</t></is></c>`,
+		`</sheetData></worksheet>`,
+	} {
+		if !strings.Contains(sheet, expected) {
+			t.Errorf("Expected %s in sheet %s", expected, sheet)
+		}
+	}
+}
+
+func TestExport_UnknownColumn(t *testing.T) {
+	client, closeServer := testServer(t)
+	defer closeServer()
+
+	_, err := Export(client, "project = TST", ioutil.Discard, CSV, &Options{Columns: []string{"Story Pionts"}})
+	if err == nil || !strings.Contains(err.Error(), "Story Pionts") {
+		t.Errorf("Expected an unknown field error, got %v", err)
+	}
+	_, err = Export(client, "project = TST", ioutil.Discard, "pdf", &Options{Columns: testColumns})
+	if err == nil {
+		t.Error("Expected an unknown format error")
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	tt := map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for index, expected := range tt {
+		if got := xlsxColumn(index); got != expected {
+			t.Errorf("%d: Expected %s, got %s", index, expected, got)
+		}
+	}
+}
+
+func TestJSONLinesWriter_SameHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	w := newJSONLinesWriter(&buf, &Options{})
+	columns := []Column{
+		{Header: "key", FieldID: "key"},
+		{Header: "Team", FieldID: "customfield_10001"},
+		{Header: "Team", FieldID: "customfield_10002"},
+		{Header: "key", FieldID: "key"},
+	}
+	if err := w.writeHeader(columns); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.writeRow([]interface{}{"TST-1", "Red", "Blue", "TST-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"Team (customfield_10001)":"Red","Team (customfield_10002)":"Blue","key":"TST-1","key (2)":"TST-1"}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, buf.String())
+	}
+}
@@ -0,0 +1,160 @@
+package export
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+)
+
+// dateValue is a date without time, formatted with Options.DateFormat
+type dateValue time.Time
+
+// serverSprint matches the name in the string representation of a sprint returned by Jira Server,
+// e.g. com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=1,rapidViewId=2,state=CLOSED,name=Sprint 1,...]
+var serverSprint = regexp.MustCompile(`^com\.atlassian\.greenhopper\.service\.sprint\.Sprint@.*[\[,]name=([^,\]]*)`)
+
+// Row returns the flattened values of the columns of an issue.
+// The values are nil, strings, float64, bool, time.Time, dates or []interface{} for multi-value fields.
+func Row(issue *jira.Issue, columns []Column, options *Options) ([]interface{}, error) {
+	options = options.withDefaults()
+
+	var fields map[string]interface{}
+	if issue.Fields != nil {
+		data, err := json.Marshal(issue.Fields)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+	}
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		switch column.FieldID {
+		case "key":
+			values[i] = issue.Key
+		case "id":
+			values[i] = issue.ID
+		default:
+			values[i] = flatten(jira.FindFieldValue(fields, column.FieldID), column.Schema, options)
+		}
+	}
+	return values, nil
+}
+
+// flatten converts a JSON value of a field to a column value
+func flatten(value interface{}, schema jira.FieldSchema, options *Options) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		values := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if flat := flatten(item, schema, options); flat != nil {
+				values = append(values, flat)
+			}
+		}
+		return values
+	case map[string]interface{}:
+		return flattenObject(v, schema, options)
+	case string:
+		return flattenString(v, schema, options)
+	}
+	return value
+}
+
+func flattenString(s string, schema jira.FieldSchema, options *Options) interface{} {
+	if s == "" {
+		return nil
+	}
+	if match := serverSprint.FindStringSubmatch(s); match != nil {
+		return match[1]
+	}
+	if schema.Type != "date" && schema.Type != "datetime" && schema.Items != "date" && schema.Items != "datetime" {
+		return s
+	}
+	t, err := jira.ParseTime(s)
+	if err != nil {
+		return s
+	}
+	if t.Year() <= 1 {
+		return nil
+	}
+	if schema.Type == "date" || schema.Items == "date" {
+		return dateValue(t)
+	}
+	if options.Location != nil {
+		t = t.In(options.Location)
+	}
+	return t
+}
+
+// flattenObject converts an object to its name: users to the configured attribute,
+// options to their value, and versions, components, statuses etc. to their name
+func flattenObject(object map[string]interface{}, schema jira.FieldSchema, options *Options) interface{} {
+	_, accountID := object["accountId"]
+	_, email := object["emailAddress"]
+	if accountID || email || schema.Type == "user" || schema.Items == "user" {
+		if value, ok := object[string(options.Users)].(string); ok && value != "" {
+			return value
+		}
+		for _, key := range []string{"displayName", "name", "accountId"} {
+			if value, ok := object[key].(string); ok && value != "" {
+				return value
+			}
+		}
+	}
+
+	if value, ok := object["value"]; ok {
+		// cascading select: "parent / child"
+		if child, ok := object["child"].(map[string]interface{}); ok {
+			return text(value, options) + " / " + text(child["value"], options)
+		}
+		return flatten(value, jira.FieldSchema{}, options)
+	}
+	for _, key := range []string{"name", "key", "displayName", "id"} {
+		if value, ok := object[key]; ok {
+			return flatten(value, jira.FieldSchema{}, options)
+		}
+	}
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil
+	}
+	return string(data)
+}
+
+// text returns a column value as text
+func text(value interface{}, options *Options) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(options.TimeFormat)
+	case dateValue:
+		return time.Time(v).Format(options.DateFormat)
+	case []interface{}:
+		values := make([]string, len(v))
+		for i := range v {
+			values[i] = text(v[i], options)
+		}
+		return strings.Join(values, options.Separator)
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
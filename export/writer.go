@@ -0,0 +1,113 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// csvWriter writes one line per issue with a header line
+type csvWriter struct {
+	w       *csv.Writer
+	options *Options
+}
+
+func newCSVWriter(w io.Writer, options *Options) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w), options: options}
+}
+
+func (c *csvWriter) writeHeader(columns []Column) error {
+	header := make([]string, len(columns))
+	for i := range columns {
+		header[i] = columns[i].Header
+	}
+	return c.w.Write(header)
+}
+
+func (c *csvWriter) writeRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i := range values {
+		record[i] = text(values[i], c.options)
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonLinesWriter writes one JSON object per issue and line, with the column headers as keys.
+// Headers shared by several fields get the field id appended, e.g. "Team (customfield_10001)".
+// Multi-value fields are arrays, numbers and booleans keep their type.
+type jsonLinesWriter struct {
+	w       *bufio.Writer
+	keys    []string
+	options *Options
+}
+
+func newJSONLinesWriter(w io.Writer, options *Options) *jsonLinesWriter {
+	return &jsonLinesWriter{w: bufio.NewWriter(w), options: options}
+}
+
+func (j *jsonLinesWriter) writeHeader(columns []Column) error {
+	fields := make(map[string]map[string]bool)
+	for _, column := range columns {
+		if fields[column.Header] == nil {
+			fields[column.Header] = make(map[string]bool)
+		}
+		fields[column.Header][column.FieldID] = true
+	}
+
+	// a column requested more than once is numbered
+	used := make(map[string]int)
+	j.keys = make([]string, len(columns))
+	for i, column := range columns {
+		key := column.Header
+		if len(fields[column.Header]) > 1 {
+			key = fmt.Sprintf("%s (%s)", column.Header, column.FieldID)
+		}
+		used[key]++
+		if used[key] > 1 {
+			key = fmt.Sprintf("%s (%d)", key, used[key])
+		}
+		j.keys[i] = key
+	}
+	return nil
+}
+
+func (j *jsonLinesWriter) writeRow(values []interface{}) error {
+	object := make(map[string]interface{}, len(values))
+	for i := range values {
+		object[j.keys[i]] = j.jsonValue(values[i])
+	}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	if _, err := j.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (j *jsonLinesWriter) jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time, dateValue:
+		return text(v, j.options)
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i := range v {
+			values[i] = j.jsonValue(v[i])
+		}
+		return values
+	}
+	return value
+}
+
+func (j *jsonLinesWriter) close() error {
+	return j.w.Flush()
+}
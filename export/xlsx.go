@@ -0,0 +1,126 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// The parts of a workbook with a single sheet, besides the sheet itself
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Issues" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// xlsxWriter writes a workbook with one sheet. The sheet is the last part of the archive,
+// so the rows are streamed into it; strings are inline strings, which needs no shared string table.
+type xlsxWriter struct {
+	zip     *zip.Writer
+	sheet   *bufio.Writer
+	row     int
+	options *Options
+}
+
+func newXLSXWriter(w io.Writer, options *Options) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	_, err = sheet.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{zip: archive, sheet: sheet, options: options}, nil
+}
+
+// xlsxColumn returns the name of a column, e.g. A for 0 and AA for 26
+func xlsxColumn(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+func (x *xlsxWriter) writeCells(values []interface{}) error {
+	x.row++
+	row := strconv.Itoa(x.row)
+	if _, err := x.sheet.WriteString(`<row r="` + row + `">`); err != nil {
+		return err
+	}
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		ref := xlsxColumn(i) + row
+		if number, ok := value.(float64); ok {
+			if _, err := x.sheet.WriteString(`<c r="` + ref + `"><v>` + strconv.FormatFloat(number, 'f', -1, 64) + `</v></c>`); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := x.sheet.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(x.sheet, []byte(text(value, x.options))); err != nil {
+			return err
+		}
+		if _, err := x.sheet.WriteString(`</t></is></c>`); err != nil {
+			return err
+		}
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (x *xlsxWriter) writeHeader(columns []Column) error {
+	header := make([]interface{}, len(columns))
+	for i := range columns {
+		header[i] = columns[i].Header
+	}
+	return x.writeCells(header)
+}
+
+func (x *xlsxWriter) writeRow(values []interface{}) error {
+	return x.writeCells(values)
+}
+
+func (x *xlsxWriter) close() error {
+	if _, err := x.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
@@ -3,6 +3,8 @@ package jira
 import (
 	"context"
 	"fmt"
+	"strings"
+
 	"github.com/google/go-querystring/query"
 )
 
@@ -58,6 +60,57 @@ func (s *FieldService) GetList() ([]Field, *Response, error) {
 	return s.GetListWithContext(context.Background())
 }
 
+// GetByNameWithContext returns the field with the given name as shown in the UI, e.g. "Story Points".
+// The comparison of the name is case insensitive. If several fields share the name, the first one is returned.
+func (s *FieldService) GetByNameWithContext(ctx context.Context, name string) (*Field, *Response, error) {
+	fields, resp, err := s.GetListWithContext(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	field := FindFieldByName(fields, name)
+	if field == nil {
+		return nil, resp, fmt.Errorf("no field with name %q found", name)
+	}
+	return field, resp, nil
+}
+
+// GetByName wraps GetByNameWithContext using the background context.
+func (s *FieldService) GetByName(name string) (*Field, *Response, error) {
+	return s.GetByNameWithContext(context.Background(), name)
+}
+
+// FindFieldByName returns the field with the given name or id from fields, or nil if there is none.
+// The comparison of the name is case insensitive.
+func FindFieldByName(fields []Field, name string) *Field {
+	for i := range fields {
+		if fields[i].ID == name {
+			return &fields[i]
+		}
+	}
+	for i := range fields {
+		if strings.EqualFold(fields[i].Name, name) {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// FindFieldValue returns the value of the field with the given id from the fields of an issue
+// as generic JSON values, e.g. IssueFields marshaled and unmarshaled into a map, or nil if there is none.
+// System fields are looked up case insensitively, as IssueFields marshals some of them with a different case than their id.
+func FindFieldValue(fields map[string]interface{}, id string) interface{} {
+	if value, ok := fields[id]; ok {
+		return value
+	}
+	for key, value := range fields {
+		if strings.EqualFold(key, id) {
+			return value
+		}
+	}
+	return nil
+}
+
 type FieldOptions struct {
 	// StartAt: The starting index of the returned projects. Base index: 0.
 	StartAt int `url:"startAt,omitempty"`
@@ -30,3 +30,60 @@ func TestFieldService_GetList(t *testing.T) {
 		t.Errorf("Error given: %s", err)
 	}
 }
+
+func TestFieldService_GetByName(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEdpoint := "/rest/api/2/field"
+
+	raw, err := ioutil.ReadFile("./mocks/all_fields.json")
+	if err != nil {
+		t.Error(err.Error())
+	}
+	testMux.HandleFunc(testAPIEdpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEdpoint)
+		fmt.Fprint(w, string(raw))
+	})
+
+	field, _, err := testClient.Field.GetByName("summary")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if field.ID != "summary" {
+		t.Errorf("Expected field summary, got %s", field.ID)
+	}
+
+	if _, _, err = testClient.Field.GetByName("Does not exist"); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+}
+
+func TestFindFieldByName(t *testing.T) {
+	fields := []Field{
+		{ID: "customfield_10002", Name: "Story Points"},
+		{ID: "customfield_10003", Name: "customfield_10002"},
+	}
+	if field := FindFieldByName(fields, "story points"); field == nil || field.ID != "customfield_10002" {
+		t.Errorf("Expected to find the field by name, got %v", field)
+	}
+	if field := FindFieldByName(fields, "customfield_10002"); field == nil || field.ID != "customfield_10002" {
+		t.Errorf("Expected the id to take precedence over the name, got %v", field)
+	}
+	if field := FindFieldByName(fields, "Epic Link"); field != nil {
+		t.Errorf("Expected no field, got %v", field)
+	}
+}
+
+func TestFindFieldValue(t *testing.T) {
+	fields := map[string]interface{}{"timeestimate": 60.0, "customfield_10002": 3.0}
+	if value := FindFieldValue(fields, "customfield_10002"); value != 3.0 {
+		t.Errorf("Expected the value of the custom field, got %v", value)
+	}
+	if value := FindFieldValue(fields, "timeEstimate"); value != 60.0 {
+		t.Errorf("Expected the value to be found case insensitively, got %v", value)
+	}
+	if value := FindFieldValue(fields, "duedate"); value != nil {
+		t.Errorf("Expected no value, got %v", value)
+	}
+}
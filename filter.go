@@ -309,3 +309,106 @@ func (fs *FilterService) SearchWithContext(ctx context.Context, opt *FilterSearc
 func (fs *FilterService) Search(opt *FilterSearchOptions) (*FiltersList, *Response, error) {
 	return fs.SearchWithContext(context.Background(), opt)
 }
+
+// FilterOptions are the properties of a filter which can be set when creating or updating it
+type FilterOptions struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Jql         string `json:"jql,omitempty"`
+	Favourite   *bool  `json:"favourite,omitempty"`
+}
+
+// CreateWithContext creates a filter owned by the current user
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/filter-createFilter
+func (fs *FilterService) CreateWithContext(ctx context.Context, options *FilterOptions) (*Filter, *Response, error) {
+	apiEndpoint := "rest/api/2/filter"
+	req, err := fs.client.NewRequestWithContext(ctx, "POST", apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter := new(Filter)
+	resp, err := fs.client.Do(req, filter)
+	if err != nil {
+		jerr := NewJiraError(resp, err)
+		return nil, resp, jerr
+	}
+
+	return filter, resp, err
+}
+
+// Create wraps CreateWithContext using the background context.
+func (fs *FilterService) Create(options *FilterOptions) (*Filter, *Response, error) {
+	return fs.CreateWithContext(context.Background(), options)
+}
+
+// UpdateWithContext changes the name, description, JQL or favourite flag of a filter. Empty options are left unchanged:
+// as the API requires the name and replaces the description and JQL, they are taken from the current filter.
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/filter-editFilter
+func (fs *FilterService) UpdateWithContext(ctx context.Context, filterID int, options *FilterOptions) (*Filter, *Response, error) {
+	update := FilterOptions{}
+	if options != nil {
+		update = *options
+	}
+	if update.Name == "" || update.Description == "" || update.Jql == "" {
+		current, resp, err := fs.GetWithContext(ctx, filterID)
+		if err != nil {
+			return nil, resp, err
+		}
+		if update.Name == "" {
+			update.Name = current.Name
+		}
+		if update.Description == "" {
+			update.Description = current.Description
+		}
+		if update.Jql == "" {
+			update.Jql = current.Jql
+		}
+	}
+
+	apiEndpoint := fmt.Sprintf("rest/api/2/filter/%d", filterID)
+	req, err := fs.client.NewRequestWithContext(ctx, "PUT", apiEndpoint, &update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filter := new(Filter)
+	resp, err := fs.client.Do(req, filter)
+	if err != nil {
+		jerr := NewJiraError(resp, err)
+		return nil, resp, jerr
+	}
+
+	return filter, resp, err
+}
+
+// Update wraps UpdateWithContext using the background context.
+func (fs *FilterService) Update(filterID int, options *FilterOptions) (*Filter, *Response, error) {
+	return fs.UpdateWithContext(context.Background(), filterID, options)
+}
+
+// DeleteWithContext deletes a filter
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/filter-deleteFilter
+// Caller must close resp.Body
+func (fs *FilterService) DeleteWithContext(ctx context.Context, filterID int) (*Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/api/2/filter/%d", filterID)
+	req, err := fs.client.NewRequestWithContext(ctx, "DELETE", apiEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := fs.client.Do(req, nil)
+	if err != nil {
+		return resp, NewJiraError(resp, err)
+	}
+	return resp, nil
+}
+
+// Delete wraps DeleteWithContext using the background context.
+// Caller must close resp.Body
+func (fs *FilterService) Delete(filterID int) (*Response, error) {
+	return fs.DeleteWithContext(context.Background(), filterID)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -124,3 +125,71 @@ func TestFilterService_Search(t *testing.T) {
 		t.Errorf("Expected Filters, got nil")
 	}
 }
+
+func TestFilterService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/filter"
+	testMux.HandleFunc(testAPIEndpoint, func(writer http.ResponseWriter, request *http.Request) {
+		testMethod(t, request, "POST")
+		testRequestURL(t, request, testAPIEndpoint)
+		body, _ := ioutil.ReadAll(request.Body)
+		if expected := `{"name":"Open bugs","jql":"type = Bug","favourite":true}`; strings.TrimSpace(string(body)) != expected {
+			t.Errorf("Expected body %s, got %s", expected, body)
+		}
+		fmt.Fprint(writer, `{"id":"10010","name":"Open bugs","jql":"type = Bug"}`)
+	})
+
+	favourite := true
+	filter, _, err := testClient.Filter.Create(&FilterOptions{Name: "Open bugs", Jql: "type = Bug", Favourite: &favourite})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if filter.ID != "10010" {
+		t.Errorf("Expected filter 10010, got %s", filter.ID)
+	}
+}
+
+func TestFilterService_Update(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/filter/10010"
+	testMux.HandleFunc(testAPIEndpoint, func(writer http.ResponseWriter, request *http.Request) {
+		testRequestURL(t, request, testAPIEndpoint)
+		if request.Method == "GET" {
+			fmt.Fprint(writer, `{"id":"10010","name":"Open bugs","description":"Unresolved bugs","jql":"type = Bug"}`)
+			return
+		}
+		testMethod(t, request, "PUT")
+		body, _ := ioutil.ReadAll(request.Body)
+		if expected := `{"name":"Open bugs","description":"Unresolved bugs","jql":"type = Bug AND resolution IS EMPTY"}`; strings.TrimSpace(string(body)) != expected {
+			t.Errorf("Expected the unset options of the current filter, got %s", body)
+		}
+		fmt.Fprint(writer, `{"id":"10010","name":"Open bugs","jql":"type = Bug AND resolution IS EMPTY"}`)
+	})
+
+	filter, _, err := testClient.Filter.Update(10010, &FilterOptions{Jql: "type = Bug AND resolution IS EMPTY"})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if filter.Jql != "type = Bug AND resolution IS EMPTY" {
+		t.Errorf("Unexpected JQL %s", filter.Jql)
+	}
+}
+
+func TestFilterService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/api/2/filter/10010"
+	testMux.HandleFunc(testAPIEndpoint, func(writer http.ResponseWriter, request *http.Request) {
+		testMethod(t, request, "DELETE")
+		testRequestURL(t, request, testAPIEndpoint)
+		writer.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := testClient.Filter.Delete(10010)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	Cleanup(resp)
+}
@@ -0,0 +1,185 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxKeysPerHierarchyQuery is the maximum number of parent keys used in one JQL query of the hierarchy walker
+const maxKeysPerHierarchyQuery = 100
+
+// HierarchyOptions specifies the optional parameters for IssueService.GetHierarchy
+type HierarchyOptions struct {
+	// EpicLinkFieldID is the id of the "Epic Link" custom field (e.g. customfield_10008).
+	// If set, the issues of an epic are also found through the epic link of company-managed projects.
+	EpicLinkFieldID string
+	// Fields are additional fields to load for every issue of the tree
+	Fields []string
+	// MaxDepth limits the number of levels below the root. 0 means no limit.
+	MaxDepth int
+}
+
+// IssueNode is an issue in the hierarchy tree, with its child issues and the values rolled up over its subtree
+type IssueNode struct {
+	Issue    Issue        `json:"issue"`
+	Children []*IssueNode `json:"children,omitempty"`
+	Rollup   IssueRollup  `json:"rollup"`
+}
+
+// IssueRollup holds values summed up over an issue and all of its descendants.
+// Times are in seconds.
+type IssueRollup struct {
+	Issues            int `json:"issues"`
+	OriginalEstimate  int `json:"originalEstimate"`
+	RemainingEstimate int `json:"remainingEstimate"`
+	TimeSpent         int `json:"timeSpent"`
+	// StatusCategories counts the issues per status category key (new, indeterminate, done)
+	StatusCategories map[string]int `json:"statusCategories"`
+}
+
+// GetHierarchyWithContext loads an issue and all of its descendants: the issues of an epic,
+// the sub-tasks of a story and the children of any higher hierarchy level.
+// Descendants are loaded level by level with batched JQL queries (parent in (...)).
+// An issue is only added once to the tree, so cyclic links do not cause endless loading.
+func (s *IssueService) GetHierarchyWithContext(ctx context.Context, issueKey string, options *HierarchyOptions) (*IssueNode, error) {
+	if options == nil {
+		options = &HierarchyOptions{}
+	}
+
+	fields := []string{"summary", "status", "issuetype", "parent", "timeoriginalestimate", "timeestimate", "timespent"}
+	if options.EpicLinkFieldID != "" {
+		fields = append(fields, options.EpicLinkFieldID)
+	}
+	fields = append(fields, options.Fields...)
+
+	var root *IssueNode
+	err := s.SearchPagesWithContext(ctx, fmt.Sprintf("issuekey = %s", issueKey), &SearchOptions{MaxResults: 100, Fields: fields}, func(issue Issue) error {
+		root = &IssueNode{Issue: issue}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, fmt.Errorf("issue %s not found", issueKey)
+	}
+
+	visited := map[string]bool{root.Issue.Key: true}
+	level := map[string]*IssueNode{root.Issue.Key: root}
+	for depth := 1; len(level) > 0 && (options.MaxDepth == 0 || depth <= options.MaxDepth); depth++ {
+		keys := make([]string, 0, len(level))
+		for key := range level {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		next := make(map[string]*IssueNode)
+		for _, chunk := range chunkStrings(keys, maxKeysPerHierarchyQuery) {
+			list := strings.Join(chunk, ", ")
+			jql := fmt.Sprintf("parent in (%s)", list)
+			if options.EpicLinkFieldID != "" {
+				jql += fmt.Sprintf(" OR %s in (%s)", jqlCustomFieldRef(options.EpicLinkFieldID), list)
+			}
+			jql += " ORDER BY key"
+
+			// SearchPagesWithContext advances StartAt, so every query needs its own options
+			err := s.SearchPagesWithContext(ctx, jql, &SearchOptions{MaxResults: 100, Fields: fields}, func(issue Issue) error {
+				if visited[issue.Key] {
+					return nil
+				}
+				parent := level[hierarchyParentKey(&issue, options.EpicLinkFieldID, level)]
+				if parent == nil {
+					return nil
+				}
+				visited[issue.Key] = true
+				node := &IssueNode{Issue: issue}
+				parent.Children = append(parent.Children, node)
+				next[issue.Key] = node
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		level = next
+	}
+
+	root.rollup()
+	return root, nil
+}
+
+// GetHierarchy wraps GetHierarchyWithContext using the background context.
+func (s *IssueService) GetHierarchy(issueKey string, options *HierarchyOptions) (*IssueNode, error) {
+	return s.GetHierarchyWithContext(context.Background(), issueKey, options)
+}
+
+// hierarchyParentKey returns the key of the parent of an issue among the issues of the current level.
+// The parent field takes precedence over the epic link, as a sub-task has a parent and may inherit an epic link.
+func hierarchyParentKey(issue *Issue, epicLinkFieldID string, level map[string]*IssueNode) string {
+	if issue.Fields == nil {
+		return ""
+	}
+	if issue.Fields.Parent != nil {
+		if _, ok := level[issue.Fields.Parent.Key]; ok {
+			return issue.Fields.Parent.Key
+		}
+	}
+	if epicLinkFieldID != "" {
+		if key, ok := issue.Fields.Unknowns[epicLinkFieldID].(string); ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// jqlCustomFieldRef returns the JQL reference of a custom field, e.g. cf[10008] for customfield_10008
+func jqlCustomFieldRef(fieldID string) string {
+	if id := strings.TrimPrefix(fieldID, "customfield_"); id != fieldID {
+		return fmt.Sprintf("cf[%s]", id)
+	}
+	return fieldID
+}
+
+// rollup computes the rolled up values of the node and all of its descendants
+func (n *IssueNode) rollup() {
+	n.Rollup = IssueRollup{Issues: 1, StatusCategories: make(map[string]int)}
+	if fields := n.Issue.Fields; fields != nil {
+		n.Rollup.OriginalEstimate = fields.TimeOriginalEstimate
+		n.Rollup.RemainingEstimate = fields.TimeEstimate
+		n.Rollup.TimeSpent = fields.TimeSpent
+		if fields.Status != nil {
+			n.Rollup.StatusCategories[fields.Status.StatusCategory.Key]++
+		}
+	}
+
+	for _, child := range n.Children {
+		child.rollup()
+		n.Rollup.Issues += child.Rollup.Issues
+		n.Rollup.OriginalEstimate += child.Rollup.OriginalEstimate
+		n.Rollup.RemainingEstimate += child.Rollup.RemainingEstimate
+		n.Rollup.TimeSpent += child.Rollup.TimeSpent
+		for category, count := range child.Rollup.StatusCategories {
+			n.Rollup.StatusCategories[category] += count
+		}
+	}
+}
+
+// Walk calls f for the node and all of its descendants, depth first, with the depth of the node below n.
+// Walking stops at the first error returned by f.
+func (n *IssueNode) Walk(f func(node *IssueNode, depth int) error) error {
+	return n.walk(f, 0)
+}
+
+func (n *IssueNode) walk(f func(node *IssueNode, depth int) error, depth int) error {
+	if err := f(n, depth); err != nil {
+		return err
+	}
+	for _, child := range n.Children {
+		if err := child.walk(f, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
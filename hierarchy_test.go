@@ -0,0 +1,161 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testSearchPage responds with the page of the issues selected by startAt, with at most 50 issues per page
+func testSearchPage(t *testing.T, w http.ResponseWriter, r *http.Request, issues []string) {
+	startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+	end := startAt + 50
+	if end > len(issues) {
+		end = len(issues)
+	}
+	if startAt > end {
+		t.Errorf("Unexpected startAt %d for %d issues", startAt, len(issues))
+		startAt = end
+	}
+	fmt.Fprintf(w, `{"startAt":%d,"maxResults":50,"total":%d,"issues":[%s]}`, startAt, len(issues), strings.Join(issues[startAt:end], ","))
+}
+
+// testKeyList returns the keys of the first "in (...)" list of a JQL query
+func testKeyList(jql string) []string {
+	match := regexp.MustCompile(`in \(([^)]*)\)`).FindStringSubmatch(jql)
+	if match == nil {
+		return nil
+	}
+	return strings.Split(match[1], ", ")
+}
+
+func TestIssueService_GetHierarchy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	queries := 0
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		queries++
+		switch jql := r.URL.Query().Get("jql"); jql {
+		case "issuekey = TST-1":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":1,"issues":[
+				{"key":"TST-1","fields":{"issuetype":{"name":"Epic"},"status":{"statusCategory":{"key":"indeterminate"}},"timeoriginalestimate":3600}}]}`)
+		case "parent in (TST-1) OR cf[10008] in (TST-1) ORDER BY key":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":2,"issues":[
+				{"key":"TST-2","fields":{"issuetype":{"name":"Story"},"parent":{"key":"TST-1"},"status":{"statusCategory":{"key":"done"}},"timespent":1800}},
+				{"key":"TST-3","fields":{"issuetype":{"name":"Story"},"customfield_10008":"TST-1","status":{"statusCategory":{"key":"new"}},"timeoriginalestimate":7200,"timeestimate":7200}}]}`)
+		case "parent in (TST-2, TST-3) OR cf[10008] in (TST-2, TST-3) ORDER BY key":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":2,"issues":[
+				{"key":"TST-1","fields":{"issuetype":{"name":"Epic"},"parent":{"key":"TST-3"}}},
+				{"key":"TST-4","fields":{"issuetype":{"name":"Sub-task","subtask":true},"parent":{"key":"TST-2"},"status":{"statusCategory":{"key":"done"}},"timespent":600}}]}`)
+		case "parent in (TST-4) OR cf[10008] in (TST-4) ORDER BY key":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":0,"issues":[]}`)
+		default:
+			t.Errorf("Unexpected JQL %q", jql)
+		}
+	})
+
+	root, err := testClient.Issue.GetHierarchy("TST-1", &HierarchyOptions{EpicLinkFieldID: "customfield_10008"})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if queries != 4 {
+		t.Errorf("Expected 4 queries, got %d", queries)
+	}
+	if len(root.Children) != 2 || len(root.Children[0].Children) != 1 || len(root.Children[1].Children) != 0 {
+		t.Fatalf("Unexpected tree %+v", root)
+	}
+	if root.Children[0].Children[0].Issue.Key != "TST-4" {
+		t.Errorf("Expected TST-4 below TST-2, got %s", root.Children[0].Children[0].Issue.Key)
+	}
+
+	rollup := root.Rollup
+	if rollup.Issues != 4 || rollup.OriginalEstimate != 10800 || rollup.RemainingEstimate != 7200 || rollup.TimeSpent != 2400 {
+		t.Errorf("Unexpected rollup %+v", rollup)
+	}
+	if rollup.StatusCategories["done"] != 2 || rollup.StatusCategories["new"] != 1 || rollup.StatusCategories["indeterminate"] != 1 {
+		t.Errorf("Unexpected status categories %v", rollup.StatusCategories)
+	}
+
+	var keys []string
+	_ = root.Walk(func(node *IssueNode, depth int) error {
+		keys = append(keys, fmt.Sprintf("%d:%s", depth, node.Issue.Key))
+		return nil
+	})
+	if fmt.Sprint(keys) != "[0:TST-1 1:TST-2 2:TST-4 1:TST-3]" {
+		t.Errorf("Unexpected walk order %v", keys)
+	}
+}
+
+func TestIssueService_GetHierarchy_Pages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// TST-1 has 60 children and its first child has 60 sub-tasks, so both levels need two pages
+	children := map[string][]string{"TST-1": nil, "TST-1001": nil}
+	for i := 1; i <= 60; i++ {
+		children["TST-1"] = append(children["TST-1"], fmt.Sprintf(`{"key":"TST-%d","fields":{"parent":{"key":"TST-1"}}}`, 1000+i))
+		children["TST-1001"] = append(children["TST-1001"], fmt.Sprintf(`{"key":"TST-%d","fields":{"parent":{"key":"TST-1001"}}}`, 2000+i))
+	}
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		jql := r.URL.Query().Get("jql")
+		if jql == "issuekey = TST-1" {
+			testSearchPage(t, w, r, []string{`{"key":"TST-1","fields":{}}`})
+			return
+		}
+		var issues []string
+		for _, key := range testKeyList(jql) {
+			issues = append(issues, children[key]...)
+		}
+		testSearchPage(t, w, r, issues)
+	})
+
+	root, err := testClient.Issue.GetHierarchy("TST-1", nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if root.Rollup.Issues != 121 {
+		t.Errorf("Expected 121 issues, got %d", root.Rollup.Issues)
+	}
+}
+
+func TestIssueService_GetHierarchy_MaxDepth(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		switch jql := r.URL.Query().Get("jql"); jql {
+		case "issuekey = TST-1":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":1,"issues":[{"key":"TST-1","fields":{}}]}`)
+		case "parent in (TST-1) ORDER BY key":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":1,"issues":[{"key":"TST-2","fields":{"parent":{"key":"TST-1"}}}]}`)
+		default:
+			t.Errorf("Unexpected JQL %q", jql)
+		}
+	})
+
+	root, err := testClient.Issue.GetHierarchy("TST-1", &HierarchyOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if root.Rollup.Issues != 2 {
+		t.Errorf("Expected 2 issues, got %d", root.Rollup.Issues)
+	}
+}
+
+func TestIssueService_GetHierarchy_NotFound(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":0,"issues":[]}`)
+	})
+
+	if _, err := testClient.Issue.GetHierarchy("TST-1", nil); err == nil {
+		t.Error("Expected an error for an unknown issue")
+	}
+}
@@ -0,0 +1,259 @@
+// Package issuecopy builds the create requests for copies of issues in another project or instance,
+// and creates the versions and components the copies refer to.
+// It is shared by the backup and migrate packages, which differ only in how they translate
+// issue keys, users and fields; see Converter and ProjectSetup.
+package issuecopy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+)
+
+// SkippedCustomFields are the custom field types which can not be set with the create issue API
+var SkippedCustomFields = map[string]bool{
+	"com.pyxis.greenhopper.jira:gh-sprint":      true,
+	"com.pyxis.greenhopper.jira:gh-lexo-rank":   true,
+	"com.pyxis.greenhopper.jira:gh-global-rank": true,
+}
+
+// Converter translates the references of source issues for the target of the copy
+type Converter struct {
+	// SourceFields are the fields of the source instance by id, for the names and types of custom fields
+	SourceFields map[string]jira.Field
+	// Key returns the key of the copy of a source issue, or false if the issue has no copy
+	Key func(key string) (string, bool)
+	// Text converts the texts of the description and of custom fields. Default: texts are kept.
+	Text func(text string) string
+	// User returns the reference to the target user of a source user, or nil if the user has no counterpart.
+	// An error means the counterpart could not be looked up.
+	User func(user *jira.User) (map[string]string, error)
+	// Field returns the id of the target field of a source custom field. An empty id with true skips the field,
+	// false means the field has no counterpart and is reported as dropped.
+	Field func(id string) (string, bool)
+	// Priority maps priority names. Default: priorities keep their name.
+	Priority func(name string) string
+}
+
+// Fields returns the fields of the create request for the copy of a source issue,
+// and the names of the fields which have a value but can not be set in the target.
+// An error of Converter.User is returned as is.
+func (c *Converter) Fields(source *jira.Issue, projectKey string, issueType *jira.MetaIssueType) (map[string]interface{}, []string, error) {
+	var dropped []string
+	fields := map[string]interface{}{
+		"project":   map[string]string{"key": projectKey},
+		"issuetype": map[string]string{"id": issueType.Id},
+		"summary":   source.Fields.Summary,
+	}
+	put := func(key, name string, value interface{}) {
+		if _, ok := issueType.Fields[key]; ok {
+			fields[key] = value
+		} else {
+			dropped = append(dropped, name)
+		}
+	}
+	putUser := func(key, name string, user *jira.User) error {
+		if user == nil {
+			return nil
+		}
+		ref, err := c.User(user)
+		if err != nil {
+			return err
+		}
+		if ref != nil {
+			put(key, name, ref)
+		} else {
+			dropped = append(dropped, fmt.Sprintf("%s (user %s not found)", name, userName(user)))
+		}
+		return nil
+	}
+	names := func(values []string) []map[string]string {
+		var named []map[string]string
+		for _, value := range values {
+			named = append(named, map[string]string{"name": value})
+		}
+		return named
+	}
+
+	if source.Fields.Description != "" {
+		put("description", "Description", c.text(source.Fields.Description))
+	}
+	if source.Fields.Environment != "" {
+		put("environment", "Environment", source.Fields.Environment)
+	}
+	if source.Fields.Priority != nil {
+		name := source.Fields.Priority.Name
+		if c.Priority != nil {
+			name = c.Priority(name)
+		}
+		put("priority", "Priority", map[string]string{"name": name})
+	}
+	if len(source.Fields.Labels) > 0 {
+		put("labels", "Labels", source.Fields.Labels)
+	}
+	if due := time.Time(source.Fields.Duedate); !due.IsZero() {
+		put("duedate", "Due date", due.Format("2006-01-02"))
+	}
+	if len(source.Fields.Components) > 0 {
+		var values []string
+		for _, component := range source.Fields.Components {
+			values = append(values, component.Name)
+		}
+		put("components", "Component/s", names(values))
+	}
+	if len(source.Fields.FixVersions) > 0 {
+		var values []string
+		for _, version := range source.Fields.FixVersions {
+			values = append(values, version.Name)
+		}
+		put("fixVersions", "Fix Version/s", names(values))
+	}
+	if len(source.Fields.AffectsVersions) > 0 {
+		var values []string
+		for _, version := range source.Fields.AffectsVersions {
+			values = append(values, version.Name)
+		}
+		put("versions", "Affects Version/s", names(values))
+	}
+	if err := putUser("assignee", "Assignee", source.Fields.Assignee); err != nil {
+		return nil, nil, err
+	}
+	if err := putUser("reporter", "Reporter", source.Fields.Reporter); err != nil {
+		return nil, nil, err
+	}
+	if parent := source.Fields.Parent; parent != nil {
+		if key, ok := c.Key(parent.Key); ok {
+			put("parent", "Parent", map[string]string{"key": key})
+		}
+	}
+
+	var custom []string
+	for key := range source.Fields.Unknowns {
+		if strings.HasPrefix(key, "customfield_") {
+			custom = append(custom, key)
+		}
+	}
+	sort.Strings(custom)
+	for _, key := range custom {
+		value := source.Fields.Unknowns[key]
+		field := c.SourceFields[key]
+		if value == nil || SkippedCustomFields[field.Schema.Custom] {
+			continue
+		}
+		name := field.Name
+		if name == "" {
+			name = key
+		}
+		target, ok := c.Field(key)
+		if target == "" {
+			if !ok {
+				dropped = append(dropped, name)
+			}
+			continue
+		}
+		converted, complete, err := c.Value(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !complete {
+			dropped = append(dropped, fmt.Sprintf("%s (user not found)", name))
+			if converted == nil {
+				continue
+			}
+		}
+		put(target, name, converted)
+	}
+	return fields, dropped, nil
+}
+
+// Value converts the value of a custom field for the target: options, versions and groups are referenced by name,
+// users are translated, and texts converted. The second result is false if the value references a user
+// without counterpart; such users are left out of lists, and a single user value is nil.
+func (c *Converter) Value(value interface{}) (interface{}, bool, error) {
+	switch v := value.(type) {
+	case string:
+		return c.text(v), true, nil
+	case []interface{}:
+		values := make([]interface{}, 0, len(v))
+		complete := true
+		for i := range v {
+			converted, ok, err := c.Value(v[i])
+			if err != nil {
+				return nil, false, err
+			}
+			if !ok {
+				complete = false
+				continue
+			}
+			values = append(values, converted)
+		}
+		return values, complete, nil
+	case map[string]interface{}:
+		if option, ok := v["value"]; ok {
+			converted := map[string]interface{}{"value": option}
+			if child, ok := v["child"]; ok {
+				converted["child"], _, _ = c.Value(child)
+			}
+			return converted, true, nil
+		}
+		if user := mapUser(v); user != nil {
+			ref, err := c.User(user)
+			if err != nil || ref == nil {
+				return nil, false, err
+			}
+			return ref, true, nil
+		}
+		if name, ok := v["name"]; ok {
+			return map[string]interface{}{"name": name}, true, nil
+		}
+	}
+	return value, true, nil
+}
+
+func (c *Converter) text(text string) string {
+	if c.Text == nil {
+		return text
+	}
+	return c.Text(text)
+}
+
+// mapUser returns the user of a custom field value, or nil if the value is not a user
+func mapUser(value map[string]interface{}) *jira.User {
+	_, account := value["accountId"]
+	_, email := value["emailAddress"]
+	_, display := value["displayName"]
+	if !account && !email && !display {
+		return nil
+	}
+	user := &jira.User{}
+	user.AccountID, _ = value["accountId"].(string)
+	user.Name, _ = value["name"].(string)
+	user.Key, _ = value["key"].(string)
+	user.EmailAddress, _ = value["emailAddress"].(string)
+	user.DisplayName, _ = value["displayName"].(string)
+	return user
+}
+
+func userName(user *jira.User) string {
+	for _, name := range []string{user.DisplayName, user.Name, user.EmailAddress, user.AccountID} {
+		if name != "" {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// Attribution returns the line added to copied comments and worklogs about their original author and time
+func Attribution(author *jira.User, created *jira.Time) string {
+	name := "unknown"
+	if author != nil && author.DisplayName != "" {
+		name = author.DisplayName
+	}
+	if created == nil {
+		return fmt.Sprintf("_Originally by %s_\n\n", name)
+	}
+	return fmt.Sprintf("_Originally by %s on %s_\n\n", name, time.Time(*created).UTC().Format("2006-01-02 15:04 MST"))
+}
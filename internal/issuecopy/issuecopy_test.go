@@ -0,0 +1,90 @@
+package issuecopy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+)
+
+func testConverter() *Converter {
+	return &Converter{
+		SourceFields: map[string]jira.Field{
+			"customfield_10001": {ID: "customfield_10001", Name: "Reviewers"},
+			"customfield_10002": {ID: "customfield_10002", Name: "Sprint", Schema: jira.FieldSchema{Custom: "com.pyxis.greenhopper.jira:gh-sprint"}},
+			"customfield_10003": {ID: "customfield_10003", Name: "Legacy"},
+		},
+		Key: func(key string) (string, bool) {
+			return map[string]string{"SRC-1": "DST-1"}[key], key == "SRC-1"
+		},
+		User: func(user *jira.User) (map[string]string, error) {
+			switch user.Name {
+			case "jdoe":
+				return map[string]string{"name": "jane"}, nil
+			case "broken":
+				return nil, errors.New("lookup failed")
+			}
+			return nil, nil
+		},
+		Field: func(id string) (string, bool) {
+			if id == "customfield_10003" {
+				return "", false
+			}
+			return id, true
+		},
+	}
+}
+
+func TestConverter_Fields(t *testing.T) {
+	issueType := &jira.MetaIssueType{Id: "3", Fields: map[string]interface{}{"assignee": nil, "parent": nil, "customfield_10001": nil}}
+	source := &jira.Issue{Key: "SRC-2", Fields: &jira.IssueFields{
+		Summary:  "Copy me",
+		Assignee: &jira.User{Name: "jdoe"},
+		Reporter: &jira.User{Name: "gone", DisplayName: "Gone"},
+		Labels:   []string{"x"},
+		Parent:   &jira.Parent{Key: "SRC-1"},
+		Unknowns: map[string]interface{}{
+			"customfield_10001": []interface{}{map[string]interface{}{"name": "jdoe", "displayName": "Jane"}, map[string]interface{}{"name": "gone", "displayName": "Gone"}},
+			"customfield_10002": []interface{}{"sprint"},
+			"customfield_10003": "old",
+		},
+	}}
+
+	converter := testConverter()
+	fields, dropped, err := converter.Fields(source, "DST", issueType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"project":           map[string]string{"key": "DST"},
+		"issuetype":         map[string]string{"id": "3"},
+		"summary":           "Copy me",
+		"assignee":          map[string]string{"name": "jane"},
+		"parent":            map[string]string{"key": "DST-1"},
+		"customfield_10001": []interface{}{map[string]string{"name": "jane"}},
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Expected fields %v, got %v", expected, fields)
+	}
+	expectedDropped := []string{"Labels", "Reporter (user Gone not found)", "Reviewers (user not found)", "Legacy"}
+	if !reflect.DeepEqual(dropped, expectedDropped) {
+		t.Errorf("Expected dropped %v, got %v", expectedDropped, dropped)
+	}
+
+	source.Fields.Unknowns["customfield_10001"] = []interface{}{map[string]interface{}{"name": "broken", "displayName": "Broken"}}
+	if _, _, err := converter.Fields(source, "DST", issueType); err == nil || err.Error() != "lookup failed" {
+		t.Errorf("Expected the error of the user lookup, got %v", err)
+	}
+}
+
+func TestAttribution(t *testing.T) {
+	created := jira.Time(time.Date(2020, 1, 2, 3, 4, 0, 0, time.UTC))
+	if got := Attribution(&jira.User{DisplayName: "Jane"}, &created); got != "_Originally by Jane on 2020-01-02 03:04 UTC_\n\n" {
+		t.Errorf("Unexpected attribution %q", got)
+	}
+	if got := Attribution(nil, nil); got != "_Originally by unknown_\n\n" {
+		t.Errorf("Unexpected attribution %q", got)
+	}
+}
@@ -0,0 +1,110 @@
+package issuecopy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jira "github.com/perolo/jira-client"
+)
+
+// Problem is a part of a source which could not be copied
+type Problem struct {
+	// Key is the key of the source issue, empty for problems of the project configuration
+	Key string
+	// Item names the part which was not copied, e.g. "comment 10010" or "version 1.0"
+	Item    string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Key == "" {
+		return fmt.Sprintf("%s: %s", p.Item, p.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", p.Key, p.Item, p.Message)
+}
+
+// ProjectSetup creates the versions and components of a source project which are missing in a target project.
+// Versions and components are matched by name, ignoring case.
+type ProjectSetup struct {
+	Client  *jira.Client
+	Project *jira.Project
+	// Prefix is put in front of the names in Versions, Components and Problems, e.g. "KEY/"
+	Prefix string
+	// Lead returns the reference to the target user of a component lead, like Converter.User.
+	// Default: component leads are not copied.
+	Lead func(user *jira.User) (map[string]string, error)
+
+	// Versions and Components are the names of the versions and components which were created
+	Versions   []string
+	Components []string
+	// Problems lists the versions, components and leads which could not be created
+	Problems []Problem
+}
+
+// CreateVersionsWithContext creates the versions which do not exist in the target project
+func (p *ProjectSetup) CreateVersionsWithContext(ctx context.Context, versions []jira.Version) {
+	existing := make(map[string]bool)
+	for _, version := range p.Project.Versions {
+		existing[strings.ToLower(version.Name)] = true
+	}
+	projectID, _ := strconv.Atoi(p.Project.ID)
+	for _, version := range versions {
+		if existing[strings.ToLower(version.Name)] {
+			continue
+		}
+		_, _, err := p.Client.Version.CreateWithContext(ctx, &jira.Version{
+			Name:        version.Name,
+			Description: version.Description,
+			Archived:    version.Archived,
+			Released:    version.Released,
+			ReleaseDate: version.ReleaseDate,
+			StartDate:   version.StartDate,
+			ProjectID:   projectID,
+		})
+		if err != nil {
+			p.problem("version "+p.Prefix+version.Name, err.Error())
+			continue
+		}
+		p.Versions = append(p.Versions, p.Prefix+version.Name)
+	}
+}
+
+// CreateComponentsWithContext creates the components which do not exist in the target project.
+// Leads are set by account id on Jira Cloud and by name on Jira Server; leads without a target user are reported as problems.
+func (p *ProjectSetup) CreateComponentsWithContext(ctx context.Context, components []jira.ProjectComponent) {
+	existing := make(map[string]bool)
+	for _, component := range p.Project.Components {
+		existing[strings.ToLower(component.Name)] = true
+	}
+	for _, component := range components {
+		if existing[strings.ToLower(component.Name)] {
+			continue
+		}
+		name := p.Prefix + component.Name
+		create := &jira.CreateComponentOptions{Name: component.Name, Description: component.Description, Project: p.Project.Key}
+		if source := component.Lead; p.Lead != nil && (source.AccountID != "" || source.Name != "" || source.EmailAddress != "") {
+			lead, err := p.Lead(&source)
+			switch {
+			case err != nil:
+				p.problem("lead of component "+name, err.Error())
+			case lead["accountId"] != "":
+				create.Lead = &jira.User{AccountID: lead["accountId"]}
+			case lead["name"] != "":
+				create.LeadUserName = lead["name"]
+			default:
+				p.problem("lead of component "+name, "no matching user in the target instance")
+			}
+		}
+		if _, _, err := p.Client.Component.CreateWithContext(ctx, create); err != nil {
+			p.problem("component "+name, err.Error())
+			continue
+		}
+		p.Components = append(p.Components, name)
+	}
+}
+
+func (p *ProjectSetup) problem(item, message string) {
+	p.Problems = append(p.Problems, Problem{Item: item, Message: message})
+}
@@ -0,0 +1,89 @@
+package issuecopy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	jira "github.com/perolo/jira-client"
+)
+
+func TestProjectSetup(t *testing.T) {
+	var created []string
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/rest/api/2/version", func(w http.ResponseWriter, r *http.Request) {
+		var version jira.Version
+		_ = json.NewDecoder(r.Body).Decode(&version)
+		if version.Name == "2.0" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		created = append(created, fmt.Sprintf("version %s project %d", version.Name, version.ProjectID))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"id": "1"}`)
+	})
+	mux.HandleFunc("/rest/api/2/component", func(w http.ResponseWriter, r *http.Request) {
+		var component jira.CreateComponentOptions
+		_ = json.NewDecoder(r.Body).Decode(&component)
+		lead := component.LeadUserName
+		if component.Lead != nil {
+			lead = component.Lead.AccountID
+		}
+		created = append(created, fmt.Sprintf("component %s lead %q", component.Name, lead))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprint(w, `{"id": "1"}`)
+	})
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	setup := &ProjectSetup{
+		Client: client,
+		Project: &jira.Project{ID: "7", Key: "DST",
+			Versions:   []jira.Version{{Name: "1.0"}},
+			Components: []jira.ProjectComponent{{Name: "core"}}},
+		Prefix: "DST/",
+		Lead: func(user *jira.User) (map[string]string, error) {
+			switch user.Name {
+			case "cloud":
+				return map[string]string{"accountId": "5b10ac8d82e05b22cc7d4ef5"}, nil
+			case "server":
+				return map[string]string{"name": "jane"}, nil
+			}
+			return nil, nil
+		},
+	}
+	setup.CreateVersionsWithContext(context.Background(), []jira.Version{{Name: "1.0"}, {Name: "1.1"}, {Name: "2.0"}})
+	setup.CreateComponentsWithContext(context.Background(), []jira.ProjectComponent{
+		{Name: "Core"},
+		{Name: "Web", Lead: jira.User{Name: "cloud"}},
+		{Name: "API", Lead: jira.User{Name: "server"}},
+		{Name: "Docs", Lead: jira.User{Name: "ghost"}},
+		{Name: "Build"},
+	})
+
+	expected := []string{
+		"version 1.1 project 7",
+		`component Web lead "5b10ac8d82e05b22cc7d4ef5"`,
+		`component API lead "jane"`,
+		`component Docs lead ""`,
+		`component Build lead ""`,
+	}
+	if !reflect.DeepEqual(created, expected) {
+		t.Errorf("Expected requests %q, got %q", expected, created)
+	}
+	if !reflect.DeepEqual(setup.Versions, []string{"DST/1.1"}) || len(setup.Components) != 4 {
+		t.Errorf("Unexpected created versions %v and components %v", setup.Versions, setup.Components)
+	}
+	if len(setup.Problems) != 2 || setup.Problems[0].Item != "version DST/2.0" ||
+		setup.Problems[1].String() != "lead of component DST/Docs: no matching user in the target instance" {
+		t.Errorf("Unexpected problems %v", setup.Problems)
+	}
+}
@@ -1,13 +1,11 @@
 package jira
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"mime/multipart"
 	"net/http"
 	"net/url"
 	"reflect"
@@ -74,6 +72,9 @@ type ChangelogHistory struct {
 
 // Changelog reflects the change log of an issue
 type Changelog struct {
+	// Total is the number of histories of the issue. Issues of a search may include only the latest histories,
+	// see IssueService.GetAllChangelog.
+	Total     int                `json:"total,omitempty"`
 	Histories []ChangelogHistory `json:"histories,omitempty"`
 }
 
@@ -83,7 +84,7 @@ type Attachment struct {
 	ID        string `json:"id,omitempty" structs:"id,omitempty"`
 	Filename  string `json:"filename,omitempty" structs:"filename,omitempty"`
 	Author    *User  `json:"author,omitempty" structs:"author,omitempty"`
-	Created   string `json:"created,omitempty" structs:"created,omitempty"`
+	Created   *Time  `json:"created,omitempty" structs:"created,omitempty"`
 	Size      int    `json:"size,omitempty" structs:"size,omitempty"`
 	MimeType  string `json:"mimeType,omitempty" structs:"mimeType,omitempty"`
 	Content   string `json:"content,omitempty" structs:"content,omitempty"`
@@ -91,14 +92,19 @@ type Attachment struct {
 }
 
 // Epic represents the epic to which an issue is associated
-// Not that this struct does not process the returned "color" value
 type Epic struct {
-	ID      int    `json:"id" structs:"id"`
-	Key     string `json:"key" structs:"key"`
-	Self    string `json:"self" structs:"self"`
-	Name    string `json:"name" structs:"name"`
-	Summary string `json:"summary" structs:"summary"`
-	Done    bool   `json:"done" structs:"done"`
+	ID      int        `json:"id" structs:"id"`
+	Key     string     `json:"key" structs:"key"`
+	Self    string     `json:"self" structs:"self"`
+	Name    string     `json:"name" structs:"name"`
+	Summary string     `json:"summary" structs:"summary"`
+	Color   *EpicColor `json:"color,omitempty" structs:"color,omitempty"`
+	Done    bool       `json:"done" structs:"done"`
+}
+
+// EpicColor is the color of an epic, e.g. "color_4"
+type EpicColor struct {
+	Key string `json:"key" structs:"key"`
 }
 
 // IssueFields represents single fields of a Jira issue.
@@ -409,14 +415,12 @@ type Option struct {
 }
 
 // UnmarshalJSON will transform the Jira time into a time.Time
-// during the transformation of the Jira JSON response
+// during the transformation of the Jira JSON response.
+// All timestamp formats of Jira are accepted, see ParseTime.
 func (t *Time) UnmarshalJSON(b []byte) error {
 	// Ignore null, like in the main JSON package.
-	if string(b) == "null" {
-		return nil
-	}
-	ti, err := time.Parse("\"2006-01-02T15:04:05.999-0700\"", string(b))
-	if err != nil {
+	ti, ok, err := unmarshalTime(b)
+	if err != nil || !ok {
 		return err
 	}
 	*t = Time(ti)
@@ -430,14 +434,12 @@ func (t Time) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON will transform the Jira date into a time.Time
-// during the transformation of the Jira JSON response
+// during the transformation of the Jira JSON response.
+// Full timestamps are accepted as well.
 func (t *Date) UnmarshalJSON(b []byte) error {
 	// Ignore null, like in the main JSON package.
-	if string(b) == "null" {
-		return nil
-	}
-	ti, err := time.Parse("\"2006-01-02\"", string(b))
-	if err != nil {
+	ti, ok, err := unmarshalTime(b)
+	if err != nil || !ok {
 		return err
 	}
 	*t = Date(ti)
@@ -472,7 +474,7 @@ type WorklogRecord struct {
 	Updated          *Time            `json:"updated,omitempty" structs:"updated,omitempty"`
 	Started          *Time            `json:"started,omitempty" structs:"started,omitempty"`
 	TimeSpent        string           `json:"timeSpent,omitempty" structs:"timeSpent,omitempty"`
-	TimeSpentSeconds int              `json:"timeSpentSeconds,omitempty" structs:"timeSpentSeconds,omitempty"`
+	TimeSpentSeconds Duration         `json:"timeSpentSeconds,omitempty" structs:"timeSpentSeconds,omitempty"`
 	ID               string           `json:"id,omitempty" structs:"id,omitempty"`
 	IssueID          string           `json:"issueId,omitempty" structs:"issueId,omitempty"`
 	Properties       []EntityProperty `json:"properties,omitempty"`
@@ -485,12 +487,12 @@ type EntityProperty struct {
 
 // TimeTracking represents the timetracking fields of a Jira issue.
 type TimeTracking struct {
-	OriginalEstimate         string `json:"originalEstimate,omitempty" structs:"originalEstimate,omitempty"`
-	RemainingEstimate        string `json:"remainingEstimate,omitempty" structs:"remainingEstimate,omitempty"`
-	TimeSpent                string `json:"timeSpent,omitempty" structs:"timeSpent,omitempty"`
-	OriginalEstimateSeconds  int    `json:"originalEstimateSeconds,omitempty" structs:"originalEstimateSeconds,omitempty"`
-	RemainingEstimateSeconds int    `json:"remainingEstimateSeconds,omitempty" structs:"remainingEstimateSeconds,omitempty"`
-	TimeSpentSeconds         int    `json:"timeSpentSeconds,omitempty" structs:"timeSpentSeconds,omitempty"`
+	OriginalEstimate         string   `json:"originalEstimate,omitempty" structs:"originalEstimate,omitempty"`
+	RemainingEstimate        string   `json:"remainingEstimate,omitempty" structs:"remainingEstimate,omitempty"`
+	TimeSpent                string   `json:"timeSpent,omitempty" structs:"timeSpent,omitempty"`
+	OriginalEstimateSeconds  Duration `json:"originalEstimateSeconds,omitempty" structs:"originalEstimateSeconds,omitempty"`
+	RemainingEstimateSeconds Duration `json:"remainingEstimateSeconds,omitempty" structs:"remainingEstimateSeconds,omitempty"`
+	TimeSpentSeconds         Duration `json:"timeSpentSeconds,omitempty" structs:"timeSpentSeconds,omitempty"`
 }
 
 // Subtasks represents all issues of a parent issue.
@@ -523,6 +525,9 @@ type IssueLinkType struct {
 
 // Comments represents a list of Comment.
 type Comments struct {
+	// Total is the number of comments of the issue. Issues of a search may include only some of them,
+	// see IssueService.GetAllComments.
+	Total    int        `json:"total,omitempty" structs:"total,omitempty"`
 	Comments []*Comment `json:"comments,omitempty" structs:"comments,omitempty"`
 }
 
@@ -534,8 +539,8 @@ type Comment struct {
 	Author       User              `json:"author,omitempty" structs:"author,omitempty"`
 	Body         string            `json:"body,omitempty" structs:"body,omitempty"`
 	UpdateAuthor User              `json:"updateAuthor,omitempty" structs:"updateAuthor,omitempty"`
-	Updated      string            `json:"updated,omitempty" structs:"updated,omitempty"`
-	Created      string            `json:"created,omitempty" structs:"created,omitempty"`
+	Updated      *Time             `json:"updated,omitempty" structs:"updated,omitempty"`
+	Created      *Time             `json:"created,omitempty" structs:"created,omitempty"`
 	Visibility   CommentVisibility `json:"visibility,omitempty" structs:"visibility,omitempty"`
 }
 
@@ -586,6 +591,14 @@ type SearchOptions struct {
 	Fields []string
 	// ValidateQuery: The validateQuery param offers control over whether to validate and how strictly to treat the validation. Default: strict.
 	ValidateQuery string `url:"validateQuery,omitempty"`
+	// Expands: Sections to expand in addition to Expand, e.g. []string{"changelog", "names"}
+	Expands []string `url:"-"`
+	// Properties: Issue properties to return, e.g. []string{"myapp.rank"}
+	Properties []string `url:"-"`
+	// FieldsByKeys: Reference the fields by their keys instead of their ids
+	FieldsByKeys bool `url:"-"`
+	// MaxURLLength: The length of the request URL above which the search is sent as POST request. Default: 4000.
+	MaxURLLength int `url:"-"`
 }
 
 // SearchResult is only a small wrapper around the Search (with JQL) method
@@ -733,45 +746,10 @@ func (s *IssueService) DownloadAttachment(attachmentID string) (*Response, error
 	return s.DownloadAttachmentWithContext(context.Background(), attachmentID)
 }
 
-// PostAttachmentWithContext uploads r (io.Reader) as an attachment to a given issueID
+// PostAttachmentWithContext uploads r (io.Reader) as an attachment to a given issueID.
+// r is streamed to Jira, see PostAttachmentsWithContext.
 func (s *IssueService) PostAttachmentWithContext(ctx context.Context, issueID string, r io.Reader, attachmentName string) (*[]Attachment, *Response, error) {
-	apiEndpoint := fmt.Sprintf("rest/api/2/issue/%s/attachments", issueID)
-
-	b := new(bytes.Buffer)
-	writer := multipart.NewWriter(b)
-
-	fw, err := writer.CreateFormFile("file", attachmentName)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	if r != nil {
-		// Copy the file
-		if _, err = io.Copy(fw, r); err != nil {
-			return nil, nil, err
-		}
-	}
-	err = writer.Close()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	req, err := s.client.NewMultiPartRequestWithContext(ctx, "POST", apiEndpoint, b)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-
-	// PostAttachment response returns a JSON array (as multiple attachments can be posted)
-	attachment := new([]Attachment)
-	resp, err := s.client.Do(req, attachment)
-	if err != nil {
-		jerr := NewJiraError(resp, err)
-		return nil, resp, jerr
-	}
-
-	return attachment, resp, nil
+	return s.PostAttachmentsWithContext(ctx, issueID, []AttachmentFile{{Name: attachmentName, Reader: r}}, nil)
 }
 
 // PostAttachment wraps PostAttachmentWithContext using the background context.
@@ -1005,6 +983,72 @@ func (s *IssueService) GetComments(issue string, options *SearchOptions) ([]Comm
 	return v.Comments, resp, err
 }
 
+// GetAllCommentsWithContext returns all comments of an issue, loading every page of the comment API.
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/issue-getComments
+func (s *IssueService) GetAllCommentsWithContext(ctx context.Context, issueID string) ([]Comment, error) {
+	var comments []Comment
+	for {
+		u := fmt.Sprintf("rest/api/2/issue/%s/comment?startAt=%d&maxResults=100", issueID, len(comments))
+		req, err := s.client.NewRequestWithContext(ctx, "GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+		page := new(GetCommentResponse)
+		resp, err := s.client.Do(req, page)
+		if err != nil {
+			return nil, NewJiraError(resp, err)
+		}
+		comments = append(comments, page.Comments...)
+		if len(page.Comments) == 0 || len(comments) >= page.Total {
+			return comments, nil
+		}
+	}
+}
+
+// GetAllComments wraps GetAllCommentsWithContext using the background context.
+func (s *IssueService) GetAllComments(issueID string) ([]Comment, error) {
+	return s.GetAllCommentsWithContext(context.Background(), issueID)
+}
+
+// getChangelogResponse is a page of the changelog API
+type getChangelogResponse struct {
+	StartAt    int                `json:"startAt"`
+	MaxResults int                `json:"maxResults"`
+	Total      int                `json:"total"`
+	Values     []ChangelogHistory `json:"values"`
+}
+
+// GetAllChangelogWithContext returns the complete changelog of an issue, loading every page of the changelog API.
+// The changelog expanded by a search includes only the latest 100 histories on Jira Cloud.
+// The changelog API is not available on Jira Server, where the expanded changelog is complete.
+//
+// Jira API docs: https://developer.atlassian.com/cloud/jira/platform/rest/v2/api-group-issues/#api-rest-api-2-issue-issueidorkey-changelog-get
+func (s *IssueService) GetAllChangelogWithContext(ctx context.Context, issueID string) ([]ChangelogHistory, error) {
+	var histories []ChangelogHistory
+	for {
+		u := fmt.Sprintf("rest/api/2/issue/%s/changelog?startAt=%d&maxResults=100", issueID, len(histories))
+		req, err := s.client.NewRequestWithContext(ctx, "GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+		page := new(getChangelogResponse)
+		resp, err := s.client.Do(req, page)
+		if err != nil {
+			return nil, NewJiraError(resp, err)
+		}
+		histories = append(histories, page.Values...)
+		if len(page.Values) == 0 || len(histories) >= page.Total {
+			return histories, nil
+		}
+	}
+}
+
+// GetAllChangelog wraps GetAllChangelogWithContext using the background context.
+func (s *IssueService) GetAllChangelog(issueID string) ([]ChangelogHistory, error) {
+	return s.GetAllChangelogWithContext(context.Background(), issueID)
+}
+
 // AddCommentWithContext adds a new comment to issueID.
 //
 // Jira API docs: https://docs.atlassian.com/jira/REST/latest/#api/2/issue-addComment
@@ -1173,7 +1217,9 @@ func (s *IssueService) AddLink(issueLink *IssueLink) (*Response, error) {
 	return s.AddLinkWithContext(context.Background(), issueLink)
 }
 
-// SearchWithContext will search for tickets according to the jql
+// SearchWithContext will search for tickets according to the jql.
+// If the request URL would be longer than options.MaxURLLength (default 4000), e.g. for a long list of issue keys,
+// the search is sent as POST request (see SearchPostWithContext).
 //
 // Jira API docs: https://developer.atlassian.com/jiradev/jira-apis/jira-rest-apis/jira-rest-api-tutorials/jira-rest-api-example-query-issues
 func (s *IssueService) SearchWithContext(ctx context.Context, jql string, options *SearchOptions) (*SearchResult, *Response, error) {
@@ -1193,8 +1239,8 @@ func (s *IssueService) SearchWithContext(ctx context.Context, jql string, option
 		if options.MaxResults != 0 {
 			uv.Add("maxResults", strconv.Itoa(options.MaxResults))
 		}
-		if options.Expand != "" {
-			uv.Add("expand", options.Expand)
+		if expand := options.expand(); len(expand) > 0 {
+			uv.Add("expand", strings.Join(expand, ","))
 		}
 		if strings.Join(options.Fields, ",") != "" {
 			uv.Add("fields", strings.Join(options.Fields, ","))
@@ -1202,9 +1248,22 @@ func (s *IssueService) SearchWithContext(ctx context.Context, jql string, option
 		if options.ValidateQuery != "" {
 			uv.Add("validateQuery", options.ValidateQuery)
 		}
+		if len(options.Properties) > 0 {
+			uv.Add("properties", strings.Join(options.Properties, ","))
+		}
+		if options.FieldsByKeys {
+			uv.Add("fieldsByKeys", "true")
+		}
 	}
 
 	u.RawQuery = uv.Encode()
+	maxURLLength := defaultMaxSearchURLLength
+	if options != nil && options.MaxURLLength > 0 {
+		maxURLLength = options.MaxURLLength
+	}
+	if len(u.String()) > maxURLLength {
+		return s.SearchPostWithContext(ctx, jql, options)
+	}
 
 	req, err := s.client.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
@@ -1603,14 +1662,14 @@ func (s *IssueService) UpdateAssignee(issueID string, assignee *User) (*Response
 	return s.UpdateAssigneeWithContext(context.Background(), issueID, assignee)
 }
 
+// CreatedTime parses the creation time of the history entry, see ParseTime.
+// An empty or null creation time is the zero time.
 func (c ChangelogHistory) CreatedTime() (time.Time, error) {
-	var t time.Time
 	// Ignore null
-	if c.Created == "null" {
-		return t, nil
+	if c.Created == "null" || c.Created == "" {
+		return time.Time{}, nil
 	}
-	t, err := time.Parse("2006-01-02T15:04:05.999-0700", c.Created)
-	return t, err
+	return ParseTime(c.Created)
 }
 
 // GetRemoteLinksWithContext gets remote issue links on the issue.
@@ -2055,3 +2055,51 @@ func TestTime_MarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestIssueService_GetAllComments(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/issue/10000/comment", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("startAt") {
+		case "0":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":2,"total":3,"comments":[{"id":"1"},{"id":"2"}]}`)
+		case "2":
+			fmt.Fprint(w, `{"startAt":2,"maxResults":2,"total":3,"comments":[{"id":"3"}]}`)
+		default:
+			t.Errorf("Unexpected startAt %s", r.URL.Query().Get("startAt"))
+		}
+	})
+
+	comments, err := testClient.Issue.GetAllComments("10000")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(comments) != 3 || comments[2].ID != "3" {
+		t.Errorf("Expected 3 comments, got %+v", comments)
+	}
+}
+
+func TestIssueService_GetAllChangelog(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/issue/10000/changelog", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("startAt") {
+		case "0":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":2,"total":3,"values":[{"id":"1"},{"id":"2"}]}`)
+		case "2":
+			fmt.Fprint(w, `{"startAt":2,"maxResults":2,"total":3,"values":[{"id":"3","items":[{"field":"status","toString":"Done"}]}]}`)
+		default:
+			t.Errorf("Unexpected startAt %s", r.URL.Query().Get("startAt"))
+		}
+	})
+
+	histories, err := testClient.Issue.GetAllChangelog("10000")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(histories) != 3 || histories[2].Id != "3" || histories[2].Items[0].ToString != "Done" {
+		t.Errorf("Expected 3 histories, got %+v", histories)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"reflect"
 	"sort"
 	"strings"
@@ -44,6 +45,7 @@ type Client struct {
 	Project          *ProjectService
 	Board            *BoardService
 	Sprint           *SprintService
+	Epic             *EpicService
 	User             *UserService
 	Group            *GroupService
 	ProField         *ProfieldService
@@ -97,6 +99,7 @@ func NewClient(httpClient httpClient, baseURL string) (*Client, error) {
 	c.Project = &ProjectService{client: c}
 	c.Board = &BoardService{client: c}
 	c.Sprint = &SprintService{client: c}
+	c.Epic = &EpicService{client: c}
 	c.User = &UserService{client: c}
 	c.Group = &GroupService{client: c}
 	c.ProField = &ProfieldService{client: c}
@@ -268,6 +271,22 @@ func addOptions(s string, opt interface{}) (string, error) {
 // A relative URL can be provided in urlStr, in which case it is resolved relative to the baseURL of the Client.
 // If specified, the value pointed to by buf is a multipart form.
 func (c *Client) NewMultiPartRequestWithContext(ctx context.Context, method, urlStr string, buf *bytes.Buffer) (*http.Request, error) {
+	var body io.Reader
+	if buf != nil {
+		body = buf
+	}
+	return c.NewStreamRequestWithContext(ctx, method, urlStr, body)
+}
+
+// NewMultiPartRequest wraps NewMultiPartRequestWithContext using the background context.
+func (c *Client) NewMultiPartRequest(method, urlStr string, buf *bytes.Buffer) (*http.Request, error) {
+	return c.NewMultiPartRequestWithContext(context.Background(), method, urlStr, buf)
+}
+
+// NewStreamRequestWithContext creates an API request with a body which is streamed to Jira as it is read,
+// e.g. a multipart form written through an io.Pipe. The Content-Type has to be set by the caller.
+// A relative URL can be provided in urlStr, in which case it is resolved relative to the baseURL of the Client.
+func (c *Client) NewStreamRequestWithContext(ctx context.Context, method, urlStr string, body io.Reader) (*http.Request, error) {
 	rel, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, err
@@ -277,7 +296,7 @@ func (c *Client) NewMultiPartRequestWithContext(ctx context.Context, method, url
 
 	u := c.baseURL.ResolveReference(rel)
 
-	req, err := newRequestWithContext(ctx, method, u.String(), buf)
+	req, err := newRequestWithContext(ctx, method, u.String(), body)
 	if err != nil {
 		return nil, err
 	}
@@ -303,9 +322,9 @@ func (c *Client) NewMultiPartRequestWithContext(ctx context.Context, method, url
 	return req, nil
 }
 
-// NewMultiPartRequest wraps NewMultiPartRequestWithContext using the background context.
-func (c *Client) NewMultiPartRequest(method, urlStr string, buf *bytes.Buffer) (*http.Request, error) {
-	return c.NewMultiPartRequestWithContext(context.Background(), method, urlStr, buf)
+// NewStreamRequest wraps NewStreamRequestWithContext using the background context.
+func (c *Client) NewStreamRequest(method, urlStr string, body io.Reader) (*http.Request, error) {
+	return c.NewStreamRequestWithContext(context.Background(), method, urlStr, body)
 }
 
 // Do sends an API request and returns the API response.
@@ -360,6 +379,7 @@ func (c *Client) Do2(req *http.Request) ([]byte, error) {
 	return body, err
 }
 
+// Save sends the request and streams the response body into the file filename.
 func (c *Client) Save(req *http.Request, filename string) error {
 	httpResp, err := c.client.Do(req)
 	if err != nil {
@@ -373,15 +393,16 @@ func (c *Client) Save(req *http.Request, filename string) error {
 		return err
 	}
 
-	// Open a NewDecoder and defer closing the reader only if there is a provided interface to decode to
 	defer CleanupH(httpResp)
-	body, err := ioutil.ReadAll(httpResp.Body)
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return err
 	}
-	err = ioutil.WriteFile(filename, body, 0600)
-
-	return err
+	if _, err = io.Copy(file, httpResp.Body); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
 }
 
 // CheckResponse checks the API response for errors, and returns them if present.
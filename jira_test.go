@@ -121,6 +121,9 @@ func TestNewClient_WithServices(t *testing.T) {
 	if c.Sprint == nil {
 		t.Error("No SprintService provided")
 	}
+	if c.Epic == nil {
+		t.Error("No EpicService provided")
+	}
 	if c.User == nil {
 		t.Error("No UserService provided")
 	}
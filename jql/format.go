@@ -0,0 +1,132 @@
+package jql
+
+import (
+	"strings"
+)
+
+// String returns the statement as canonical JQL on one line:
+// keywords in upper case, field names and values quoted only where necessary and parentheses only where needed.
+func (s *Statement) String() string {
+	return s.format("", "")
+}
+
+// Pretty returns the statement as JQL with every operand of AND and OR on its own line,
+// nested groups indented by indent.
+func (s *Statement) Pretty(indent string) string {
+	return s.format("\n", indent)
+}
+
+func (s *Statement) format(newline, indent string) string {
+	var b strings.Builder
+	if s.Where != nil {
+		formatExpr(&b, s.Where, "", newline, indent, false)
+	}
+	if len(s.OrderBy) > 0 {
+		if s.Where != nil {
+			if newline != "" {
+				b.WriteString(newline)
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString("ORDER BY ")
+		for i, sort := range s.OrderBy {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(QuoteField(sort.Field.Name))
+			if sort.Direction != "" {
+				b.WriteString(" " + string(sort.Direction))
+			}
+		}
+	}
+	return b.String()
+}
+
+// formatExpr writes an expression. prefix is the indentation of the current line in pretty mode.
+// parens is set if the expression has to be put in parentheses because of the precedence of the parent.
+func formatExpr(b *strings.Builder, expr Expr, prefix, newline, indent string, parens bool) {
+	switch e := expr.(type) {
+	case *LogicalExpr:
+		inner := prefix
+		if parens {
+			b.WriteByte('(')
+			if newline != "" {
+				inner = prefix + indent
+				b.WriteString(newline + inner)
+			}
+		}
+		for i, operand := range e.Operands {
+			if i > 0 {
+				if newline != "" {
+					b.WriteString(newline + inner + e.Operator + " ")
+				} else {
+					b.WriteString(" " + e.Operator + " ")
+				}
+			}
+			// AND binds stronger than OR: only OR inside AND needs parentheses
+			child, ok := operand.(*LogicalExpr)
+			formatExpr(b, operand, inner, newline, indent, ok && e.Operator == "AND" && child.Operator == "OR")
+		}
+		if parens {
+			if newline != "" {
+				b.WriteString(newline + prefix)
+			}
+			b.WriteByte(')')
+		}
+	case *NotExpr:
+		b.WriteString("NOT ")
+		_, logical := e.X.(*LogicalExpr)
+		formatExpr(b, e.X, prefix, newline, indent, logical)
+	case *TermExpr:
+		b.WriteString(formatTerm(e))
+	}
+}
+
+func formatTerm(t *TermExpr) string {
+	parts := []string{QuoteField(t.Field.Name), string(t.Operator)}
+	if t.Value != nil {
+		parts = append(parts, formatValue(t.Value))
+	}
+	for _, p := range t.Predicates {
+		if p.Keyword == "DURING" && len(p.Values) == 2 {
+			parts = append(parts, "DURING ("+formatValue(p.Values[0])+", "+formatValue(p.Values[1])+")")
+			continue
+		}
+		parts = append(parts, p.Keyword)
+		for _, v := range p.Values {
+			parts = append(parts, formatValue(v))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatValue(v ValueNode) string {
+	switch value := v.(type) {
+	case *Literal:
+		return formatLiteral(value)
+	case *List:
+		values := make([]string, len(value.Values))
+		for i := range value.Values {
+			values[i] = formatValue(value.Values[i])
+		}
+		return "(" + strings.Join(values, ", ") + ")"
+	case *FunctionCall:
+		args := make([]string, len(value.Args))
+		for i := range value.Args {
+			args[i] = formatLiteral(value.Args[i])
+		}
+		return value.Name + "(" + strings.Join(args, ", ") + ")"
+	}
+	return ""
+}
+
+func formatLiteral(l *Literal) string {
+	if l.Empty {
+		return "EMPTY"
+	}
+	if l.Quoted || reservedWords[strings.ToLower(l.Text)] {
+		return Quote(l.Text)
+	}
+	return l.Text
+}
@@ -0,0 +1,656 @@
+// Package jql builds Jira Query Language (JQL) queries from typed clauses,
+// so values and field names are always escaped correctly.
+//
+//	query := jql.Where(jql.And(
+//		jql.Field("project").Eq(jql.String("TST")),
+//		jql.Field("status").NotIn(jql.Strings("Done", "Closed")...),
+//		jql.CustomField("Story Points").Gt(jql.Int(3)),
+//	)).OrderBy(jql.Field("created"), jql.Desc)
+//
+// Custom fields can be referenced by their display name; Build resolves them to cf[id] with a FieldResolver.
+package jql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+)
+
+// reservedWords are the words which can not be used unquoted as field names in JQL
+var reservedWords = map[string]bool{
+	"a": true, "an": true, "abort": true, "access": true, "add": true, "after": true, "alias": true, "all": true,
+	"alter": true, "and": true, "any": true, "are": true, "as": true, "asc": true, "at": true, "audit": true,
+	"avg": true, "be": true, "before": true, "begin": true, "between": true, "boolean": true, "break": true,
+	"but": true, "by": true, "byte": true, "catch": true, "cf": true, "changed": true, "char": true,
+	"character": true, "check": true, "checkpoint": true, "collate": true, "collation": true, "column": true,
+	"commit": true, "connect": true, "continue": true, "count": true, "create": true, "current": true,
+	"date": true, "decimal": true, "declare": true, "decrement": true, "default": true, "defaults": true,
+	"define": true, "delete": true, "delimiter": true, "desc": true, "difference": true, "distinct": true,
+	"divide": true, "do": true, "double": true, "drop": true, "during": true, "else": true, "empty": true,
+	"encoding": true, "end": true, "equals": true, "escape": true, "exclusive": true, "exec": true,
+	"execute": true, "exists": true, "explain": true, "false": true, "fetch": true, "file": true, "field": true,
+	"first": true, "float": true, "for": true, "from": true, "function": true, "go": true, "goto": true,
+	"grant": true, "greater": true, "group": true, "having": true, "identified": true, "if": true,
+	"immediate": true, "in": true, "increment": true, "index": true, "initial": true, "inner": true,
+	"inout": true, "input": true, "insert": true, "int": true, "integer": true, "intersect": true,
+	"intersection": true, "into": true, "is": true, "isempty": true, "isnull": true, "join": true, "last": true,
+	"left": true, "less": true, "like": true, "limit": true, "lock": true, "long": true, "max": true,
+	"min": true, "minus": true, "mode": true, "modify": true, "modulo": true, "more": true, "multiply": true,
+	"next": true, "noaudit": true, "not": true, "notin": true, "nowait": true, "null": true, "number": true,
+	"object": true, "of": true, "on": true, "option": true, "or": true, "order": true, "outer": true,
+	"output": true, "power": true, "previous": true, "prior": true, "privileges": true, "public": true,
+	"raise": true, "raw": true, "remainder": true, "rename": true, "resource": true, "return": true,
+	"returns": true, "revoke": true, "right": true, "row": true, "rowid": true, "rownum": true, "rows": true,
+	"select": true, "session": true, "set": true, "share": true, "size": true, "sqrt": true, "start": true,
+	"strict": true, "string": true, "subtract": true, "sum": true, "synonym": true, "table": true, "then": true,
+	"to": true, "trans": true, "transaction": true, "trigger": true, "true": true, "uid": true, "union": true,
+	"unique": true, "update": true, "user": true, "validate": true, "values": true, "view": true, "was": true,
+	"when": true, "whenever": true, "where": true, "while": true, "with": true,
+}
+
+// plainFieldName matches field names which do not need quotes, e.g. status, issuekey or cf[10001]
+var plainFieldName = regexp.MustCompile(`^(?:[A-Za-z_][A-Za-z0-9_.]*|cf\[\d+\])$`)
+
+// Quote returns s as a quoted JQL string, escaping quotes, backslashes and line breaks
+func Quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// QuoteField returns the name of a field as it has to be written in JQL: unchanged if possible, otherwise quoted
+func QuoteField(name string) string {
+	if plainFieldName.MatchString(name) && !reservedWords[strings.ToLower(name)] {
+		return name
+	}
+	return Quote(name)
+}
+
+// Value is the value of a clause: a string, a number, a date, a function call or EMPTY
+type Value interface {
+	jql() string
+}
+
+// literal is a value which is written to the query as is
+type literal string
+
+func (l literal) jql() string {
+	return string(l)
+}
+
+// Empty is the EMPTY value, matching fields without value
+var Empty Value = literal("EMPTY")
+
+// String returns a quoted string value
+func String(s string) Value {
+	return literal(Quote(s))
+}
+
+// Strings returns quoted string values, e.g. for In
+func Strings(s ...string) []Value {
+	values := make([]Value, len(s))
+	for i := range s {
+		values[i] = String(s[i])
+	}
+	return values
+}
+
+// Int returns a number value
+func Int(n int) Value {
+	return literal(strconv.Itoa(n))
+}
+
+// Float returns a number value
+func Float(f float64) Value {
+	return literal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// Date returns the day of t in the time zone loc, see jira.FormatJQLDate
+func Date(t time.Time, loc *time.Location) Value {
+	return literal(jira.FormatJQLDate(t, loc))
+}
+
+// DateTime returns the timestamp t in the time zone loc, see jira.FormatJQLTime.
+// loc should be the time zone of the user running the query.
+func DateTime(t time.Time, loc *time.Location) Value {
+	return literal(jira.FormatJQLTime(t, loc))
+}
+
+// Relative returns a date relative to now, e.g. "-1w" or "2d"
+func Relative(offset string) Value {
+	return literal(Quote(offset))
+}
+
+// Function returns a call of a JQL function with quoted arguments, e.g. membersOf("jira-users")
+func Function(name string, args ...string) Value {
+	quoted := make([]string, len(args))
+	for i := range args {
+		quoted[i] = Quote(args[i])
+	}
+	return literal(fmt.Sprintf("%s(%s)", name, strings.Join(quoted, ", ")))
+}
+
+// CurrentUser is the currentUser() function
+func CurrentUser() Value { return Function("currentUser") }
+
+// OpenSprints is the openSprints() function
+func OpenSprints() Value { return Function("openSprints") }
+
+// ClosedSprints is the closedSprints() function
+func ClosedSprints() Value { return Function("closedSprints") }
+
+// FutureSprints is the futureSprints() function
+func FutureSprints() Value { return Function("futureSprints") }
+
+// Now is the now() function
+func Now() Value { return Function("now") }
+
+// StartOfDay is the startOfDay() function, with an optional offset like "-1d"
+func StartOfDay(offset ...string) Value { return Function("startOfDay", offset...) }
+
+// EndOfDay is the endOfDay() function, with an optional offset like "+1d"
+func EndOfDay(offset ...string) Value { return Function("endOfDay", offset...) }
+
+// StartOfWeek is the startOfWeek() function, with an optional offset like "-1w"
+func StartOfWeek(offset ...string) Value { return Function("startOfWeek", offset...) }
+
+// EndOfWeek is the endOfWeek() function, with an optional offset like "+1w"
+func EndOfWeek(offset ...string) Value { return Function("endOfWeek", offset...) }
+
+// StartOfMonth is the startOfMonth() function, with an optional offset like "-1M"
+func StartOfMonth(offset ...string) Value { return Function("startOfMonth", offset...) }
+
+// EndOfMonth is the endOfMonth() function, with an optional offset like "+1M"
+func EndOfMonth(offset ...string) Value { return Function("endOfMonth", offset...) }
+
+// StartOfYear is the startOfYear() function, with an optional offset like "-1y"
+func StartOfYear(offset ...string) Value { return Function("startOfYear", offset...) }
+
+// EndOfYear is the endOfYear() function, with an optional offset like "+1y"
+func EndOfYear(offset ...string) Value { return Function("endOfYear", offset...) }
+
+// MembersOf is the membersOf() function
+func MembersOf(group string) Value { return Function("membersOf", group) }
+
+// ReleasedVersions is the releasedVersions() function, optionally limited to a project
+func ReleasedVersions(project ...string) Value { return Function("releasedVersions", project...) }
+
+// UnreleasedVersions is the unreleasedVersions() function, optionally limited to a project
+func UnreleasedVersions(project ...string) Value { return Function("unreleasedVersions", project...) }
+
+// LinkedIssues is the linkedIssues() function, optionally limited to a link type
+func LinkedIssues(issueKey string, linkType ...string) Value {
+	return Function("linkedIssues", append([]string{issueKey}, linkType...)...)
+}
+
+// FieldResolver maps the display name of a custom field to its JQL reference, e.g. "Story Points" to cf[10002]
+type FieldResolver interface {
+	ResolveField(name string) (string, error)
+}
+
+// fieldList resolves field names with the fields of a Jira instance
+type fieldList []jira.Field
+
+// Fields returns a FieldResolver for the fields of a Jira instance, as returned by FieldService.GetList
+func Fields(fields []jira.Field) FieldResolver {
+	return fieldList(fields)
+}
+
+// ResolveField returns cf[id] for a custom field and the clause name (or id) for a system field
+func (f fieldList) ResolveField(name string) (string, error) {
+	field := jira.FindFieldByName(f, name)
+	if field == nil {
+		return "", fmt.Errorf("no field with name %q found", name)
+	}
+	if id := strings.TrimPrefix(field.ID, "customfield_"); field.Custom && id != field.ID {
+		return fmt.Sprintf("cf[%s]", id), nil
+	}
+	if len(field.ClauseNames) > 0 {
+		return field.ClauseNames[0], nil
+	}
+	return field.ID, nil
+}
+
+// FieldRef references a field in a clause
+type FieldRef struct {
+	name   string
+	custom bool
+}
+
+// Field references a field by its JQL name, e.g. "status" or "cf[10002]"
+func Field(name string) FieldRef {
+	return FieldRef{name: name}
+}
+
+// CustomField references a field by its display name, e.g. "Story Points".
+// With a FieldResolver, Build writes its cf[id] reference; otherwise the quoted name is written.
+func CustomField(name string) FieldRef {
+	return FieldRef{name: name, custom: true}
+}
+
+func (f FieldRef) build(r FieldResolver) (string, error) {
+	if f.name == "" {
+		return "", fmt.Errorf("empty field name")
+	}
+	if f.custom && r != nil {
+		return r.ResolveField(f.name)
+	}
+	return QuoteField(f.name), nil
+}
+
+// Operator is a JQL operator
+type Operator string
+
+// The operators of JQL
+const (
+	OpEquals         Operator = "="
+	OpNotEquals      Operator = "!="
+	OpGreater        Operator = ">"
+	OpGreaterOrEqual Operator = ">="
+	OpLess           Operator = "<"
+	OpLessOrEqual    Operator = "<="
+	OpContains       Operator = "~"
+	OpNotContains    Operator = "!~"
+	OpIn             Operator = "in"
+	OpNotIn          Operator = "not in"
+	OpIs             Operator = "is"
+	OpIsNot          Operator = "is not"
+	OpWas            Operator = "was"
+	OpWasNot         Operator = "was not"
+	OpWasIn          Operator = "was in"
+	OpWasNotIn       Operator = "was not in"
+	OpChanged        Operator = "changed"
+)
+
+// Clause is a condition of a query
+type Clause interface {
+	build(r FieldResolver) (string, error)
+}
+
+// Comparison is a clause comparing a field with values, e.g. status in ("Open", "Reopened")
+type Comparison struct {
+	field    FieldRef
+	operator Operator
+	values   []Value
+}
+
+func (f FieldRef) compare(operator Operator, values ...Value) *Comparison {
+	return &Comparison{field: f, operator: operator, values: values}
+}
+
+// Eq returns the clause field = v
+func (f FieldRef) Eq(v Value) *Comparison { return f.compare(OpEquals, v) }
+
+// NotEq returns the clause field != v
+func (f FieldRef) NotEq(v Value) *Comparison { return f.compare(OpNotEquals, v) }
+
+// Gt returns the clause field > v
+func (f FieldRef) Gt(v Value) *Comparison { return f.compare(OpGreater, v) }
+
+// Gte returns the clause field >= v
+func (f FieldRef) Gte(v Value) *Comparison { return f.compare(OpGreaterOrEqual, v) }
+
+// Lt returns the clause field < v
+func (f FieldRef) Lt(v Value) *Comparison { return f.compare(OpLess, v) }
+
+// Lte returns the clause field <= v
+func (f FieldRef) Lte(v Value) *Comparison { return f.compare(OpLessOrEqual, v) }
+
+// Contains returns the text search clause field ~ v
+func (f FieldRef) Contains(v Value) *Comparison { return f.compare(OpContains, v) }
+
+// NotContains returns the text search clause field !~ v
+func (f FieldRef) NotContains(v Value) *Comparison { return f.compare(OpNotContains, v) }
+
+// In returns the clause field in (values)
+func (f FieldRef) In(values ...Value) *Comparison { return f.compare(OpIn, values...) }
+
+// NotIn returns the clause field not in (values)
+func (f FieldRef) NotIn(values ...Value) *Comparison { return f.compare(OpNotIn, values...) }
+
+// IsEmpty returns the clause field is EMPTY
+func (f FieldRef) IsEmpty() *Comparison { return f.compare(OpIs, Empty) }
+
+// IsNotEmpty returns the clause field is not EMPTY
+func (f FieldRef) IsNotEmpty() *Comparison { return f.compare(OpIsNot, Empty) }
+
+func (c *Comparison) build(r FieldResolver) (string, error) {
+	field, err := c.field.build(r)
+	if err != nil {
+		return "", err
+	}
+	values, err := buildValues(c.operator, c.values)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s %s", field, c.operator, values), nil
+}
+
+// String returns the clause as JQL, without resolving custom field names
+func (c *Comparison) String() string {
+	return buildString(c)
+}
+
+// History is a clause on the history of a field (was, changed), optionally restricted by predicates
+type History struct {
+	field      FieldRef
+	operator   Operator
+	values     []Value
+	predicates []predicate
+}
+
+type predicate struct {
+	keyword string
+	values  []Value
+}
+
+// Was returns the clause field was v
+func (f FieldRef) Was(v Value) *History {
+	return &History{field: f, operator: OpWas, values: []Value{v}}
+}
+
+// WasNot returns the clause field was not v
+func (f FieldRef) WasNot(v Value) *History {
+	return &History{field: f, operator: OpWasNot, values: []Value{v}}
+}
+
+// WasIn returns the clause field was in (values)
+func (f FieldRef) WasIn(values ...Value) *History {
+	return &History{field: f, operator: OpWasIn, values: values}
+}
+
+// WasNotIn returns the clause field was not in (values)
+func (f FieldRef) WasNotIn(values ...Value) *History {
+	return &History{field: f, operator: OpWasNotIn, values: values}
+}
+
+// Changed returns the clause field changed
+func (f FieldRef) Changed() *History { return &History{field: f, operator: OpChanged} }
+
+func (h *History) with(keyword string, values ...Value) *History {
+	h.predicates = append(h.predicates, predicate{keyword: keyword, values: values})
+	return h
+}
+
+// From restricts a changed clause to changes from v
+func (h *History) From(v Value) *History { return h.with("FROM", v) }
+
+// To restricts a changed clause to changes to v
+func (h *History) To(v Value) *History { return h.with("TO", v) }
+
+// By restricts the clause to changes by the user v
+func (h *History) By(v Value) *History { return h.with("BY", v) }
+
+// After restricts the clause to changes after the date v
+func (h *History) After(v Value) *History { return h.with("AFTER", v) }
+
+// Before restricts the clause to changes before the date v
+func (h *History) Before(v Value) *History { return h.with("BEFORE", v) }
+
+// On restricts the clause to changes on the date v
+func (h *History) On(v Value) *History { return h.with("ON", v) }
+
+// During restricts the clause to changes between the dates from and to
+func (h *History) During(from, to Value) *History { return h.with("DURING", from, to) }
+
+func (h *History) build(r FieldResolver) (string, error) {
+	field, err := h.field.build(r)
+	if err != nil {
+		return "", err
+	}
+	parts := []string{field, string(h.operator)}
+	if h.operator != OpChanged {
+		values, err := buildValues(h.operator, h.values)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, values)
+	}
+	for _, p := range h.predicates {
+		if len(p.values) == 0 || p.values[0] == nil || (p.keyword == "DURING" && (len(p.values) != 2 || p.values[1] == nil)) {
+			return "", fmt.Errorf("missing value of %s", p.keyword)
+		}
+		if p.keyword == "DURING" {
+			parts = append(parts, fmt.Sprintf("DURING (%s, %s)", p.values[0].jql(), p.values[1].jql()))
+		} else {
+			parts = append(parts, p.keyword, p.values[0].jql())
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// String returns the clause as JQL, without resolving custom field names
+func (h *History) String() string {
+	return buildString(h)
+}
+
+// buildValues returns the value of a clause, or the list of values of an in clause
+func buildValues(operator Operator, values []Value) (string, error) {
+	list := operator == OpIn || operator == OpNotIn || operator == OpWasIn || operator == OpWasNotIn
+	if list && len(values) == 0 {
+		return "", fmt.Errorf("operator %s needs at least one value", operator)
+	}
+	if !list && len(values) != 1 {
+		return "", fmt.Errorf("operator %s needs one value", operator)
+	}
+
+	parts := make([]string, len(values))
+	for i, v := range values {
+		if v == nil {
+			return "", fmt.Errorf("nil value for operator %s", operator)
+		}
+		parts[i] = v.jql()
+	}
+	if list {
+		return "(" + strings.Join(parts, ", ") + ")", nil
+	}
+	return parts[0], nil
+}
+
+// Group combines clauses with AND or OR
+type Group struct {
+	operator string
+	clauses  []Clause
+}
+
+// And combines clauses with AND. Nil clauses are skipped.
+func And(clauses ...Clause) *Group {
+	return &Group{operator: "AND", clauses: clauses}
+}
+
+// Or combines clauses with OR. Nil clauses are skipped.
+func Or(clauses ...Clause) *Group {
+	return &Group{operator: "OR", clauses: clauses}
+}
+
+func (g *Group) build(r FieldResolver) (string, error) {
+	part, _, err := g.join(r)
+	return part, err
+}
+
+// join builds the clauses of the group and returns the operator which joins the top level of the result,
+// or an empty operator if the result is a single clause. A group with a single clause is written as that clause,
+// so the operator of nested groups is only known after they are built.
+func (g *Group) join(r FieldResolver) (string, string, error) {
+	var parts []string
+	operator := ""
+	for _, clause := range g.clauses {
+		if isNil(clause) {
+			continue
+		}
+		part, op, err := buildJoined(clause, r)
+		if err != nil {
+			return "", "", err
+		}
+		if part == "" {
+			continue
+		}
+		// AND binds stronger than OR, so only an OR inside an AND group needs parentheses
+		if op == "OR" && g.operator == "AND" {
+			part, op = "("+part+")", ""
+		}
+		parts = append(parts, part)
+		operator = op
+	}
+	if len(parts) > 1 {
+		operator = g.operator
+	}
+	return strings.Join(parts, " "+g.operator+" "), operator, nil
+}
+
+// buildJoined builds a clause and returns the operator which joins the top level of the result, if any
+func buildJoined(clause Clause, r FieldResolver) (string, string, error) {
+	if group, ok := clause.(*Group); ok {
+		return group.join(r)
+	}
+	part, err := clause.build(r)
+	return part, "", err
+}
+
+// String returns the clauses as JQL, without resolving custom field names
+func (g *Group) String() string {
+	return buildString(g)
+}
+
+// Negation negates a clause
+type Negation struct {
+	clause Clause
+}
+
+// Not negates a clause
+func Not(clause Clause) *Negation {
+	return &Negation{clause: clause}
+}
+
+func (n *Negation) build(r FieldResolver) (string, error) {
+	if isNil(n.clause) {
+		return "", fmt.Errorf("NOT without clause")
+	}
+	part, operator, err := buildJoined(n.clause, r)
+	if err != nil {
+		return "", err
+	}
+	if operator != "" {
+		part = "(" + part + ")"
+	}
+	return "NOT " + part, nil
+}
+
+// String returns the clause as JQL, without resolving custom field names
+func (n *Negation) String() string {
+	return buildString(n)
+}
+
+// Direction is the sort direction of ORDER BY
+type Direction string
+
+// The sort directions
+const (
+	Asc  Direction = "ASC"
+	Desc Direction = "DESC"
+)
+
+type ordering struct {
+	field     FieldRef
+	direction Direction
+}
+
+// Query is a JQL query: a clause and the ordering of the result
+type Query struct {
+	where   Clause
+	orderBy []ordering
+}
+
+// Where returns a query for the issues matching the clause. A nil clause matches all issues.
+func Where(clause Clause) *Query {
+	return &Query{where: clause}
+}
+
+// OrderBy adds a sort field to the query
+func (q *Query) OrderBy(field FieldRef, direction Direction) *Query {
+	q.orderBy = append(q.orderBy, ordering{field: field, direction: direction})
+	return q
+}
+
+// Build returns the query as JQL. Custom fields referenced by name are resolved with r, if r is not nil.
+func (q *Query) Build(r FieldResolver) (string, error) {
+	var jql string
+	if !isNil(q.where) {
+		var err error
+		if jql, err = q.where.build(r); err != nil {
+			return "", err
+		}
+	}
+
+	if len(q.orderBy) > 0 {
+		orders := make([]string, len(q.orderBy))
+		for i, o := range q.orderBy {
+			field, err := o.field.build(r)
+			if err != nil {
+				return "", err
+			}
+			orders[i] = field
+			if o.direction != "" {
+				orders[i] += " " + string(o.direction)
+			}
+		}
+		jql = strings.TrimSpace(jql + " ORDER BY " + strings.Join(orders, ", "))
+	}
+	return jql, nil
+}
+
+// String returns the query as JQL, without resolving custom field names.
+// An invalid query is returned as an empty string; use Build to get the error.
+func (q *Query) String() string {
+	jql, err := q.Build(nil)
+	if err != nil {
+		return ""
+	}
+	return jql
+}
+
+// buildString returns the JQL of a clause without resolving custom field names, or "" if it is invalid
+func buildString(c Clause) string {
+	jql, err := c.build(nil)
+	if err != nil {
+		return ""
+	}
+	return jql
+}
+
+// isNil reports whether a clause is nil, including typed nil pointers
+func isNil(c Clause) bool {
+	switch v := c.(type) {
+	case nil:
+		return true
+	case *Comparison:
+		return v == nil
+	case *History:
+		return v == nil
+	case *Group:
+		return v == nil
+	case *Negation:
+		return v == nil
+	}
+	return false
+}
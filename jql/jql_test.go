@@ -0,0 +1,135 @@
+package jql
+
+import (
+	"testing"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+)
+
+func TestQuote(t *testing.T) {
+	if got := Quote(`say "hi" \ bye` + "\n"); got != `"say \"hi\" \\ bye\n"` {
+		t.Errorf("Unexpected quoting %s", got)
+	}
+	tt := map[string]string{
+		"status":        "status",
+		"cf[10001]":     "cf[10001]",
+		"order":         `"order"`,
+		"Story Points":  `"Story Points"`,
+		"Epic-Link":     `"Epic-Link"`,
+		"issue.comment": "issue.comment",
+	}
+	for name, expected := range tt {
+		if got := QuoteField(name); got != expected {
+			t.Errorf("%s: Expected %s, got %s", name, expected, got)
+		}
+	}
+}
+
+func TestQuery_String(t *testing.T) {
+	since := time.Date(2021, time.March, 1, 23, 30, 0, 0, time.UTC)
+	tt := []struct {
+		query    *Query
+		expected string
+	}{
+		{
+			Where(And(
+				Field("project").Eq(String("TST")),
+				Field("status").NotIn(Strings("Done", "Won't Fix")...),
+				Or(Field("assignee").Eq(CurrentUser()), Field("assignee").IsEmpty()),
+			)).OrderBy(Field("priority"), Desc).OrderBy(Field("key"), ""),
+			`project = "TST" AND status not in ("Done", "Won't Fix") AND (assignee = currentUser() OR assignee is EMPTY) ORDER BY priority DESC, key`,
+		},
+		{
+			Where(Or(And(Field("sprint").In(OpenSprints()), Field("type").Eq(String("Bug"))), Not(Field("labels").IsNotEmpty()))),
+			`sprint in (openSprints()) AND type = "Bug" OR NOT labels is not EMPTY`,
+		},
+		{
+			Where(Not(Or(Field("summary").Contains(String("crash")), Field("description").NotContains(String("crash"))))),
+			`NOT (summary ~ "crash" OR description !~ "crash")`,
+		},
+		{
+			Where(And(
+				Field("created").Gte(StartOfWeek("-1w")),
+				Field("updated").Lt(DateTime(since, time.FixedZone("CET", 3600))),
+				Field("duedate").Lte(Date(since, nil)),
+				Field("votes").Gt(Int(2)),
+				Field("order").NotEq(Float(1.5)),
+			)),
+			`created >= startOfWeek("-1w") AND updated < "2021-03-02 00:30" AND duedate <= "2021-03-01" AND votes > 2 AND "order" != 1.5`,
+		},
+		{
+			Where(And(
+				Field("status").Changed().From(String("Open")).To(String("Done")).By(CurrentUser()).During(Relative("-2w"), Now()),
+				Field("assignee").WasIn(MembersOf("devs")).After(StartOfMonth()),
+				Field("status").WasNot(String("Reopened")).On(Date(since, nil)),
+			)),
+			`status changed FROM "Open" TO "Done" BY currentUser() DURING ("-2w", now()) AND assignee was in (membersOf("devs")) AFTER startOfMonth() AND status was not "Reopened" ON "2021-03-01"`,
+		},
+		{
+			Where(And(Field("x").Eq(Int(3)), Or(Or(Field("a").Eq(Int(1)), Field("b").Eq(Int(2)))))),
+			`x = 3 AND ("a" = 1 OR b = 2)`,
+		},
+		{
+			Where(And(Field("x").Eq(Int(3)), Not(Or(Or(Field("a").Eq(Int(1)), Field("b").Eq(Int(2))))))),
+			`x = 3 AND NOT ("a" = 1 OR b = 2)`,
+		},
+		{
+			Where(And(Field("x").Eq(Int(3)), And(nil, Or(Field("a").Eq(Int(1)), Field("b").Eq(Int(2)))))),
+			`x = 3 AND ("a" = 1 OR b = 2)`,
+		},
+		{
+			Where(Not(And(Or(Field("x").Eq(Int(3)))))),
+			`NOT x = 3`,
+		},
+		{
+			Where(nil).OrderBy(Field("created"), Asc),
+			`ORDER BY created ASC`,
+		},
+		{
+			Where(And(nil, Field("issue").In(LinkedIssues("TST-1", "blocks")), Or())),
+			`issue in (linkedIssues("TST-1", "blocks"))`,
+		},
+	}
+	for _, tc := range tt {
+		if got := tc.query.String(); got != tc.expected {
+			t.Errorf("Expected\n%s\ngot\n%s", tc.expected, got)
+		}
+	}
+}
+
+func TestQuery_Build_Errors(t *testing.T) {
+	for _, query := range []*Query{
+		Where(Field("status").In()),
+		Where(Field("").Eq(String("x"))),
+		Where(Field("status").Eq(nil)),
+		Where(Not(nil)),
+	} {
+		if _, err := query.Build(nil); err == nil {
+			t.Errorf("Expected an error for %#v", query)
+		}
+	}
+}
+
+func TestQuery_Build_Fields(t *testing.T) {
+	fields := Fields([]jira.Field{
+		{ID: "customfield_10002", Name: "Story Points", Custom: true},
+		{ID: "status", Name: "Status", ClauseNames: []string{"status"}},
+	})
+	query := Where(And(CustomField("story points").Gt(Int(3)), CustomField("Status").Eq(String("Open")))).OrderBy(CustomField("Story Points"), Desc)
+
+	got, err := query.Build(fields)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if expected := `cf[10002] > 3 AND status = "Open" ORDER BY cf[10002] DESC`; got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+	if expected := `"story points" > 3 AND Status = "Open" ORDER BY "Story Points" DESC`; query.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, query.String())
+	}
+
+	if _, err := Where(CustomField("Unknown").IsEmpty()).Build(fields); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+}
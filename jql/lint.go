@@ -0,0 +1,270 @@
+package jql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	jira "github.com/perolo/jira-client"
+)
+
+// Severity is the severity of a lint problem
+type Severity string
+
+// The severities of lint problems
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// The rules of the linter
+const (
+	RuleSyntax        = "syntax"
+	RuleUnknownField  = "unknown-field"
+	RuleUsername      = "username"
+	RuleReservedWord  = "reserved-word"
+	RuleAlwaysTrue    = "always-true"
+	RuleAlwaysFalse   = "always-false"
+	RuleDuplicate     = "duplicate-clause"
+	RuleRemoteInvalid = "remote"
+)
+
+// Problem is a problem found in a JQL query.
+// Pos is the byte offset in the query, or -1 if the problem has no position (e.g. for remote validation).
+type Problem struct {
+	Pos      int
+	Severity Severity
+	Rule     string
+	Message  string
+}
+
+func (p Problem) String() string {
+	if p.Pos < 0 {
+		return fmt.Sprintf("%s: %s (%s)", p.Severity, p.Message, p.Rule)
+	}
+	return fmt.Sprintf("%d: %s: %s (%s)", p.Pos, p.Severity, p.Message, p.Rule)
+}
+
+// LintOptions specifies the optional checks of Lint
+type LintOptions struct {
+	// Fields are the fields of the instance, as returned by FieldService.GetList.
+	// If set, fields which are not known are reported.
+	Fields []jira.Field
+	// Cloud reports user names in user clauses, which Jira Cloud does not accept any more (account ids are required)
+	Cloud bool
+}
+
+// jqlOnlyFields are fields which can be used in JQL but are not returned by the field API
+var jqlOnlyFields = map[string]bool{
+	"category": true, "comment": true, "filter": true, "request": true, "savedfilter": true, "searchrequest": true,
+	"issue": true, "issuekey": true, "key": true, "id": true, "text": true, "parent": true, "parentepic": true,
+	"hierarchylevel": true, "issuefunction": true, "lastviewed": true, "level": true, "subtasks": true,
+	"worklogauthor": true, "worklogcomment": true, "worklogdate": true, "updateddate": true, "createddate": true,
+	"resolutiondate": true, "duedate": true, "issuelinktype": true, "attachments": true, "voter": true, "watcher": true,
+}
+
+// userFields are the system fields holding users
+var userFields = map[string]bool{
+	"assignee": true, "reporter": true, "creator": true, "voter": true, "watcher": true, "worklogauthor": true,
+}
+
+// accountID matches the account ids of Jira Cloud, e.g. 5b10a2844c20165700ede21g or 557058:f58131cb-b67d-43c7-b30d-6b58d40bd077
+var accountID = regexp.MustCompile(`^(?:[0-9a-f]{24}|[0-9]+:[0-9a-f-]{36}|qm:[0-9a-f-]+:[0-9a-f-]+)$`)
+
+// customFieldRef matches cf[10001]
+var customFieldRef = regexp.MustCompile(`^cf\[(\d+)\]$`)
+
+// Lint parses a JQL query and reports problems: syntax errors, unknown fields, user names on Cloud,
+// unquoted reserved words, duplicate clauses and clauses which are always true or always false.
+// It is meant to check stored queries like Filter.Jql or the sub-queries of board configurations offline.
+func Lint(query string, options *LintOptions) []Problem {
+	if options == nil {
+		options = &LintOptions{}
+	}
+
+	stmt, err := Parse(query)
+	if err != nil {
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return []Problem{{Pos: syntaxErr.Pos, Severity: SeverityError, Rule: RuleSyntax, Message: syntaxErr.Msg}}
+		}
+		return []Problem{{Pos: -1, Severity: SeverityError, Rule: RuleSyntax, Message: err.Error()}}
+	}
+
+	l := &linter{options: options}
+	if len(options.Fields) > 0 {
+		l.fields = make(map[string]*jira.Field)
+		for i := range options.Fields {
+			field := &options.Fields[i]
+			l.fields[strings.ToLower(field.ID)] = field
+			l.fields[strings.ToLower(field.Name)] = field
+			for _, name := range field.ClauseNames {
+				l.fields[strings.ToLower(name)] = field
+			}
+		}
+	}
+
+	if stmt.Where != nil {
+		l.expr(stmt.Where)
+	}
+	for _, sort := range stmt.OrderBy {
+		l.field(sort.Field)
+	}
+	return l.problems
+}
+
+type linter struct {
+	options  *LintOptions
+	fields   map[string]*jira.Field
+	problems []Problem
+}
+
+func (l *linter) report(pos int, severity Severity, rule, format string, args ...interface{}) {
+	l.problems = append(l.problems, Problem{Pos: pos, Severity: severity, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) expr(expr Expr) {
+	switch e := expr.(type) {
+	case *LogicalExpr:
+		for _, operand := range e.Operands {
+			l.expr(operand)
+		}
+		l.contradictions(e)
+	case *NotExpr:
+		l.expr(e.X)
+	case *TermExpr:
+		l.term(e)
+	}
+}
+
+// field checks a field name and returns the field of the instance, if known
+func (l *linter) field(name FieldName) *jira.Field {
+	lower := strings.ToLower(name.Name)
+	if !name.Quoted && reservedWords[lower] {
+		l.report(name.Position, SeverityError, RuleReservedWord, "reserved word %s has to be quoted", name.Name)
+	}
+	if l.fields == nil {
+		return nil
+	}
+
+	if match := customFieldRef.FindStringSubmatch(lower); match != nil {
+		lower = "customfield_" + match[1]
+	}
+	field := l.fields[lower]
+	if field == nil && !jqlOnlyFields[strings.ReplaceAll(lower, " ", "")] {
+		l.report(name.Position, SeverityError, RuleUnknownField, "unknown field %s", name.Name)
+	}
+	return field
+}
+
+func (l *linter) term(t *TermExpr) {
+	field := l.field(t.Field)
+	isUser := userFields[strings.ToLower(t.Field.Name)] || (field != nil && field.Schema.Type == "user")
+
+	l.values(t.Value, l.options.Cloud && isUser)
+	for _, p := range t.Predicates {
+		for _, v := range p.Values {
+			l.values(v, l.options.Cloud && (p.Keyword == "BY" || (isUser && (p.Keyword == "FROM" || p.Keyword == "TO"))))
+		}
+	}
+}
+
+// values checks the values of a clause. With users set, literal values have to be account ids.
+func (l *linter) values(v ValueNode, users bool) {
+	switch value := v.(type) {
+	case *List:
+		for _, item := range value.Values {
+			l.values(item, users)
+		}
+	case *Literal:
+		if value.Empty {
+			return
+		}
+		if !value.Quoted && reservedWords[strings.ToLower(value.Text)] {
+			l.report(value.Position, SeverityError, RuleReservedWord, "reserved word %s has to be quoted", value.Text)
+		}
+		if users && !accountID.MatchString(value.Text) {
+			l.report(value.Position, SeverityWarning, RuleUsername, "%s looks like a user name; Jira Cloud requires account ids", value.Text)
+		}
+	}
+}
+
+// contradictions reports operands of AND / OR which are duplicates, or which contradict each other,
+// like status = Done AND status != Done (always false) or assignee is EMPTY OR assignee is not EMPTY (always true).
+// assignee = bob OR assignee != bob is not always true: negative operators never match empty values.
+func (l *linter) contradictions(e *LogicalExpr) {
+	var terms []*TermExpr
+	for _, operand := range e.Operands {
+		if term, ok := operand.(*TermExpr); ok && len(term.Predicates) == 0 && term.Operator != OpChanged {
+			terms = append(terms, term)
+		}
+	}
+
+	for i := 0; i < len(terms); i++ {
+		for j := i + 1; j < len(terms); j++ {
+			a, b := terms[i], terms[j]
+			if !strings.EqualFold(a.Field.Name, b.Field.Name) || formatValue(a.Value) != formatValue(b.Value) {
+				continue
+			}
+			if a.Operator == b.Operator {
+				l.report(b.Pos(), SeverityWarning, RuleDuplicate, "duplicate clause %s", formatTerm(b))
+				continue
+			}
+			if negatedOperators[a.Operator] != b.Operator {
+				continue
+			}
+			if e.Operator == "AND" {
+				l.report(a.Pos(), SeverityWarning, RuleAlwaysFalse, "%s AND %s is always false", formatTerm(a), formatTerm(b))
+			} else if emptyMatchingOperators[a.Operator] {
+				l.report(a.Pos(), SeverityWarning, RuleAlwaysTrue, "%s OR %s is always true", formatTerm(a), formatTerm(b))
+			}
+		}
+	}
+}
+
+// negatedOperators maps operators to their negation
+var negatedOperators = map[Operator]Operator{
+	OpEquals: OpNotEquals, OpNotEquals: OpEquals,
+	OpIn: OpNotIn, OpNotIn: OpIn,
+	OpIs: OpIsNot, OpIsNot: OpIs,
+	OpContains: OpNotContains, OpNotContains: OpContains,
+	OpWas: OpWasNot, OpWasNot: OpWas,
+	OpWasIn: OpWasNotIn, OpWasNotIn: OpWasIn,
+}
+
+// emptyMatchingOperators are the operators which, together with their negation, cover empty values as well,
+// so that a clause OR its negation is always true
+var emptyMatchingOperators = map[Operator]bool{
+	OpIs: true, OpIsNot: true,
+	OpWas: true, OpWasNot: true,
+}
+
+// Validate lets Jira validate a JQL query, by running a search for one issue with strict validation.
+// The errors reported by Jira (e.g. unknown values or fields not visible to the user) are returned as problems.
+// An error is only returned if the validation itself failed.
+func Validate(ctx context.Context, client *jira.Client, query string) ([]Problem, error) {
+	_, resp, err := client.Issue.SearchWithContext(ctx, query, &jira.SearchOptions{
+		MaxResults:    1,
+		Fields:        []string{"key"},
+		ValidateQuery: "strict",
+	})
+	if err == nil {
+		return nil, nil
+	}
+
+	var jerr *jira.Error
+	if resp == nil || resp.StatusCode != http.StatusBadRequest || !errors.As(err, &jerr) {
+		return nil, err
+	}
+	var problems []Problem
+	for _, message := range jerr.ErrorMessages {
+		problems = append(problems, Problem{Pos: -1, Severity: SeverityError, Rule: RuleRemoteInvalid, Message: message})
+	}
+	for key, message := range jerr.Errors {
+		problems = append(problems, Problem{Pos: -1, Severity: SeverityError, Rule: RuleRemoteInvalid, Message: key + ": " + message})
+	}
+	return problems, nil
+}
@@ -0,0 +1,140 @@
+package jql
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/perolo/jira-client"
+)
+
+var lintFields = []jira.Field{
+	{ID: "project", Name: "Project", ClauseNames: []string{"project"}},
+	{ID: "status", Name: "Status", ClauseNames: []string{"status"}},
+	{ID: "resolution", Name: "Resolution", ClauseNames: []string{"resolution"}},
+	{ID: "summary", Name: "Summary", ClauseNames: []string{"summary"}},
+	{ID: "assignee", Name: "Assignee", ClauseNames: []string{"assignee"}, Schema: jira.FieldSchema{Type: "user"}},
+	{ID: "customfield_10001", Name: "Story Points", Custom: true, ClauseNames: []string{"cf[10001]", "Story Points"}},
+	{ID: "customfield_10002", Name: "Approver", Custom: true, ClauseNames: []string{"cf[10002]", "Approver"}, Schema: jira.FieldSchema{Type: "user"}},
+}
+
+func rules(problems []Problem) []string {
+	var result []string
+	for _, p := range problems {
+		result = append(result, p.Rule)
+	}
+	return result
+}
+
+func TestLint(t *testing.T) {
+	tt := []struct {
+		query   string
+		cloud   bool
+		rules   []string
+		message string
+	}{
+		{query: `project = TST AND "Story Points" > 3 AND cf[10001] < 8 AND issuekey = TST-1`},
+		{query: `project = TST AND Stroy = 3`, rules: []string{RuleUnknownField}, message: "unknown field Stroy"},
+		{query: `project = TST ORDER BY rnak`, rules: []string{RuleUnknownField}},
+		{query: `project = TST AND`, rules: []string{RuleSyntax}},
+		{query: `assignee = jsmith`},
+		{query: `assignee = jsmith`, cloud: true, rules: []string{RuleUsername}, message: "jsmith looks like a user name; Jira Cloud requires account ids"},
+		{query: `assignee in (5b10a2844c20165700ede21a, currentUser()) OR assignee is EMPTY`, cloud: true},
+		{query: `Approver = "557058:f58131cb-b67d-43c7-b30d-6b58d40bd077" OR Approver = jsmith`, cloud: true, rules: []string{RuleUsername}},
+		{query: `status changed by jsmith`, cloud: true, rules: []string{RuleUsername}},
+		{query: `status = order`, rules: []string{RuleReservedWord}, message: "reserved word order has to be quoted"},
+		{query: `status = "order"`},
+		{query: `project = TST AND status = Done AND status != Done`, rules: []string{RuleAlwaysFalse}, message: "status = Done AND status != Done is always false"},
+		{query: `assignee is EMPTY OR assignee is not EMPTY`, rules: []string{RuleAlwaysTrue}},
+		{query: `status in (Open, Done) AND status not in (Open, Done)`, rules: []string{RuleAlwaysFalse}},
+		{query: `assignee = bob OR assignee != bob`},
+		{query: `resolution = Fixed OR resolution != Fixed`},
+		{query: `status in (Open, Done) OR status not in (Open, Done)`},
+		{query: `summary ~ login OR summary !~ login`},
+		{query: `status was Open OR status was not Open`, rules: []string{RuleAlwaysTrue}},
+		{query: `status = Done AND status != Open`},
+		{query: `project = TST OR project = TST`, rules: []string{RuleDuplicate}},
+	}
+	for _, test := range tt {
+		problems := Lint(test.query, &LintOptions{Fields: lintFields, Cloud: test.cloud})
+		if got := rules(problems); len(got) != len(test.rules) || (len(got) > 0 && got[0] != test.rules[0]) {
+			t.Errorf("%s: Expected %v, got %v", test.query, test.rules, problems)
+			continue
+		}
+		if test.message != "" && problems[0].Message != test.message {
+			t.Errorf("%s: Expected message %q, got %q", test.query, test.message, problems[0].Message)
+		}
+	}
+}
+
+func TestLint_Position(t *testing.T) {
+	problems := Lint(`project = TST AND Stroy = 3`, &LintOptions{Fields: lintFields})
+	if len(problems) != 1 || problems[0].Pos != 18 || problems[0].Severity != SeverityError {
+		t.Errorf("Unexpected problems %v", problems)
+	}
+
+	problems = Lint(`project = `, nil)
+	if len(problems) != 1 || problems[0].Pos != 10 || problems[0].Rule != RuleSyntax {
+		t.Errorf("Unexpected problems %v", problems)
+	}
+}
+
+func TestLint_WithoutFields(t *testing.T) {
+	if problems := Lint(`Stroy = 3 ORDER BY rnak`, nil); len(problems) != 0 {
+		t.Errorf("Expected no problems without fields, got %v", problems)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("validateQuery"); got != "strict" {
+			t.Errorf("Expected strict validation, got %q", got)
+		}
+		if r.URL.Query().Get("jql") == "project = TST" {
+			_, _ = w.Write([]byte(`{"startAt": 0, "maxResults": 1, "total": 0, "issues": []}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errorMessages": ["The value 'XYZ' does not exist for the field 'project'."], "errors": {}}`))
+	})
+
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := Validate(context.Background(), client, "project = TST")
+	if err != nil || len(problems) != 0 {
+		t.Errorf("Expected a valid query, got %v, %v", problems, err)
+	}
+
+	problems, err = Validate(context.Background(), client, "project = XYZ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 1 || problems[0].Rule != RuleRemoteInvalid || problems[0].Pos != -1 ||
+		problems[0].Message != "The value 'XYZ' does not exist for the field 'project'." {
+		t.Errorf("Unexpected problems %v", problems)
+	}
+}
+
+func TestValidate_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Validate(context.Background(), client, "project = TST"); err == nil {
+		t.Error("Expected an error")
+	}
+}
@@ -0,0 +1,558 @@
+package jql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxError is an error in the syntax of a JQL query.
+// Pos is the byte offset of the error in the query.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("jql: %s at position %d", e.Msg, e.Pos)
+}
+
+// Statement is a parsed JQL query: the condition and the ordering of the result
+type Statement struct {
+	// Where is nil for a query without condition
+	Where   Expr
+	OrderBy []SortField
+}
+
+// Expr is a condition of a parsed query: *LogicalExpr, *NotExpr or *TermExpr
+type Expr interface {
+	Pos() int
+}
+
+// LogicalExpr combines two or more conditions with AND or OR
+type LogicalExpr struct {
+	Position int
+	// Operator is "AND" or "OR"
+	Operator string
+	Operands []Expr
+}
+
+// NotExpr negates a condition
+type NotExpr struct {
+	Position int
+	X        Expr
+}
+
+// TermExpr is a clause on a field, e.g. status = Done or status changed FROM Open
+type TermExpr struct {
+	Field    FieldName
+	Operator Operator
+	// Value is nil for the changed operator
+	Value      ValueNode
+	Predicates []Predicate
+}
+
+// FieldName is a field name in a parsed query
+type FieldName struct {
+	Position int
+	Name     string
+	Quoted   bool
+}
+
+// ValueNode is a value in a parsed query: *Literal, *List or *FunctionCall
+type ValueNode interface {
+	Pos() int
+}
+
+// Literal is a single value. Empty is set for EMPTY and NULL.
+type Literal struct {
+	Position int
+	Text     string
+	Quoted   bool
+	Empty    bool
+}
+
+// List is a list of values, e.g. ("Open", "Reopened")
+type List struct {
+	Position int
+	Values   []ValueNode
+}
+
+// FunctionCall is a call of a JQL function, e.g. membersOf("jira-users")
+type FunctionCall struct {
+	Position int
+	Name     string
+	Args     []*Literal
+}
+
+// Predicate restricts a history clause, e.g. AFTER "2021-01-01" or DURING ("-1w", now())
+type Predicate struct {
+	Position int
+	// Keyword is one of FROM, TO, BY, AFTER, BEFORE, ON or DURING
+	Keyword string
+	Values  []ValueNode
+}
+
+// SortField is a field of the ORDER BY part of a query
+type SortField struct {
+	Field FieldName
+	// Direction is "ASC", "DESC" or empty
+	Direction Direction
+}
+
+// Pos returns the position of the expression in the query
+func (e *LogicalExpr) Pos() int { return e.Position }
+
+// Pos returns the position of the expression in the query
+func (e *NotExpr) Pos() int { return e.Position }
+
+// Pos returns the position of the expression in the query
+func (e *TermExpr) Pos() int { return e.Field.Position }
+
+// Pos returns the position of the value in the query
+func (v *Literal) Pos() int { return v.Position }
+
+// Pos returns the position of the value in the query
+func (v *List) Pos() int { return v.Position }
+
+// Pos returns the position of the value in the query
+func (v *FunctionCall) Pos() int { return v.Position }
+
+// tokenKind is the kind of a token of the lexer
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// is reports whether the token is the unquoted keyword (case insensitive)
+func (t token) is(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+// wordDelimiters are the characters which end an unquoted word
+const wordDelimiters = "\"'(),=!<>~&|"
+
+// lex splits a query into tokens
+func lex(query string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(query); {
+		c := query[i]
+		r, size := utf8.DecodeRuneInString(query[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case c == '"' || c == '\'':
+			text, n, err := lexString(query[i:])
+			if err != nil {
+				return nil, &SyntaxError{Pos: i, Msg: err.Error()}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i})
+			i += n
+		case c == '&' || c == '|':
+			if i+1 >= len(query) || query[i+1] != c {
+				return nil, &SyntaxError{Pos: i, Msg: fmt.Sprintf("unexpected %q", c)}
+			}
+			keyword := "AND"
+			if c == '|' {
+				keyword = "OR"
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: keyword, pos: i})
+			i += 2
+		case strings.IndexByte("=!<>~", c) >= 0:
+			operator := string(c)
+			if i+1 < len(query) && (query[i+1] == '=' || (c == '!' && query[i+1] == '~')) {
+				operator += string(query[i+1])
+			}
+			if operator == "!" {
+				// ! is an alias of NOT
+				tokens = append(tokens, token{kind: tokenWord, text: "NOT", pos: i})
+			} else {
+				tokens = append(tokens, token{kind: tokenOperator, text: operator, pos: i})
+			}
+			i += len(operator)
+		default:
+			start := i
+			for i < len(query) {
+				r, size := utf8.DecodeRuneInString(query[i:])
+				if unicode.IsSpace(r) || strings.ContainsRune(wordDelimiters, r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: query[start:i], pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(query)}), nil
+}
+
+// lexString reads a quoted string at the start of s and returns its unescaped text and its length in s
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case quote:
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(s) {
+				return "", 0, fmt.Errorf("unterminated string")
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// parser is a recursive descent parser of JQL
+type parser struct {
+	tokens []token
+	i      int
+}
+
+// Parse parses a JQL query. Errors are of type *SyntaxError.
+func Parse(query string) (*Statement, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+
+	stmt := &Statement{}
+	if !p.peek().is("ORDER") && p.peek().kind != tokenEOF {
+		if stmt.Where, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek().is("ORDER") {
+		p.next()
+		if !p.next().is("BY") {
+			return nil, p.errorf(p.prev(), "expected BY after ORDER")
+		}
+		if stmt.OrderBy, err = p.parseSortFields(); err != nil {
+			return nil, err
+		}
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	return stmt, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) prev() token {
+	if p.i == 0 {
+		return p.tokens[0]
+	}
+	return p.tokens[p.i-1]
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if t.kind == tokenEOF {
+		msg += " (end of query)"
+	}
+	return &SyntaxError{Pos: t.pos, Msg: msg}
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	return p.parseLogical("OR", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	return p.parseLogical("AND", p.parseNot)
+}
+
+func (p *parser) parseLogical(operator string, operand func() (Expr, error)) (Expr, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	if !p.peek().is(operator) {
+		return first, nil
+	}
+
+	expr := &LogicalExpr{Position: first.Pos(), Operator: operator, Operands: []Expr{first}}
+	for p.peek().is(operator) {
+		p.next()
+		x, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		expr.Operands = append(expr.Operands, x)
+	}
+	return expr, nil
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	t := p.peek()
+	if t.is("NOT") {
+		p.next()
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{Position: t.pos, X: x}, nil
+	}
+	if t.kind == tokenLParen {
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, p.errorf(p.peek(), "expected )")
+		}
+		p.next()
+		return x, nil
+	}
+	return p.parseTerm()
+}
+
+func (p *parser) parseField() (FieldName, error) {
+	t := p.next()
+	if t.kind != tokenWord && t.kind != tokenString {
+		return FieldName{}, p.errorf(t, "expected field name")
+	}
+	return FieldName{Position: t.pos, Name: t.text, Quoted: t.kind == tokenString}, nil
+}
+
+func (p *parser) parseTerm() (Expr, error) {
+	field, err := p.parseField()
+	if err != nil {
+		return nil, err
+	}
+	term := &TermExpr{Field: field}
+
+	t := p.next()
+	switch {
+	case t.kind == tokenOperator:
+		term.Operator = Operator(t.text)
+		if !comparisonOperators[term.Operator] {
+			return nil, p.errorf(t, "unknown operator %s", t.text)
+		}
+	case t.is("IN"):
+		term.Operator = OpIn
+	case t.is("NOT"):
+		if !p.next().is("IN") {
+			return nil, p.errorf(p.prev(), "expected IN after NOT")
+		}
+		term.Operator = OpNotIn
+	case t.is("IS"):
+		term.Operator = OpIs
+		if p.peek().is("NOT") {
+			p.next()
+			term.Operator = OpIsNot
+		}
+	case t.is("WAS"):
+		term.Operator = OpWas
+		if p.peek().is("NOT") {
+			p.next()
+			term.Operator = OpWasNot
+		}
+		if p.peek().is("IN") {
+			p.next()
+			term.Operator += " in"
+		}
+	case t.is("CHANGED"):
+		term.Operator = OpChanged
+	default:
+		return nil, p.errorf(t, "expected operator after field %s", field.Name)
+	}
+
+	if term.Operator != OpChanged {
+		list := term.Operator == OpIn || term.Operator == OpNotIn || term.Operator == OpWasIn || term.Operator == OpWasNotIn
+		if term.Value, err = p.parseValue(list); err != nil {
+			return nil, err
+		}
+	}
+
+	if term.Operator == OpWas || term.Operator == OpWasNot || term.Operator == OpWasIn || term.Operator == OpWasNotIn || term.Operator == OpChanged {
+		if term.Predicates, err = p.parsePredicates(); err != nil {
+			return nil, err
+		}
+	}
+	return term, nil
+}
+
+// parseValue parses the value of a clause. For list operators, a parenthesized list is accepted.
+func (p *parser) parseValue(list bool) (ValueNode, error) {
+	t := p.peek()
+	if list && t.kind == tokenLParen {
+		p.next()
+		values := &List{Position: t.pos}
+		for {
+			v, err := p.parseValue(false)
+			if err != nil {
+				return nil, err
+			}
+			values.Values = append(values.Values, v)
+			if p.peek().kind == tokenComma {
+				p.next()
+				continue
+			}
+			if p.peek().kind != tokenRParen {
+				return nil, p.errorf(p.peek(), "expected , or )")
+			}
+			p.next()
+			return values, nil
+		}
+	}
+
+	t = p.next()
+	switch t.kind {
+	case tokenString:
+		return &Literal{Position: t.pos, Text: t.text, Quoted: true}, nil
+	case tokenWord:
+		if t.is("EMPTY") || t.is("NULL") {
+			return &Literal{Position: t.pos, Text: strings.ToUpper(t.text), Empty: true}, nil
+		}
+		if p.peek().kind == tokenLParen {
+			return p.parseFunctionArgs(t)
+		}
+		return &Literal{Position: t.pos, Text: t.text}, nil
+	}
+	return nil, p.errorf(t, "expected value")
+}
+
+func (p *parser) parseFunctionArgs(name token) (ValueNode, error) {
+	p.next()
+	call := &FunctionCall{Position: name.pos, Name: name.text}
+	if p.peek().kind == tokenRParen {
+		p.next()
+		return call, nil
+	}
+	for {
+		t := p.next()
+		if t.kind != tokenWord && t.kind != tokenString {
+			return nil, p.errorf(t, "expected argument of %s", name.text)
+		}
+		call.Args = append(call.Args, &Literal{Position: t.pos, Text: t.text, Quoted: t.kind == tokenString})
+
+		t = p.next()
+		if t.kind == tokenRParen {
+			return call, nil
+		}
+		if t.kind != tokenComma {
+			return nil, p.errorf(t, "expected , or )")
+		}
+	}
+}
+
+// comparisonOperators are the operators written with symbols
+var comparisonOperators = map[Operator]bool{
+	OpEquals: true, OpNotEquals: true, OpGreater: true, OpGreaterOrEqual: true,
+	OpLess: true, OpLessOrEqual: true, OpContains: true, OpNotContains: true,
+}
+
+var predicateKeywords = []string{"FROM", "TO", "BY", "AFTER", "BEFORE", "ON", "DURING"}
+
+func (p *parser) parsePredicates() ([]Predicate, error) {
+	var predicates []Predicate
+	for {
+		t := p.peek()
+		keyword := ""
+		for _, k := range predicateKeywords {
+			if t.is(k) {
+				keyword = k
+			}
+		}
+		if keyword == "" {
+			return predicates, nil
+		}
+		p.next()
+
+		predicate := Predicate{Position: t.pos, Keyword: keyword}
+		if keyword == "DURING" {
+			if p.next().kind != tokenLParen {
+				return nil, p.errorf(p.prev(), "expected ( after DURING")
+			}
+			for i := 0; i < 2; i++ {
+				v, err := p.parseValue(false)
+				if err != nil {
+					return nil, err
+				}
+				predicate.Values = append(predicate.Values, v)
+				if i == 0 && p.next().kind != tokenComma {
+					return nil, p.errorf(p.prev(), "expected , in DURING")
+				}
+			}
+			if p.next().kind != tokenRParen {
+				return nil, p.errorf(p.prev(), "expected ) after DURING")
+			}
+		} else {
+			v, err := p.parseValue(false)
+			if err != nil {
+				return nil, err
+			}
+			predicate.Values = []ValueNode{v}
+		}
+		predicates = append(predicates, predicate)
+	}
+}
+
+func (p *parser) parseSortFields() ([]SortField, error) {
+	var fields []SortField
+	for {
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		sort := SortField{Field: field}
+		if t := p.peek(); t.is("ASC") || t.is("DESC") {
+			p.next()
+			sort.Direction = Direction(strings.ToUpper(t.text))
+		}
+		fields = append(fields, sort)
+
+		if p.peek().kind != tokenComma {
+			return fields, nil
+		}
+		p.next()
+	}
+}
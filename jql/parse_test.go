@@ -0,0 +1,113 @@
+package jql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	tt := map[string]string{
+		`project = TST`:                         `project = TST`,
+		`project=TST and status!="In Progress"`: `project = TST AND status != "In Progress"`,
+		`project = TST AND (assignee = currentUser() OR assignee is EMPTY)`:          `project = TST AND (assignee = currentUser() OR assignee is EMPTY)`,
+		`(x = 1 OR y = 2) AND z = 3`:                                                 `(x = 1 OR y = 2) AND z = 3`,
+		`x = 1 OR y = 2 AND z = 3`:                                                   `x = 1 OR y = 2 AND z = 3`,
+		`x = 1 && y = 2 || !z = 3`:                                                   `x = 1 AND y = 2 OR NOT z = 3`,
+		`NOT (x = 1 OR y = 2)`:                                                       `NOT (x = 1 OR y = 2)`,
+		`status NOT IN (Done, 'Won\'t Fix') order by priority desc, key`:             `status not in (Done, "Won't Fix") ORDER BY priority DESC, key`,
+		`"Story Points" >= 5 AND cf[10001] ~ "foo*"`:                                 `"Story Points" >= 5 AND cf[10001] ~ "foo*"`,
+		`created > startOfDay(-7d) and resolution = null`:                            `created > startOfDay(-7d) AND resolution = EMPTY`,
+		`status was in (Open, "In Progress") by jsmith during ("2021/01/01", now())`: `status was in (Open, "In Progress") BY jsmith DURING ("2021/01/01", now())`,
+		`status changed from Open to Done after -1w`:                                 `status changed FROM Open TO Done AFTER -1w`,
+		`issuekey in linkedIssues("TST-1", "blocks")`:                                `issuekey in linkedIssues("TST-1", "blocks")`,
+		`order by created`:                                                           `ORDER BY created`,
+		`summary ~ "and"`:                                                            `summary ~ "and"`,
+		"labels = voilà AND x\u00a0=\u00a01":                                         `labels = voilà AND x = 1`,
+	}
+	for query, expected := range tt {
+		stmt, err := Parse(query)
+		if err != nil {
+			t.Errorf("%s: %s", query, err)
+			continue
+		}
+		if got := stmt.String(); got != expected {
+			t.Errorf("%s:\nExpected %s\ngot      %s", query, expected, got)
+			continue
+		}
+		again, err := Parse(expected)
+		if err != nil {
+			t.Errorf("%s: %s", expected, err)
+			continue
+		}
+		if got := again.String(); got != expected {
+			t.Errorf("Formatting is not stable: %s became %s", expected, got)
+		}
+	}
+}
+
+func TestParse_AST(t *testing.T) {
+	stmt, err := Parse(`project = TST AND NOT status in (Done, Closed)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	and, ok := stmt.Where.(*LogicalExpr)
+	if !ok || and.Operator != "AND" || len(and.Operands) != 2 {
+		t.Fatalf("Unexpected condition %#v", stmt.Where)
+	}
+	term, ok := and.Operands[0].(*TermExpr)
+	if !ok || term.Field.Name != "project" || term.Operator != OpEquals || term.Pos() != 0 {
+		t.Errorf("Unexpected first operand %#v", and.Operands[0])
+	}
+	not, ok := and.Operands[1].(*NotExpr)
+	if !ok || not.Pos() != 18 {
+		t.Fatalf("Unexpected second operand %#v", and.Operands[1])
+	}
+	in, ok := not.X.(*TermExpr)
+	if !ok || in.Operator != OpIn {
+		t.Fatalf("Unexpected negated operand %#v", not.X)
+	}
+	list, ok := in.Value.(*List)
+	if !ok || len(list.Values) != 2 || list.Values[1].(*Literal).Text != "Closed" || list.Values[1].Pos() != 39 {
+		t.Errorf("Unexpected list %#v", in.Value)
+	}
+}
+
+func TestStatement_Pretty(t *testing.T) {
+	stmt, err := Parse(`project = TST AND (assignee = currentUser() OR reporter = currentUser()) ORDER BY key`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "project = TST\n" +
+		"AND (\n" +
+		"  assignee = currentUser()\n" +
+		"  OR reporter = currentUser()\n" +
+		")\n" +
+		"ORDER BY key"
+	if got := stmt.Pretty("  "); got != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestParse_SyntaxError(t *testing.T) {
+	tt := map[string]int{
+		`project = `:                 10,
+		`project = TST AND`:          17,
+		`project TST`:                8,
+		`status in (Open, Done`:      21,
+		`summary ~ "unterminated`:    10,
+		`(project = TST`:             14,
+		`project = TST ORDER status`: 20,
+		`project == TST`:             8,
+	}
+	for query, pos := range tt {
+		_, err := Parse(query)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%s: Expected syntax error, got %v", query, err)
+			continue
+		}
+		if syntaxErr.Pos != pos {
+			t.Errorf("%s: Expected error at %d, got %s", query, pos, syntaxErr)
+		}
+	}
+}
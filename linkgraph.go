@@ -0,0 +1,439 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// LinkDirection selects which links of an issue are followed when building a link graph
+type LinkDirection int
+
+// These constants are the directions in which links are followed.
+// An outward link of an issue is a link where the issue is the source, e.g. "TST-1 blocks TST-2" for TST-1.
+const (
+	LinkDirectionOutward LinkDirection = 1 << iota
+	LinkDirectionInward
+	LinkDirectionBoth = LinkDirectionOutward | LinkDirectionInward
+)
+
+// maxKeysPerLinkGraphQuery is the maximum number of issue keys loaded with one JQL query of the link graph builder
+const maxKeysPerLinkGraphQuery = 100
+
+// LinkGraphOptions specifies the optional parameters for building a link graph
+type LinkGraphOptions struct {
+	// LinkTypes are the names of the link types to follow, e.g. "Blocks". By default all link types are followed.
+	LinkTypes []string
+	// Direction is the direction in which links are followed. Default: LinkDirectionBoth.
+	Direction LinkDirection
+	// MaxDepth is the number of links followed from the seed issues. 0 means no limit.
+	MaxDepth int
+}
+
+// LinkGraph is a directed graph of issues and the links between them.
+// Every edge points from the outward to the inward side of a link, e.g. from the blocking to the blocked issue.
+type LinkGraph struct {
+	Nodes map[string]*LinkGraphNode `json:"nodes"`
+	Edges []LinkGraphEdge           `json:"edges"`
+}
+
+// LinkGraphNode is an issue in a link graph.
+// Depth is the number of links between the issue and the nearest seed issue.
+type LinkGraphNode struct {
+	Key               string `json:"key"`
+	Summary           string `json:"summary,omitempty"`
+	Status            string `json:"status,omitempty"`
+	Type              string `json:"type,omitempty"`
+	RemainingEstimate int    `json:"remainingEstimate,omitempty"`
+	Depth             int    `json:"depth"`
+}
+
+// LinkGraphEdge is a link between two issues of a link graph
+type LinkGraphEdge struct {
+	ID    string `json:"id,omitempty"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+// GetLinkGraphWithContext builds the link graph starting at the seed issues.
+// The graph is expanded level by level, loading the linked issues of a level with batched JQL queries.
+func (s *IssueService) GetLinkGraphWithContext(ctx context.Context, seeds []string, options *LinkGraphOptions) (*LinkGraph, error) {
+	if options == nil {
+		options = &LinkGraphOptions{}
+	}
+	direction := options.Direction
+	if direction == 0 {
+		direction = LinkDirectionBoth
+	}
+	followed := make(map[string]bool, len(options.LinkTypes))
+	for _, name := range options.LinkTypes {
+		followed[strings.ToLower(name)] = true
+	}
+
+	graph := &LinkGraph{Nodes: make(map[string]*LinkGraphNode)}
+	edges := make(map[string]bool)
+	loaded := make(map[string]bool)
+	fields := []string{"summary", "status", "issuetype", "timeestimate", "issuelinks"}
+
+	frontier := seeds
+	for depth := 0; len(frontier) > 0; depth++ {
+		var next []string
+		for _, chunk := range chunkStrings(frontier, maxKeysPerLinkGraphQuery) {
+			jql := fmt.Sprintf("issuekey in (%s)", strings.Join(chunk, ", "))
+			// SearchPagesWithContext advances StartAt, so every query needs its own options
+			err := s.SearchPagesWithContext(ctx, jql, &SearchOptions{MaxResults: 100, Fields: fields}, func(issue Issue) error {
+				loaded[issue.Key] = true
+				graph.addNode(&issue, depth)
+				if issue.Fields == nil {
+					return nil
+				}
+
+				for _, link := range issue.Fields.IssueLinks {
+					if len(followed) > 0 && !followed[strings.ToLower(link.Type.Name)] {
+						continue
+					}
+					var other *Issue
+					edge := LinkGraphEdge{ID: link.ID, Type: link.Type.Name, Label: link.Type.Outward}
+					if link.OutwardIssue != nil && direction&LinkDirectionOutward != 0 {
+						other = link.OutwardIssue
+						edge.From, edge.To = issue.Key, other.Key
+					} else if link.InwardIssue != nil && direction&LinkDirectionInward != 0 {
+						other = link.InwardIssue
+						edge.From, edge.To = other.Key, issue.Key
+					} else {
+						continue
+					}
+
+					id := edge.ID
+					if id == "" {
+						id = edge.From + " " + edge.Type + " " + edge.To
+					}
+					if !edges[id] {
+						edges[id] = true
+						graph.Edges = append(graph.Edges, edge)
+					}
+
+					if _, ok := graph.Nodes[other.Key]; !ok {
+						graph.addNode(other, depth+1)
+						// the links of issues at MaxDepth are not followed, so they are not loaded
+						if options.MaxDepth == 0 || depth+1 < options.MaxDepth {
+							next = append(next, other.Key)
+						}
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		frontier = nil
+		for _, key := range next {
+			if !loaded[key] {
+				frontier = append(frontier, key)
+			}
+		}
+	}
+
+	return graph, nil
+}
+
+// GetLinkGraph wraps GetLinkGraphWithContext using the background context.
+func (s *IssueService) GetLinkGraph(seeds []string, options *LinkGraphOptions) (*LinkGraph, error) {
+	return s.GetLinkGraphWithContext(context.Background(), seeds, options)
+}
+
+// GetLinkGraphByJQLWithContext builds the link graph starting at all issues matching the JQL query.
+func (s *IssueService) GetLinkGraphByJQLWithContext(ctx context.Context, jql string, options *LinkGraphOptions) (*LinkGraph, error) {
+	var seeds []string
+	err := s.SearchPagesWithContext(ctx, jql, &SearchOptions{MaxResults: 100, Fields: []string{"key"}}, func(issue Issue) error {
+		seeds = append(seeds, issue.Key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetLinkGraphWithContext(ctx, seeds, options)
+}
+
+// GetLinkGraphByJQL wraps GetLinkGraphByJQLWithContext using the background context.
+func (s *IssueService) GetLinkGraphByJQL(jql string, options *LinkGraphOptions) (*LinkGraph, error) {
+	return s.GetLinkGraphByJQLWithContext(context.Background(), jql, options)
+}
+
+// addNode adds an issue to the graph, or completes the node if it was only known from a link
+func (g *LinkGraph) addNode(issue *Issue, depth int) {
+	node, ok := g.Nodes[issue.Key]
+	if !ok {
+		node = &LinkGraphNode{Key: issue.Key, Depth: depth}
+		g.Nodes[issue.Key] = node
+	}
+	if issue.Fields == nil {
+		return
+	}
+	if issue.Fields.Summary != "" {
+		node.Summary = issue.Fields.Summary
+	}
+	if issue.Fields.Status != nil && issue.Fields.Status.Name != "" {
+		node.Status = issue.Fields.Status.Name
+	}
+	if issue.Fields.Type.Name != "" {
+		node.Type = issue.Fields.Type.Name
+	}
+	if issue.Fields.TimeEstimate != 0 {
+		node.RemainingEstimate = issue.Fields.TimeEstimate
+	}
+}
+
+// keys returns the keys of all nodes, sorted
+func (g *LinkGraph) keys() []string {
+	keys := make([]string, 0, len(g.Nodes))
+	for key := range g.Nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// successors returns the adjacency lists of the edges of the given link type (all edges if linkType is empty)
+func (g *LinkGraph) successors(linkType string) map[string][]string {
+	successors := make(map[string][]string)
+	for _, edge := range g.Edges {
+		if linkType != "" && !strings.EqualFold(edge.Type, linkType) {
+			continue
+		}
+		successors[edge.From] = append(successors[edge.From], edge.To)
+	}
+	for key := range successors {
+		sort.Strings(successors[key])
+	}
+	return successors
+}
+
+// Cycles returns the cycles formed by the links of the given type (all links if linkType is empty),
+// e.g. issues which block each other. Every cycle is returned as the sorted keys of the issues in it.
+func (g *LinkGraph) Cycles(linkType string) [][]string {
+	successors := g.successors(linkType)
+
+	// Tarjan's algorithm for strongly connected components
+	index := 0
+	indices := make(map[string]int)
+	lowlink := make(map[string]int)
+	onStack := make(map[string]bool)
+	var stack []string
+	var cycles [][]string
+
+	var connect func(key string)
+	connect = func(key string) {
+		indices[key] = index
+		lowlink[key] = index
+		index++
+		stack = append(stack, key)
+		onStack[key] = true
+
+		selfLoop := false
+		for _, next := range successors[key] {
+			if next == key {
+				selfLoop = true
+			}
+			if _, ok := indices[next]; !ok {
+				connect(next)
+				if lowlink[next] < lowlink[key] {
+					lowlink[key] = lowlink[next]
+				}
+			} else if onStack[next] && indices[next] < lowlink[key] {
+				lowlink[key] = indices[next]
+			}
+		}
+
+		if lowlink[key] == indices[key] {
+			var component []string
+			for {
+				last := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[last] = false
+				component = append(component, last)
+				if last == key {
+					break
+				}
+			}
+			if len(component) > 1 || selfLoop {
+				sort.Strings(component)
+				cycles = append(cycles, component)
+			}
+		}
+	}
+
+	for _, key := range g.keys() {
+		if _, ok := indices[key]; !ok {
+			connect(key)
+		}
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return cycles[i][0] < cycles[j][0] })
+	return cycles
+}
+
+// TopologicalOrder returns the keys of all issues ordered such that the source of every link
+// of the given type (all links if linkType is empty) comes before its target, e.g. blocking before blocked issues.
+// Issues without a mutual order are sorted by key. An error is returned if the links contain a cycle.
+func (g *LinkGraph) TopologicalOrder(linkType string) ([]string, error) {
+	successors := g.successors(linkType)
+	inDegree := make(map[string]int, len(g.Nodes))
+	for _, targets := range successors {
+		for _, target := range targets {
+			inDegree[target]++
+		}
+	}
+
+	var ready []string
+	for _, key := range g.keys() {
+		if inDegree[key] == 0 {
+			ready = append(ready, key)
+		}
+	}
+
+	order := make([]string, 0, len(g.Nodes))
+	for len(ready) > 0 {
+		key := ready[0]
+		ready = ready[1:]
+		order = append(order, key)
+
+		released := false
+		for _, next := range successors[key] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				ready = append(ready, next)
+				released = true
+			}
+		}
+		if released {
+			sort.Strings(ready)
+		}
+	}
+
+	if len(order) < len(g.Nodes) {
+		return nil, fmt.Errorf("the %q links contain a cycle: %v", linkType, g.Cycles(linkType))
+	}
+	return order, nil
+}
+
+// CriticalPath returns the longest chain of issues connected by links of the given type (all links if linkType is empty),
+// together with its total weight. The weight of an issue is given by weight; if weight is nil, every issue counts 1.
+// An error is returned if the links contain a cycle.
+func (g *LinkGraph) CriticalPath(linkType string, weight func(node *LinkGraphNode) float64) ([]string, float64, error) {
+	if weight == nil {
+		weight = func(node *LinkGraphNode) float64 { return 1 }
+	}
+
+	order, err := g.TopologicalOrder(linkType)
+	if err != nil {
+		return nil, 0, err
+	}
+	successors := g.successors(linkType)
+
+	distance := make(map[string]float64, len(order))
+	previous := make(map[string]string, len(order))
+	for _, key := range order {
+		distance[key] = weight(g.Nodes[key])
+	}
+	for _, key := range order {
+		for _, next := range successors[key] {
+			if candidate := distance[key] + weight(g.Nodes[next]); candidate > distance[next] {
+				distance[next] = candidate
+				previous[next] = key
+			}
+		}
+	}
+
+	end, total := "", 0.0
+	for _, key := range order {
+		if end == "" || distance[key] > total {
+			end, total = key, distance[key]
+		}
+	}
+	if end == "" {
+		return nil, 0, nil
+	}
+
+	path := []string{end}
+	for key, ok := previous[end]; ok; key, ok = previous[key] {
+		path = append([]string{key}, path...)
+	}
+	return path, total, nil
+}
+
+// WriteDOT writes the graph in the Graphviz DOT format
+func (g *LinkGraph) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph issues {"); err != nil {
+		return err
+	}
+	for _, key := range g.keys() {
+		node := g.Nodes[key]
+		label := node.Key
+		if node.Summary != "" {
+			label += "\n" + node.Summary
+		}
+		if _, err := fmt.Fprintf(w, "  %s [label=%s];\n", dotQuote(key), dotQuote(label)); err != nil {
+			return err
+		}
+	}
+	for _, edge := range g.Edges {
+		if _, err := fmt.Fprintf(w, "  %s -> %s [label=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(edge.Label)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// WriteMermaid writes the graph as a Mermaid flowchart
+func (g *LinkGraph) WriteMermaid(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "flowchart LR"); err != nil {
+		return err
+	}
+	for _, key := range g.keys() {
+		node := g.Nodes[key]
+		label := node.Key
+		if node.Summary != "" {
+			label += ": " + node.Summary
+		}
+		if _, err := fmt.Fprintf(w, "  %s[\"%s\"]\n", mermaidID(key), mermaidEscape(label)); err != nil {
+			return err
+		}
+	}
+	for _, edge := range g.Edges {
+		if _, err := fmt.Fprintf(w, "  %s -->|%s| %s\n", mermaidID(edge.From), mermaidEscape(edge.Label), mermaidID(edge.To)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the graph as JSON
+func (g *LinkGraph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(g)
+}
+
+// dotQuote quotes a string as DOT identifier
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// mermaidID converts an issue key into a Mermaid node id, as the dash is not allowed in ids
+func mermaidID(key string) string {
+	return strings.ReplaceAll(key, "-", "_")
+}
+
+// mermaidEscape escapes the characters which would end a Mermaid label
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;", "\n", " ").Replace(s)
+}
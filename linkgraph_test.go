@@ -0,0 +1,231 @@
+package jira
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func testLinkGraph() *LinkGraph {
+	return &LinkGraph{
+		Nodes: map[string]*LinkGraphNode{
+			"TST-1": {Key: "TST-1", Summary: "Design", RemainingEstimate: 3},
+			"TST-2": {Key: "TST-2", Summary: "Build", RemainingEstimate: 5},
+			"TST-3": {Key: "TST-3", Summary: "Docs", RemainingEstimate: 1},
+			"TST-4": {Key: "TST-4", Summary: "Release", RemainingEstimate: 1},
+		},
+		Edges: []LinkGraphEdge{
+			{From: "TST-1", To: "TST-2", Type: "Blocks", Label: "blocks"},
+			{From: "TST-1", To: "TST-3", Type: "Blocks", Label: "blocks"},
+			{From: "TST-2", To: "TST-4", Type: "Blocks", Label: "blocks"},
+			{From: "TST-3", To: "TST-4", Type: "Blocks", Label: "blocks"},
+			{From: "TST-4", To: "TST-1", Type: "Relates", Label: "relates to"},
+		},
+	}
+}
+
+func TestIssueService_GetLinkGraph(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch jql := r.URL.Query().Get("jql"); jql {
+		case "issuekey in (TST-1)":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":1,"issues":[{"key":"TST-1","fields":{"summary":"Design","issuelinks":[
+				{"id":"1","type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},"outwardIssue":{"key":"TST-2","fields":{"summary":"Build"}}},
+				{"id":"2","type":{"name":"Relates","inward":"relates to","outward":"relates to"},"outwardIssue":{"key":"TST-9"}},
+				{"id":"3","type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},"inwardIssue":{"key":"TST-3"}}]}}]}`)
+		case "issuekey in (TST-2, TST-3)":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":2,"issues":[
+				{"key":"TST-2","fields":{"summary":"Build","issuelinks":[
+					{"id":"1","type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},"inwardIssue":{"key":"TST-1"}},
+					{"id":"4","type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},"outwardIssue":{"key":"TST-4"}}]}},
+				{"key":"TST-3","fields":{"summary":"Plan","issuelinks":[
+					{"id":"3","type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},"outwardIssue":{"key":"TST-1"}}]}}]}`)
+		default:
+			t.Errorf("Unexpected JQL %q", jql)
+		}
+	})
+
+	graph, err := testClient.Issue.GetLinkGraph([]string{"TST-1"}, &LinkGraphOptions{LinkTypes: []string{"blocks"}, MaxDepth: 2})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(graph.Nodes) != 4 {
+		t.Errorf("Expected 4 nodes, got %d", len(graph.Nodes))
+	}
+	if _, ok := graph.Nodes["TST-9"]; ok {
+		t.Error("Expected the relates link not to be followed")
+	}
+	if node := graph.Nodes["TST-4"]; node == nil || node.Depth != 2 {
+		t.Errorf("Expected TST-4 at depth 2, got %+v", node)
+	}
+	if len(graph.Edges) != 3 {
+		t.Errorf("Expected 3 edges, got %+v", graph.Edges)
+	}
+	if graph.Edges[1].From != "TST-3" || graph.Edges[1].To != "TST-1" {
+		t.Errorf("Expected the inward link to point from TST-3 to TST-1, got %+v", graph.Edges[1])
+	}
+}
+
+func TestIssueService_GetLinkGraph_MaxDepth(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if jql := r.URL.Query().Get("jql"); jql != "issuekey in (TST-1)" {
+			t.Errorf("Expected only the seed issue to be loaded, got %q", jql)
+		}
+		fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":1,"issues":[{"key":"TST-1","fields":{"summary":"Design","issuelinks":[
+			{"id":"1","type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},"outwardIssue":{"key":"TST-2"}},
+			{"id":"3","type":{"name":"Blocks","inward":"is blocked by","outward":"blocks"},"inwardIssue":{"key":"TST-3"}}]}}]}`)
+	})
+
+	graph, err := testClient.Issue.GetLinkGraph([]string{"TST-1"}, &LinkGraphOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(graph.Nodes) != 3 || len(graph.Edges) != 2 {
+		t.Errorf("Expected 3 nodes and 2 edges, got %d and %+v", len(graph.Nodes), graph.Edges)
+	}
+	for key, node := range graph.Nodes {
+		if node.Depth > 1 {
+			t.Errorf("Expected no issue beyond one link, got %s at depth %d", key, node.Depth)
+		}
+	}
+}
+
+func TestIssueService_GetLinkGraph_Pages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// TST-1 blocks 60 issues, each of which starts a chain of two more issues, so every level needs two pages
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		var issues []string
+		for _, key := range testKeyList(r.URL.Query().Get("jql")) {
+			var blocked []int
+			n, _ := strconv.Atoi(strings.TrimPrefix(key, "TST-"))
+			switch {
+			case n == 1:
+				for i := 1; i <= 60; i++ {
+					blocked = append(blocked, 1000+i)
+				}
+			case n > 1000 && n < 3000:
+				blocked = append(blocked, n+1000)
+			}
+			var links []string
+			for _, other := range blocked {
+				links = append(links, fmt.Sprintf(`{"id":"%d","type":{"name":"Blocks","outward":"blocks"},"outwardIssue":{"key":"TST-%d"}}`, other, other))
+			}
+			issues = append(issues, fmt.Sprintf(`{"key":%q,"fields":{"issuelinks":[%s]}}`, key, strings.Join(links, ",")))
+		}
+		testSearchPage(t, w, r, issues)
+	})
+
+	graph, err := testClient.Issue.GetLinkGraph([]string{"TST-1"}, nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(graph.Nodes) != 181 || len(graph.Edges) != 180 {
+		t.Errorf("Expected 181 nodes and 180 edges, got %d and %d", len(graph.Nodes), len(graph.Edges))
+	}
+}
+
+func TestLinkGraph_TopologicalOrder(t *testing.T) {
+	graph := testLinkGraph()
+
+	order, err := graph.TopologicalOrder("Blocks")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if !reflect.DeepEqual(order, []string{"TST-1", "TST-2", "TST-3", "TST-4"}) {
+		t.Errorf("Unexpected order %v", order)
+	}
+
+	if _, err := graph.TopologicalOrder(""); err == nil {
+		t.Error("Expected an error for the cycle over all link types")
+	}
+}
+
+func TestLinkGraph_Cycles(t *testing.T) {
+	graph := testLinkGraph()
+
+	if cycles := graph.Cycles("blocks"); len(cycles) != 0 {
+		t.Errorf("Expected no blocking cycles, got %v", cycles)
+	}
+	cycles := graph.Cycles("")
+	if !reflect.DeepEqual(cycles, [][]string{{"TST-1", "TST-2", "TST-3", "TST-4"}}) {
+		t.Errorf("Unexpected cycles %v", cycles)
+	}
+}
+
+func TestLinkGraph_CriticalPath(t *testing.T) {
+	graph := testLinkGraph()
+
+	path, total, err := graph.CriticalPath("Blocks", func(node *LinkGraphNode) float64 {
+		return float64(node.RemainingEstimate)
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if !reflect.DeepEqual(path, []string{"TST-1", "TST-2", "TST-4"}) || total != 9 {
+		t.Errorf("Unexpected critical path %v with weight %g", path, total)
+	}
+
+	path, total, _ = graph.CriticalPath("Blocks", nil)
+	if len(path) != 3 || total != 3 {
+		t.Errorf("Expected a path of 3 issues, got %v with weight %g", path, total)
+	}
+}
+
+func TestLinkGraph_WriteDOT(t *testing.T) {
+	graph := &LinkGraph{
+		Nodes: map[string]*LinkGraphNode{"TST-1": {Key: "TST-1", Summary: `Say "hi"`}, "TST-2": {Key: "TST-2"}},
+		Edges: []LinkGraphEdge{{From: "TST-1", To: "TST-2", Type: "Blocks", Label: "blocks"}},
+	}
+
+	var buf bytes.Buffer
+	if err := graph.WriteDOT(&buf); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	expected := "digraph issues {\n" +
+		`  "TST-1" [label="TST-1\nSay \"hi\""];` + "\n" +
+		`  "TST-2" [label="TST-2"];` + "\n" +
+		`  "TST-1" -> "TST-2" [label="blocks"];` + "\n" +
+		"}\n"
+	if buf.String() != expected {
+		t.Errorf("Expected DOT\n%s\ngot\n%s", expected, buf.String())
+	}
+}
+
+func TestLinkGraph_WriteMermaid(t *testing.T) {
+	graph := &LinkGraph{
+		Nodes: map[string]*LinkGraphNode{"TST-1": {Key: "TST-1", Summary: "Design"}, "TST-2": {Key: "TST-2"}},
+		Edges: []LinkGraphEdge{{From: "TST-1", To: "TST-2", Type: "Blocks", Label: "blocks"}},
+	}
+
+	var buf bytes.Buffer
+	if err := graph.WriteMermaid(&buf); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	expected := "flowchart LR\n  TST_1[\"TST-1: Design\"]\n  TST_2[\"TST-2\"]\n  TST_1 -->|blocks| TST_2\n"
+	if buf.String() != expected {
+		t.Errorf("Expected Mermaid\n%s\ngot\n%s", expected, buf.String())
+	}
+}
+
+func TestLinkGraph_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testLinkGraph().WriteJSON(&buf); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if !strings.Contains(buf.String(), `"from": "TST-1"`) {
+		t.Errorf("Expected edges in the JSON output, got %s", buf.String())
+	}
+}
@@ -0,0 +1,115 @@
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// The kinds of journal entries
+const (
+	kindIssue      = "issue"
+	kindReferences = "references"
+	kindStatus     = "status"
+	kindComment    = "comment"
+	kindWorklog    = "worklog"
+	kindAttachment = "attachment"
+	kindLink       = "link"
+	kindRemoteLink = "remotelink"
+)
+
+// Journal records the objects copied by a migration, mapping their ids in the source instance
+// to their ids in the target instance. It is an append-only file with one JSON object per line,
+// so an interrupted migration resumes after the last recorded step.
+type Journal struct {
+	mutex   sync.Mutex
+	file    *os.File
+	entries map[string]string
+}
+
+type journalEntry struct {
+	Kind   string `json:"kind"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// OpenJournal opens the journal in file, creating it if needed, and loads its entries.
+// A last line left incomplete by a crash is discarded.
+func OpenJournal(file string) (*Journal, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if end := bytes.LastIndexByte(data, '\n'); end+1 < len(data) {
+		data = data[:end+1]
+		if err := os.Truncate(file, int64(len(data))); err != nil {
+			return nil, err
+		}
+	}
+
+	j := &Journal{entries: make(map[string]string)}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid journal %s, line %d: %w", file, line, err)
+		}
+		j.entries[journalKey(entry.Kind, entry.Source)] = entry.Target
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	j.file, err = os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// NewMemoryJournal returns a journal which is not persisted, for migrations which need not be resumed
+func NewMemoryJournal() *Journal {
+	return &Journal{entries: make(map[string]string)}
+}
+
+func journalKey(kind, source string) string {
+	return kind + "\x00" + source
+}
+
+// Lookup returns the target id recorded for an object of the source instance
+func (j *Journal) Lookup(kind, source string) (string, bool) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	target, ok := j.entries[journalKey(kind, source)]
+	return target, ok
+}
+
+// Record adds an entry to the journal and writes it to the file
+func (j *Journal) Record(kind, source, target string) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.file != nil {
+		data, err := json.Marshal(journalEntry{Kind: kind, Source: source, Target: target})
+		if err != nil {
+			return err
+		}
+		if _, err := j.file.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	j.entries[journalKey(kind, source)] = target
+	return nil
+}
+
+// Close closes the journal file
+func (j *Journal) Close() error {
+	if j.file == nil {
+		return nil
+	}
+	return j.file.Close()
+}
@@ -0,0 +1,154 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	jira "github.com/perolo/jira-client"
+	"github.com/perolo/jira-client/internal/issuecopy"
+)
+
+// issueKeyPattern matches issue keys in texts, e.g. ABC-123
+var issueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]*-[0-9]+\b`)
+
+// Mapping translates the configuration of the source instance to the target instance.
+// The maps are keyed by the name (or id, for fields) in the source instance;
+// unmapped values keep their name.
+type Mapping struct {
+	// Users maps source users, by email address, name or account id, to target users by email address, name or account id.
+	// Unmapped users are looked up in the target instance by their email address, name and account id.
+	Users map[string]string
+	// Fields maps source field ids to target field ids. Unmapped custom fields are matched by their name;
+	// fields mapped to "" are not migrated.
+	Fields map[string]string
+	// Statuses maps status names. Migrated issues are transitioned into the mapped status of their source.
+	Statuses map[string]string
+	// Priorities maps priority names
+	Priorities map[string]string
+	// IssueTypes maps issue type names
+	IssueTypes map[string]string
+	// LinkTypes maps issue link type names
+	LinkTypes map[string]string
+}
+
+// mapName returns the mapped name, or the name itself if it is not mapped
+func mapName(mapping map[string]string, name string) string {
+	if mapped, ok := mapping[name]; ok {
+		return mapped
+	}
+	return name
+}
+
+// rewriteKeys replaces the keys of migrated issues in a text by their new keys
+func (m *migration) rewriteKeys(text string) string {
+	return issueKeyPattern.ReplaceAllStringFunc(text, func(key string) string {
+		if target, ok := m.journal.Lookup(kindIssue, key); ok {
+			return target
+		}
+		return key
+	})
+}
+
+// targetField returns the id of the target field for a source field, or "" if it has no counterpart
+func (m *migration) targetField(id string) string {
+	if mapped, ok := m.options.Mapping.Fields[id]; ok {
+		return mapped
+	}
+	if !strings.HasPrefix(id, "customfield_") {
+		return id
+	}
+	field, ok := m.sourceFields[id]
+	if !ok {
+		return ""
+	}
+	return m.targetFields[strings.ToLower(field.Name)]
+}
+
+// userWithContext returns the reference to the target user of a source user, given by its email address, name and account id,
+// or nil if the user does not exist in the target instance
+func (m *migration) userWithContext(ctx context.Context, ids ...string) (map[string]string, error) {
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		if mapped, ok := m.options.Mapping.Users[id]; ok {
+			if mapped == "" {
+				return nil, nil
+			}
+			user, err := m.findUserWithContext(ctx, mapped)
+			return userRef(user), err
+		}
+	}
+	for _, id := range ids {
+		if id == "" {
+			continue
+		}
+		user, err := m.findUserWithContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil {
+			return userRef(user), nil
+		}
+	}
+	return nil, nil
+}
+
+// findUserWithContext searches the target user with an email address, name, key or account id.
+// The results are cached, including the users which were not found; failed searches are not.
+func (m *migration) findUserWithContext(ctx context.Context, id string) (*jira.User, error) {
+	if user, ok := m.users[id]; ok {
+		return user, nil
+	}
+	// The query parameter is used by Jira Cloud, the username parameter by Jira Server
+	query := url.QueryEscape(id)
+	users, _, err := m.target.User.FindWithContext(ctx, query, jira.WithUsername(query))
+	if err != nil {
+		return nil, fmt.Errorf("searching user %s: %w", id, err)
+	}
+	var found *jira.User
+	for i := range users {
+		user := &users[i]
+		if strings.EqualFold(user.EmailAddress, id) || strings.EqualFold(user.Name, id) || user.Key == id || user.AccountID == id {
+			found = user
+			break
+		}
+	}
+	m.users[id] = found
+	return found, nil
+}
+
+// userRef returns the reference to a user in a create or edit request
+func userRef(user *jira.User) map[string]string {
+	if user == nil {
+		return nil
+	}
+	if user.AccountID != "" {
+		return map[string]string{"accountId": user.AccountID}
+	}
+	return map[string]string{"name": user.Name}
+}
+
+// converter returns the converter of source issues for their create requests in the target instance
+func (m *migration) converter(ctx context.Context) *issuecopy.Converter {
+	return &issuecopy.Converter{
+		SourceFields: m.sourceFields,
+		Key: func(key string) (string, bool) {
+			return m.journal.Lookup(kindIssue, key)
+		},
+		Text: m.rewriteKeys,
+		User: func(user *jira.User) (map[string]string, error) {
+			return m.userWithContext(ctx, user.EmailAddress, user.Name, user.AccountID)
+		},
+		Field: func(id string) (string, bool) {
+			_, mapped := m.options.Mapping.Fields[id]
+			return m.targetField(id), mapped
+		},
+		Priority: func(name string) string {
+			return mapName(m.options.Mapping.Priorities, name)
+		},
+	}
+}
@@ -0,0 +1,502 @@
+// Package migrate copies projects from one Jira instance to another.
+//
+// Issues are copied with their comments, worklogs, attachments, issue links and remote links.
+// Users, custom fields, statuses, priorities, issue types and link types are translated with a Mapping,
+// and references to migrated issues in descriptions, comments and text fields are rewritten to the new keys.
+// Every copied object is recorded in a Journal, so an interrupted migration can be run again
+// and continues where it stopped.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jira "github.com/perolo/jira-client"
+	"github.com/perolo/jira-client/internal/issuecopy"
+	"github.com/perolo/jira-client/jql"
+)
+
+// Options specifies the optional parameters of Migrate
+type Options struct {
+	// Projects maps source project keys to the keys of the target projects, which must exist.
+	// Unmapped projects keep their key.
+	Projects map[string]string
+	// Mapping translates users, fields and the configuration of the source instance
+	Mapping Mapping
+	// Journal records the progress of the migration. Default: a journal in memory, which can not be resumed.
+	Journal *Journal
+	// PageSize is the number of issues fetched with one search request. Default: 100.
+	PageSize int
+	// Attribute prefixes migrated comments and worklogs with their original author and time,
+	// as they are created in the name of the migrating user
+	Attribute bool
+
+	SkipComments    bool
+	SkipWorklogs    bool
+	SkipAttachments bool
+	SkipLinks       bool
+	SkipRemoteLinks bool
+}
+
+// Problem is a part of a source project which could not be migrated
+type Problem = issuecopy.Problem
+
+// Report is the result of a migration
+type Report struct {
+	// Created counts the issues created by this run, Resumed the issues created by an earlier run according to the journal
+	Created int
+	Resumed int
+	// Versions and Components are the versions and components created in the target projects, as "KEY/name"
+	Versions   []string
+	Components []string
+	// Problems lists everything which could not be migrated
+	Problems []Problem
+}
+
+// migration holds the state of a migration
+type migration struct {
+	source       *jira.Client
+	target       *jira.Client
+	options      Options
+	journal      *Journal
+	report       *Report
+	sourceFields map[string]jira.Field
+	targetFields map[string]string
+	meta         map[string]*jira.MetaProject
+	users        map[string]*jira.User
+}
+
+// MigrateWithContext copies the issues of the source projects into the target instance.
+// Missing versions and components are created first. Then all issues are created, standard issues before sub-tasks,
+// and finally every issue is completed: references to migrated issues are rewritten, the issue is transitioned
+// into its status, and its comments, worklogs, attachments, links and remote links are copied.
+//
+// Parts which can not be migrated are listed as problems in the report; an error is returned
+// if the migration could not run at all or the journal could not be written. Running the migration again
+// with the same journal skips everything which was copied already.
+func MigrateWithContext(ctx context.Context, source, target *jira.Client, projects []string, options *Options) (*Report, error) {
+	m := &migration{
+		source:       source,
+		target:       target,
+		report:       &Report{},
+		sourceFields: make(map[string]jira.Field),
+		targetFields: make(map[string]string),
+		meta:         make(map[string]*jira.MetaProject),
+		users:        make(map[string]*jira.User),
+	}
+	if options != nil {
+		m.options = *options
+	}
+	if m.options.PageSize <= 0 {
+		m.options.PageSize = 100
+	}
+	m.journal = m.options.Journal
+	if m.journal == nil {
+		m.journal = NewMemoryJournal()
+	}
+
+	if err := m.loadFieldsWithContext(ctx); err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		if err := m.prepareProjectWithContext(ctx, project); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, project := range projects {
+		for _, issueTypes := range []string{"standardIssueTypes", "subTaskIssueTypes"} {
+			query := jql.Where(jql.And(
+				jql.Field("project").Eq(jql.String(project)),
+				jql.Field("issuetype").In(jql.Function(issueTypes)),
+			)).OrderBy(jql.Field("key"), jql.Asc).String()
+			err := m.source.Issue.SearchPagesWithContext(ctx, query, m.searchOptions(), func(issue jira.Issue) error {
+				return m.createIssueWithContext(ctx, &issue, m.targetProject(project))
+			})
+			if err != nil {
+				return m.report, err
+			}
+		}
+	}
+
+	for _, project := range projects {
+		query := jql.Where(jql.Field("project").Eq(jql.String(project))).OrderBy(jql.Field("key"), jql.Asc).String()
+		err := m.source.Issue.SearchPagesWithContext(ctx, query, m.searchOptions(), func(issue jira.Issue) error {
+			return m.completeIssueWithContext(ctx, &issue)
+		})
+		if err != nil {
+			return m.report, err
+		}
+	}
+	return m.report, nil
+}
+
+// Migrate wraps MigrateWithContext using the background context.
+func Migrate(source, target *jira.Client, projects []string, options *Options) (*Report, error) {
+	return MigrateWithContext(context.Background(), source, target, projects, options)
+}
+
+func (m *migration) searchOptions() *jira.SearchOptions {
+	return &jira.SearchOptions{MaxResults: m.options.PageSize, Fields: []string{"*all"}}
+}
+
+func (m *migration) targetProject(sourceKey string) string {
+	return mapName(m.options.Projects, sourceKey)
+}
+
+func (m *migration) problem(key, item, message string) {
+	m.report.Problems = append(m.report.Problems, Problem{Key: key, Item: item, Message: message})
+}
+
+// loadFieldsWithContext loads the fields of both instances. Target fields are indexed by their lower case name
+// for the matching of custom fields; ambiguous names are not matched.
+func (m *migration) loadFieldsWithContext(ctx context.Context) error {
+	sourceFields, _, err := m.source.Field.GetListWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	for _, field := range sourceFields {
+		m.sourceFields[field.ID] = field
+	}
+	targetFields, _, err := m.target.Field.GetListWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	for _, field := range targetFields {
+		if !field.Custom {
+			continue
+		}
+		name := strings.ToLower(field.Name)
+		if _, ok := m.targetFields[name]; ok {
+			m.targetFields[name] = ""
+			continue
+		}
+		m.targetFields[name] = field.ID
+	}
+	return nil
+}
+
+// prepareProjectWithContext creates the versions and components of a source project which are missing in its target project
+// and loads the create metadata of the target project
+func (m *migration) prepareProjectWithContext(ctx context.Context, sourceKey string) error {
+	source, _, err := m.source.Project.GetWithContext(ctx, sourceKey)
+	if err != nil {
+		return err
+	}
+	target, _, err := m.target.Project.GetWithContext(ctx, m.targetProject(sourceKey))
+	if err != nil {
+		return err
+	}
+
+	setup := &issuecopy.ProjectSetup{
+		Client:  m.target,
+		Project: target,
+		Prefix:  target.Key + "/",
+		Lead:    m.converter(ctx).User,
+	}
+	setup.CreateVersionsWithContext(ctx, source.Versions)
+	setup.CreateComponentsWithContext(ctx, source.Components)
+	m.report.Versions = append(m.report.Versions, setup.Versions...)
+	m.report.Components = append(m.report.Components, setup.Components...)
+	m.report.Problems = append(m.report.Problems, setup.Problems...)
+
+	meta, _, err := m.target.Issue.GetCreateMetaWithContext(ctx, target.Key)
+	if err != nil {
+		return err
+	}
+	project := meta.GetProjectWithKey(target.Key)
+	if project == nil {
+		return fmt.Errorf("no create permission in project %s", target.Key)
+	}
+	m.meta[target.Key] = project
+	return nil
+}
+
+// createIssueWithContext creates the copy of a source issue, unless the journal records it as created
+func (m *migration) createIssueWithContext(ctx context.Context, source *jira.Issue, projectKey string) error {
+	if _, ok := m.journal.Lookup(kindIssue, source.Key); ok {
+		m.report.Resumed++
+		return nil
+	}
+	project := m.meta[projectKey]
+	typeName := mapName(m.options.Mapping.IssueTypes, source.Fields.Type.Name)
+	issueType := project.GetIssueTypeWithName(typeName)
+	if issueType == nil {
+		m.problem(source.Key, "issue", fmt.Sprintf("issue type %q does not exist in project %s", typeName, projectKey))
+		return nil
+	}
+
+	fields, dropped, err := m.converter(ctx).Fields(source, project.Key, issueType)
+	if err != nil {
+		m.problem(source.Key, "issue", err.Error())
+		return nil
+	}
+	if parent := source.Fields.Parent; parent != nil && fields["parent"] == nil && issueType.Subtasks {
+		m.problem(source.Key, "issue", fmt.Sprintf("parent %s was not migrated", parent.Key))
+		return nil
+	}
+	issue, resp, err := m.target.Issue.CreateWithContext(ctx, &jira.Issue{Fields: &jira.IssueFields{Unknowns: fields}})
+	if err != nil {
+		if resp != nil {
+			err = jira.NewJiraError(resp, err)
+		}
+		m.problem(source.Key, "issue", err.Error())
+		return nil
+	}
+	m.report.Created++
+	if len(dropped) > 0 {
+		m.problem(source.Key, "fields", "not migrated: "+strings.Join(dropped, ", "))
+	}
+	return m.journal.Record(kindIssue, source.Key, issue.Key)
+}
+
+// completeIssueWithContext copies everything of a source issue which needs the copies of all issues:
+// references, status, comments, worklogs, attachments, links and remote links
+func (m *migration) completeIssueWithContext(ctx context.Context, source *jira.Issue) error {
+	target, ok := m.journal.Lookup(kindIssue, source.Key)
+	if !ok {
+		return nil
+	}
+
+	if _, done := m.journal.Lookup(kindReferences, source.Key); !done {
+		if err := m.updateReferencesWithContext(ctx, source, target); err != nil {
+			m.problem(source.Key, "references", err.Error())
+		} else if err := m.journal.Record(kindReferences, source.Key, target); err != nil {
+			return err
+		}
+	}
+
+	if _, done := m.journal.Lookup(kindStatus, source.Key); !done && source.Fields.Status != nil {
+		status := mapName(m.options.Mapping.Statuses, source.Fields.Status.Name)
+		if _, err := m.target.Issue.TransitionToStatusWithContext(ctx, target, status, 0); err != nil {
+			m.problem(source.Key, "status "+status, err.Error())
+		} else if err := m.journal.Record(kindStatus, source.Key, status); err != nil {
+			return err
+		}
+	}
+
+	if !m.options.SkipComments {
+		if err := m.copyCommentsWithContext(ctx, source, target); err != nil {
+			return err
+		}
+	}
+	if !m.options.SkipWorklogs {
+		if err := m.copyWorklogsWithContext(ctx, source, target); err != nil {
+			return err
+		}
+	}
+	if !m.options.SkipAttachments {
+		if err := m.copyAttachmentsWithContext(ctx, source, target); err != nil {
+			return err
+		}
+	}
+	if !m.options.SkipLinks {
+		if err := m.copyLinksWithContext(ctx, source); err != nil {
+			return err
+		}
+	}
+	if !m.options.SkipRemoteLinks {
+		if err := m.copyRemoteLinksWithContext(ctx, source, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateReferencesWithContext rewrites the keys of issues which were created after the copy of the source issue,
+// in its description and text fields
+func (m *migration) updateReferencesWithContext(ctx context.Context, source *jira.Issue, target string) error {
+	fields := make(map[string]interface{})
+	if description := m.rewriteKeys(source.Fields.Description); description != source.Fields.Description {
+		fields["description"] = description
+	}
+	for key, value := range source.Fields.Unknowns {
+		text, ok := value.(string)
+		if !ok || !strings.HasPrefix(key, "customfield_") || !issueKeyPattern.MatchString(text) {
+			continue
+		}
+		field := m.targetField(key)
+		if rewritten := m.rewriteKeys(text); field != "" && rewritten != text {
+			fields[field] = rewritten
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	resp, err := m.target.Issue.UpdateIssueWithContext(ctx, target, map[string]interface{}{"fields": fields})
+	if err != nil {
+		return jira.NewJiraError(resp, err)
+	}
+	jira.Cleanup(resp)
+	return nil
+}
+
+func (m *migration) copyCommentsWithContext(ctx context.Context, source *jira.Issue, target string) error {
+	comments, err := m.source.Issue.GetAllCommentsWithContext(ctx, source.Key)
+	if err != nil {
+		m.problem(source.Key, "comments", err.Error())
+		return nil
+	}
+	for _, comment := range comments {
+		if _, done := m.journal.Lookup(kindComment, comment.ID); done {
+			continue
+		}
+		body := m.rewriteKeys(comment.Body)
+		if m.options.Attribute {
+			body = issuecopy.Attribution(&comment.Author, comment.Created) + body
+		}
+		created, _, err := m.target.Issue.AddCommentWithContext(ctx, target, &jira.Comment{Body: body, Visibility: comment.Visibility})
+		if err != nil {
+			m.problem(source.Key, "comment "+comment.ID, err.Error())
+			continue
+		}
+		if err := m.journal.Record(kindComment, comment.ID, created.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *migration) copyWorklogsWithContext(ctx context.Context, source *jira.Issue, target string) error {
+	worklogs, err := m.source.Issue.GetAllWorklogsWithContext(ctx, source.Key)
+	if err != nil {
+		m.problem(source.Key, "worklogs", err.Error())
+		return nil
+	}
+	for _, worklog := range worklogs {
+		if _, done := m.journal.Lookup(kindWorklog, worklog.ID); done {
+			continue
+		}
+		comment := m.rewriteKeys(worklog.Comment)
+		if m.options.Attribute {
+			comment = issuecopy.Attribution(worklog.Author, worklog.Created) + comment
+		}
+		created, _, err := m.target.Issue.AddWorklogRecordWithContext(ctx, target, &jira.WorklogRecord{
+			Comment:          comment,
+			Started:          worklog.Started,
+			TimeSpentSeconds: worklog.TimeSpentSeconds,
+		})
+		if err != nil {
+			m.problem(source.Key, "worklog "+worklog.ID, err.Error())
+			continue
+		}
+		if err := m.journal.Record(kindWorklog, worklog.ID, created.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyAttachmentsWithContext streams the attachments from the source to the target instance
+func (m *migration) copyAttachmentsWithContext(ctx context.Context, source *jira.Issue, target string) error {
+	for _, attachment := range source.Fields.Attachments {
+		if _, done := m.journal.Lookup(kindAttachment, attachment.ID); done {
+			continue
+		}
+		resp, err := m.source.Issue.DownloadAttachmentWithContext(ctx, attachment.ID)
+		if err != nil {
+			m.problem(source.Key, "attachment "+attachment.Filename, err.Error())
+			continue
+		}
+		created, _, err := m.target.Issue.PostAttachmentWithContext(ctx, target, resp.Body, attachment.Filename)
+		jira.Cleanup(resp)
+		if err != nil {
+			m.problem(source.Key, "attachment "+attachment.Filename, err.Error())
+			continue
+		}
+		id := ""
+		if created != nil && len(*created) > 0 {
+			id = (*created)[0].ID
+		}
+		if err := m.journal.Record(kindAttachment, attachment.ID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyLinksWithContext creates the links between migrated issues. A link is listed by both of its issues
+// and created once, from the side of the issue which is completed first.
+func (m *migration) copyLinksWithContext(ctx context.Context, source *jira.Issue) error {
+	for _, link := range source.Fields.IssueLinks {
+		inward, outward := source.Key, ""
+		if link.OutwardIssue != nil {
+			outward = link.OutwardIssue.Key
+		} else if link.InwardIssue != nil {
+			inward, outward = link.InwardIssue.Key, source.Key
+		} else {
+			continue
+		}
+		id := link.ID
+		if id == "" {
+			id = inward + " " + link.Type.Name + " " + outward
+		}
+		if _, done := m.journal.Lookup(kindLink, id); done {
+			continue
+		}
+		other := outward
+		if other == source.Key {
+			other = inward
+		}
+
+		linkType := mapName(m.options.Mapping.LinkTypes, link.Type.Name)
+		item := fmt.Sprintf("link %q to %s", linkType, other)
+		targetInward, ok := m.journal.Lookup(kindIssue, inward)
+		if !ok {
+			m.problem(source.Key, item, "the linked issue was not migrated")
+			continue
+		}
+		targetOutward, ok := m.journal.Lookup(kindIssue, outward)
+		if !ok {
+			m.problem(source.Key, item, "the linked issue was not migrated")
+			continue
+		}
+
+		_, err := m.target.Issue.AddLinkWithContext(ctx, &jira.IssueLink{
+			Type:         jira.IssueLinkType{Name: linkType},
+			InwardIssue:  &jira.Issue{Key: targetInward},
+			OutwardIssue: &jira.Issue{Key: targetOutward},
+		})
+		if err != nil {
+			m.problem(source.Key, item, err.Error())
+			continue
+		}
+		if err := m.journal.Record(kindLink, id, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *migration) copyRemoteLinksWithContext(ctx context.Context, source *jira.Issue, target string) error {
+	links, _, err := m.source.Issue.GetRemoteLinksWithContext(ctx, source.Key)
+	if err != nil {
+		m.problem(source.Key, "remote links", err.Error())
+		return nil
+	}
+	if links == nil {
+		return nil
+	}
+	for _, link := range *links {
+		id := source.Key + "/" + strconv.Itoa(link.ID)
+		if _, done := m.journal.Lookup(kindRemoteLink, id); done {
+			continue
+		}
+		created, _, err := m.target.Issue.AddRemoteLinkWithContext(ctx, target, &jira.RemoteLink{
+			GlobalID:     link.GlobalID,
+			Application:  link.Application,
+			Relationship: link.Relationship,
+			Object:       link.Object,
+		})
+		if err != nil {
+			m.problem(source.Key, "remote link "+strconv.Itoa(link.ID), err.Error())
+			continue
+		}
+		if err := m.journal.Record(kindRemoteLink, id, strconv.Itoa(created.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,399 @@
+package migrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	jira "github.com/perolo/jira-client"
+)
+
+const sourceIssues = `[
+	{"id": "1", "key": "OLD-1", "fields": {
+		"summary": "First",
+		"description": "See OLD-2",
+		"issuetype": {"name": "Task"},
+		"status": {"name": "Open"},
+		"priority": {"name": "Major"},
+		"assignee": {"name": "jdoe", "emailAddress": "jdoe@example.com", "displayName": "John Doe"},
+		"reporter": {"name": "old.admin", "displayName": "Admin"},
+		"customfield_100": {"self": "http://old/option/7", "id": "7", "value": "A"},
+		"customfield_101": "unmatched",
+		"issuelinks": [{"id": "500", "type": {"name": "Blocks"}, "outwardIssue": {"key": "OLD-2"}}]
+	}},
+	{"id": "2", "key": "OLD-2", "fields": {
+		"summary": "Second",
+		"issuetype": {"name": "Task"},
+		"status": {"name": "Closed"},
+		"issuelinks": [{"id": "500", "type": {"name": "Blocks"}, "inwardIssue": {"key": "OLD-1"}}]
+	}},
+	{"id": "3", "key": "OLD-3", "fields": {
+		"summary": "Third",
+		"issuetype": {"name": "Sub-task"},
+		"status": {"name": "Open"},
+		"parent": {"key": "OLD-1"},
+		"attachment": [{"id": "9", "filename": "notes.txt"}]
+	}}
+]`
+
+func sourceServer(t *testing.T) (*jira.Client, func()) {
+	var issues []json.RawMessage
+	if err := json.Unmarshal([]byte(sourceIssues), &issues); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id": "customfield_100", "name": "Team", "custom": true}, {"id": "customfield_101", "name": "Legacy", "custom": true}]`)
+	})
+	mux.HandleFunc("/rest/api/2/project/OLD", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"id": "1", "key": "OLD", "versions": [{"name": "1.0"}], "components": [
+			{"name": "Core", "lead": {"accountId": "5b10ac8d82e05b22cc7d4ef5"}},
+			{"name": "Docs", "lead": {"name": "ghost"}}]}`)
+	})
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("jql")
+		page := issues
+		if strings.Contains(query, "standardIssueTypes()") {
+			page = issues[:2]
+		} else if strings.Contains(query, "subTaskIssueTypes()") {
+			page = issues[2:]
+		}
+		data, _ := json.Marshal(page)
+		_, _ = fmt.Fprintf(w, `{"startAt": 0, "maxResults": 100, "total": %d, "issues": %s}`, len(page), data)
+	})
+	mux.HandleFunc("/rest/api/2/issue/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/issue/OLD-1/comment":
+			_, _ = fmt.Fprint(w, `{"total": 1, "comments": [{"id": "70", "body": "Related to OLD-3", "author": {"displayName": "John Doe"}}]}`)
+		case "/rest/api/2/issue/OLD-2/remotelink":
+			_, _ = fmt.Fprint(w, `[{"id": 80, "object": {"url": "https://example.com", "title": "Example"}}]`)
+		default:
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/comment"):
+				_, _ = fmt.Fprint(w, `{"total": 0, "comments": []}`)
+			case strings.HasSuffix(r.URL.Path, "/worklog"):
+				_, _ = fmt.Fprint(w, `{"total": 0, "worklogs": []}`)
+			default:
+				_, _ = fmt.Fprint(w, `[]`)
+			}
+		}
+	})
+	mux.HandleFunc("/secure/attachment/9/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "notes")
+	})
+
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server.Close
+}
+
+// testTarget is a Jira instance recording the changes of a migration
+type testTarget struct {
+	mutex    sync.Mutex
+	created  []map[string]interface{}
+	requests []string
+}
+
+func (target *testTarget) record(format string, args ...interface{}) {
+	target.mutex.Lock()
+	defer target.mutex.Unlock()
+	target.requests = append(target.requests, fmt.Sprintf(format, args...))
+}
+
+func (target *testTarget) server(t *testing.T) (*jira.Client, func()) {
+	decode := func(r *http.Request) map[string]interface{} {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Invalid request body: %s", err)
+		}
+		return body
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `[{"id": "customfield_900", "name": "Team", "custom": true}]`)
+	})
+	mux.HandleFunc("/rest/api/2/project/NEW", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"id": "2", "key": "NEW", "versions": [{"name": "1.0"}]}`)
+	})
+	mux.HandleFunc("/rest/api/2/component", func(w http.ResponseWriter, r *http.Request) {
+		body := decode(r)
+		lead := body["leadUserName"]
+		if user, ok := body["lead"].(map[string]interface{}); ok {
+			lead = user["accountId"]
+		}
+		target.record("component %v lead %v", body["name"], lead)
+		_, _ = fmt.Fprint(w, `{"id": "1"}`)
+	})
+	mux.HandleFunc("/rest/api/2/user/search", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("username") {
+		case "jdoe@example.com":
+			_, _ = fmt.Fprint(w, `[{"name": "john", "emailAddress": "jdoe@example.com"}]`)
+		case "5b10ac8d82e05b22cc7d4ef5":
+			_, _ = fmt.Fprint(w, `[{"accountId": "5b10ac8d82e05b22cc7d4ef5"}]`)
+		case "admin":
+			_, _ = fmt.Fprint(w, `[{"name": "admin"}, {"name": "admin2"}]`)
+		default:
+			_, _ = fmt.Fprint(w, `[]`)
+		}
+	})
+	mux.HandleFunc("/rest/api/2/issue/createmeta", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"projects": [{"key": "NEW", "issuetypes": [
+			{"id": "1", "name": "Task", "fields": {"summary": {}, "description": {}, "assignee": {}, "reporter": {}, "priority": {}, "customfield_900": {}}},
+			{"id": "2", "name": "Sub-task", "subtask": true, "fields": {"summary": {}, "parent": {}}}]}]}`)
+	})
+	mux.HandleFunc("/rest/api/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		fields := decode(r)["fields"].(map[string]interface{})
+		target.mutex.Lock()
+		target.created = append(target.created, fields)
+		key := fmt.Sprintf("NEW-%d", len(target.created))
+		target.mutex.Unlock()
+		target.record("create %s", key)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"key": %q}`, key)
+	})
+	mux.HandleFunc("/rest/api/2/issue/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"), "/")
+		switch {
+		case len(parts) == 1 && r.Method == "PUT":
+			target.record("update %s %v", parts[0], decode(r)["fields"])
+			w.WriteHeader(http.StatusNoContent)
+		case len(parts) == 1:
+			_, _ = fmt.Fprintf(w, `{"key": %q, "fields": {"status": {"name": "To Do"}}}`, parts[0])
+		case parts[1] == "transitions" && r.Method == "GET":
+			_, _ = fmt.Fprint(w, `{"transitions": [{"id": "31", "to": {"name": "Done"}}]}`)
+		case parts[1] == "transitions":
+			target.record("transition %s %v", parts[0], decode(r)["transition"])
+			w.WriteHeader(http.StatusNoContent)
+		case parts[1] == "comment":
+			target.record("comment %s %q", parts[0], decode(r)["body"])
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprint(w, `{"id": "170"}`)
+		case parts[1] == "remotelink":
+			object := decode(r)["object"].(map[string]interface{})
+			target.record("remotelink %s %v", parts[0], object["url"])
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprint(w, `{"id": 180}`)
+		case parts[1] == "attachments":
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("Invalid attachment upload: %s", err)
+				return
+			}
+			data, _ := ioutil.ReadAll(file)
+			target.record("attachment %s %s %s", parts[0], header.Filename, data)
+			_, _ = fmt.Fprint(w, `[{"id": "190"}]`)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	mux.HandleFunc("/rest/api/2/issueLink", func(w http.ResponseWriter, r *http.Request) {
+		var link jira.IssueLink
+		if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+			t.Errorf("Invalid link: %s", err)
+		}
+		target.record("link %s %s %s", link.InwardIssue.Key, link.Type.Name, link.OutwardIssue.Key)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server.Close
+}
+
+func TestMigrate(t *testing.T) {
+	source, closeSource := sourceServer(t)
+	defer closeSource()
+	target := &testTarget{}
+	client, closeTarget := target.server(t)
+	defer closeTarget()
+
+	file := filepath.Join(t.TempDir(), "journal")
+	journal, err := OpenJournal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := &Options{
+		Projects: map[string]string{"OLD": "NEW"},
+		Mapping: Mapping{
+			Users:      map[string]string{"old.admin": "admin"},
+			Statuses:   map[string]string{"Open": "To Do", "Closed": "Done"},
+			Priorities: map[string]string{"Major": "High"},
+			LinkTypes:  map[string]string{"Blocks": "Blocker"},
+		},
+		Journal: journal,
+	}
+	report, err := Migrate(source, client, []string{"OLD"}, options)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	_ = journal.Close()
+
+	if report.Created != 3 || report.Resumed != 0 {
+		t.Errorf("Expected 3 created issues, got %+v", report)
+	}
+	if fmt.Sprint(report.Components) != "[NEW/Core NEW/Docs]" || len(report.Versions) != 0 {
+		t.Errorf("Expected only components Core and Docs to be created, got %v and %v", report.Versions, report.Components)
+	}
+
+	first := target.created[0]
+	expected := map[string]string{
+		"description":     "See OLD-2",
+		"priority":        "map[name:High]",
+		"assignee":        "map[name:john]",
+		"reporter":        "map[name:admin]",
+		"customfield_900": "map[value:A]",
+	}
+	for key, value := range expected {
+		if fmt.Sprint(first[key]) != value {
+			t.Errorf("Expected %s %s, got %v", key, value, first[key])
+		}
+	}
+	if fmt.Sprint(target.created[2]["parent"]) != "map[key:NEW-1]" {
+		t.Errorf("Expected the sub-task under NEW-1, got %v", target.created[2]["parent"])
+	}
+
+	sort.Strings(target.requests)
+	expectedRequests := []string{
+		"attachment NEW-3 notes.txt notes",
+		`comment NEW-1 "Related to NEW-3"`,
+		"component Core lead 5b10ac8d82e05b22cc7d4ef5",
+		"component Docs lead <nil>",
+		"create NEW-1",
+		"create NEW-2",
+		"create NEW-3",
+		"link NEW-1 Blocker NEW-2",
+		"remotelink NEW-2 https://example.com",
+		"transition NEW-2 map[id:31]",
+		"update NEW-1 map[description:See NEW-2]",
+	}
+	if strings.Join(target.requests, "\n") != strings.Join(expectedRequests, "\n") {
+		t.Errorf("Expected requests\n%s\ngot\n%s", strings.Join(expectedRequests, "\n"), strings.Join(target.requests, "\n"))
+	}
+
+	var problems []string
+	for _, problem := range report.Problems {
+		problems = append(problems, problem.String())
+	}
+	sort.Strings(problems)
+	if strings.Join(problems, "\n") != "OLD-1 fields: not migrated: Legacy\nlead of component NEW/Docs: no matching user in the target instance" {
+		t.Errorf("Unexpected problems %v", problems)
+	}
+
+	// A second run with the journal copies nothing again
+	target.requests = nil
+	journal, err = OpenJournal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer journal.Close()
+	options.Journal = journal
+	report, err = Migrate(source, client, []string{"OLD"}, options)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if report.Created != 0 || report.Resumed != 3 {
+		t.Errorf("Expected 3 resumed issues, got %+v", report)
+	}
+	if len(target.requests) != 2 || !strings.HasPrefix(target.requests[0], "component Core") || !strings.HasPrefix(target.requests[1], "component Docs") {
+		t.Errorf("Expected no copies in the second run, got %v", target.requests)
+	}
+}
+
+func TestOpenJournal_incompleteLine(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "journal")
+	journal, err := OpenJournal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := journal.Record(kindIssue, "OLD-1", "NEW-1"); err != nil {
+		t.Fatal(err)
+	}
+	_ = journal.Close()
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"kind": "issue", "sou`)
+	_ = f.Close()
+
+	journal, err = OpenJournal(file)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	defer journal.Close()
+	if target, ok := journal.Lookup(kindIssue, "OLD-1"); !ok || target != "NEW-1" {
+		t.Errorf("Expected NEW-1, got %s", target)
+	}
+	if err := journal.Record(kindIssue, "OLD-2", "NEW-2"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadFile(file)
+	if lines := strings.Count(string(data), "\n"); lines != 2 || strings.Contains(string(data), "sou\"") {
+		t.Errorf("Expected two complete lines, got %q", data)
+	}
+}
+
+func TestRewriteKeys(t *testing.T) {
+	journal := NewMemoryJournal()
+	_ = journal.Record(kindIssue, "OLD-1", "NEW-7")
+	m := &migration{journal: journal}
+	got := m.rewriteKeys("OLD-1, OLD-10 and [OLD-1|https://old/browse/OLD-1] but not XOLD-1")
+	expected := "NEW-7, OLD-10 and [NEW-7|https://old/browse/NEW-7] but not XOLD-1"
+	if got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestFindUserWithContext_error(t *testing.T) {
+	searches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searches++
+		switch {
+		case r.URL.Query().Get("username") == "nobody":
+			_, _ = fmt.Fprint(w, `[]`)
+		case searches == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = fmt.Fprint(w, `[{"name": "jdoe"}]`)
+		}
+	}))
+	defer server.Close()
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &migration{target: client, users: make(map[string]*jira.User)}
+
+	if user, err := m.findUserWithContext(context.Background(), "jdoe"); err == nil || user != nil {
+		t.Errorf("Expected the error of the search, got %v, %v", user, err)
+	}
+	if user, err := m.findUserWithContext(context.Background(), "jdoe"); err != nil || user == nil || user.Name != "jdoe" {
+		t.Errorf("Expected the user to be searched again, got %v, %v", user, err)
+	}
+	for i := 0; i < 2; i++ {
+		if user, err := m.findUserWithContext(context.Background(), "nobody"); err != nil || user != nil {
+			t.Errorf("Expected no user, got %v, %v", user, err)
+		}
+	}
+	if searches != 3 {
+		t.Errorf("Expected 3 searches, the missing user cached, got %d", searches)
+	}
+}
@@ -0,0 +1,281 @@
+// Package mirror keeps a local copy of the issues of selected projects and queries it offline.
+//
+// The mirror is a directory with one JSON file per issue, holding the issue with all fields,
+// its comments and its changelog. It is updated incrementally
+// with IssueService.Sync and can be queried with a subset of JQL (see Mirror.Query),
+// so that dashboards and reports do not need to search the Jira instance.
+package mirror
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+	"github.com/perolo/jira-client/jql"
+)
+
+// Mirror is a local copy of Jira issues in a directory
+type Mirror struct {
+	dir    string
+	mutex  sync.RWMutex
+	issues map[string]*entry
+	fields []jira.Field
+}
+
+// entry is a mirrored issue with its fields as generic JSON values, as used by queries
+type entry struct {
+	issue  *jira.Issue
+	fields map[string]interface{}
+}
+
+// SyncOptions specifies the optional parameters of Sync
+type SyncOptions struct {
+	// PageSize is the number of issues fetched with one search request. Default: 100.
+	PageSize int
+	// ReconcileInterval is the time between two searches for deleted issues, see jira.SyncOptions. Default: 24 hours.
+	ReconcileInterval time.Duration
+	// Location is the time zone of the user, see jira.SyncOptions. Default: the time zone of the current user.
+	Location *time.Location
+}
+
+// SyncResult counts the changes of a sync
+type SyncResult struct {
+	Upserted int
+	Deleted  int
+}
+
+// Open opens the mirror in dir, creating the directory if needed, and loads all issues into memory
+func Open(dir string) (*Mirror, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "issues"), 0o755); err != nil {
+		return nil, err
+	}
+	m := &Mirror{dir: dir, issues: make(map[string]*entry)}
+
+	data, err := ioutil.ReadFile(m.fieldsPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &m.fields); err != nil {
+			return nil, fmt.Errorf("invalid field list %s: %w", m.fieldsPath(), err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "issues", "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeEntry(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid issue %s: %w", file, err)
+		}
+		m.issues[e.issue.Key] = e
+	}
+	return m, nil
+}
+
+func decodeEntry(data []byte) (*entry, error) {
+	e := &entry{issue: new(jira.Issue)}
+	if err := json.Unmarshal(data, e.issue); err != nil {
+		return nil, err
+	}
+	var raw struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	e.fields = raw.Fields
+	return e, nil
+}
+
+func (m *Mirror) fieldsPath() string {
+	return filepath.Join(m.dir, "fields.json")
+}
+
+// issuePath returns the file of an issue: issues/<project>/<key>.json
+func (m *Mirror) issuePath(key string) string {
+	project := key
+	if i := strings.LastIndex(key, "-"); i > 0 {
+		project = key[:i]
+	}
+	return filepath.Join(m.dir, "issues", project, key+".json")
+}
+
+// writeFile replaces a file atomically
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// SyncWithContext updates the mirror with the issues of the projects changed since the last sync.
+// The first sync of a set of projects loads all their issues. The search API returns only some of the comments
+// and the latest histories of the changelog of an issue on Jira Cloud; the missing ones are loaded
+// for every changed issue.
+func (m *Mirror) SyncWithContext(ctx context.Context, client *jira.Client, projects []string, options *SyncOptions) (*SyncResult, error) {
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("no projects to mirror")
+	}
+	opts := SyncOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	fields, _, err := client.Field.GetListWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFile(m.fieldsPath(), data); err != nil {
+		return nil, err
+	}
+	m.mutex.Lock()
+	m.fields = fields
+	m.mutex.Unlock()
+
+	sorted := append([]string{}, projects...)
+	sort.Strings(sorted)
+	query := jql.Where(jql.Field("project").In(jql.Strings(sorted...)...)).String()
+
+	result := &SyncResult{}
+	_, err = client.Issue.SyncWithContext(ctx, query, &jira.SyncOptions{
+		Store:             jira.NewFileCheckpointStore(filepath.Join(m.dir, "checkpoints")),
+		Fields:            []string{"*all"},
+		Expand:            "changelog",
+		PageSize:          opts.PageSize,
+		ReconcileInterval: opts.ReconcileInterval,
+		Location:          opts.Location,
+	}, func(event jira.SyncEvent) error {
+		if event.Type == jira.SyncDelete {
+			result.Deleted++
+			return m.delete(event.Key)
+		}
+		result.Upserted++
+		if err := completeWithContext(ctx, client, event.Issue); err != nil {
+			return err
+		}
+		return m.put(event.Issue)
+	})
+	if err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// Sync wraps SyncWithContext using the background context.
+func (m *Mirror) Sync(client *jira.Client, projects []string, options *SyncOptions) (*SyncResult, error) {
+	return m.SyncWithContext(context.Background(), client, projects, options)
+}
+
+// completeWithContext loads the comments and histories of an issue which were not returned by the search
+func completeWithContext(ctx context.Context, client *jira.Client, issue *jira.Issue) error {
+	if issue.Fields == nil {
+		return nil
+	}
+	if comments := issue.Fields.Comments; comments != nil && len(comments.Comments) < comments.Total {
+		all, err := client.Issue.GetAllCommentsWithContext(ctx, issue.Key)
+		if err != nil {
+			return err
+		}
+		comments.Comments = make([]*jira.Comment, len(all))
+		for i := range all {
+			comments.Comments[i] = &all[i]
+		}
+		comments.Total = len(all)
+	}
+	if changelog := issue.Changelog; changelog != nil && len(changelog.Histories) < changelog.Total {
+		histories, err := client.Issue.GetAllChangelogWithContext(ctx, issue.Key)
+		if err != nil {
+			return err
+		}
+		changelog.Histories = histories
+		changelog.Total = len(histories)
+	}
+	return nil
+}
+
+// put stores an issue
+func (m *Mirror) put(issue *jira.Issue) error {
+	data, err := json.Marshal(issue)
+	if err != nil {
+		return err
+	}
+	e, err := decodeEntry(data)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(m.issuePath(issue.Key), data); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	m.issues[issue.Key] = e
+	m.mutex.Unlock()
+	return nil
+}
+
+// delete removes an issue
+func (m *Mirror) delete(key string) error {
+	if err := os.Remove(m.issuePath(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	m.mutex.Lock()
+	delete(m.issues, key)
+	m.mutex.Unlock()
+	return nil
+}
+
+// Get returns the mirrored issue with the given key
+func (m *Mirror) Get(key string) (*jira.Issue, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	e, ok := m.issues[key]
+	if !ok {
+		return nil, false
+	}
+	return e.issue, true
+}
+
+// Len returns the number of mirrored issues
+func (m *Mirror) Len() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.issues)
+}
+
+// Fields returns the fields of the instance, as loaded by the last sync
+func (m *Mirror) Fields() []jira.Field {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.fields
+}
@@ -0,0 +1,205 @@
+package mirror
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+)
+
+const testFields = `[
+	{"id": "summary", "name": "Summary", "schema": {"type": "string"}},
+	{"id": "status", "name": "Status", "schema": {"type": "status"}},
+	{"id": "assignee", "name": "Assignee", "schema": {"type": "user"}},
+	{"id": "created", "name": "Created", "schema": {"type": "datetime"}},
+	{"id": "labels", "name": "Labels", "schema": {"type": "array", "items": "string"}},
+	{"id": "customfield_10001", "name": "Story Points", "custom": true, "clauseNames": ["cf[10001]", "Story Points"], "schema": {"type": "number"}}
+]`
+
+var testIssues = []string{
+	`{"id": "10001", "key": "TST-1", "fields": {"summary": "Login fails on Safari", "project": {"key": "TST", "name": "Test"},
+		"status": {"name": "Open", "statusCategory": {"key": "new", "name": "To Do"}}, "assignee": {"accountId": "abc", "displayName": "Jane Doe"},
+		"created": "2021-03-01T10:00:00.000+0000", "updated": "2021-03-01T10:00:00.000+0000", "labels": ["frontend", "browser"], "customfield_10001": 3,
+		"comment": {"total": 2, "comments": [{"body": "Reproduced with version 14"}]}},
+		"changelog": {"total": 2, "histories": [{"id": "2", "items": [{"field": "status", "fromString": "New", "toString": "Open"}]}]}}`,
+	`{"id": "10002", "key": "TST-2", "fields": {"summary": "Crash on startup", "project": {"key": "TST", "name": "Test"},
+		"status": {"name": "Done", "statusCategory": {"key": "done", "name": "Done"}},
+		"created": "2021-03-05T10:00:00.000+0000", "updated": "2021-03-05T10:00:00.000+0000", "labels": ["backend"], "customfield_10001": 8}}`,
+	`{"id": "10010", "key": "TST-10", "fields": {"summary": "Slow search", "project": {"key": "TST", "name": "Test"},
+		"status": {"name": "In Progress", "statusCategory": {"key": "indeterminate", "name": "In Progress"}}, "assignee": {"accountId": "def", "displayName": "John Roe"},
+		"created": "2021-03-10T10:00:00.000+0000", "updated": "2021-03-10T10:00:00.000+0000"}}`,
+}
+
+func testMirror(t *testing.T) (*Mirror, string) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, testFields)
+	})
+	mux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		if jql := r.URL.Query().Get("jql"); jql != `project in ("ABC", "TST") ORDER BY updated ASC, key ASC` {
+			t.Errorf("Unexpected query %s", jql)
+		}
+		if expand := r.URL.Query().Get("expand"); expand != "changelog" {
+			t.Errorf("Expected the changelog, got %q", expand)
+		}
+		_, _ = fmt.Fprintf(w, `{"startAt": 0, "maxResults": 50, "total": 3, "issues": [%s]}`, strings.Join(testIssues, ","))
+	})
+	// The search returns only some of the comments and histories of TST-1
+	mux.HandleFunc("/rest/api/2/issue/TST-1/comment", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"startAt": 0, "maxResults": 100, "total": 2, "comments": [{"body": "Reported by support"}, {"body": "Reproduced with version 14"}]}`)
+	})
+	mux.HandleFunc("/rest/api/2/issue/TST-1/changelog", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"startAt": 0, "maxResults": 100, "total": 2, "values": [
+			{"id": "1", "items": [{"field": "priority", "fromString": "Major", "toString": "Critical"}]},
+			{"id": "2", "items": [{"field": "status", "fromString": "New", "toString": "Open"}]}]}`)
+	})
+
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	m, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := m.Sync(client, []string{"TST", "ABC"}, &SyncOptions{Location: time.UTC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Upserted != 3 || result.Deleted != 0 {
+		t.Errorf("Unexpected sync result %+v", result)
+	}
+	return m, dir
+}
+
+func keys(issues []*jira.Issue) string {
+	var result []string
+	for _, issue := range issues {
+		result = append(result, issue.Key)
+	}
+	return strings.Join(result, " ")
+}
+
+func TestMirror_SyncAndOpen(t *testing.T) {
+	m, dir := testMirror(t)
+	if m.Len() != 3 {
+		t.Errorf("Expected 3 issues, got %d", m.Len())
+	}
+
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.Len() != 3 || len(reopened.Fields()) != 6 {
+		t.Fatalf("Expected 3 issues and 6 fields, got %d and %d", reopened.Len(), len(reopened.Fields()))
+	}
+	issue, ok := reopened.Get("TST-1")
+	if !ok {
+		t.Fatal("Expected TST-1")
+	}
+	if issue.Fields.Summary != "Login fails on Safari" || issue.Fields.Comments == nil || len(issue.Fields.Comments.Comments) != 2 {
+		t.Errorf("Expected the issue with all comments, got %+v", issue.Fields)
+	}
+	if issue.Changelog == nil || len(issue.Changelog.Histories) != 2 || issue.Changelog.Histories[0].Id != "1" {
+		t.Errorf("Expected the complete changelog, got %+v", issue.Changelog)
+	}
+	if _, ok := reopened.Get("TST-99"); ok {
+		t.Error("Expected no TST-99")
+	}
+}
+
+func TestMirror_Query(t *testing.T) {
+	m, _ := testMirror(t)
+	now := time.Date(2021, time.March, 11, 12, 0, 0, 0, time.UTC)
+	options := &QueryOptions{CurrentUser: "abc", Now: now, Location: time.UTC}
+
+	tt := map[string]string{
+		``:                                      "TST-1 TST-2 TST-10",
+		`project = TST`:                         "TST-1 TST-2 TST-10",
+		`project = Test AND status = done`:      "TST-2",
+		`status != Done`:                        "TST-1 TST-10",
+		`status in (Open, "In Progress")`:       "TST-1 TST-10",
+		`status not in (Open)`:                  "TST-2 TST-10",
+		`statusCategory = "To Do"`:              "TST-1",
+		`assignee is EMPTY`:                     "TST-2",
+		`assignee is not EMPTY`:                 "TST-1 TST-10",
+		`assignee = currentUser()`:              "TST-1",
+		`assignee = "John Roe"`:                 "TST-10",
+		`labels = frontend OR labels = backend`: "TST-1 TST-2",
+		`labels is EMPTY`:                       "TST-10",
+		`summary ~ "crash"`:                     "TST-2",
+		`text ~ "version 14"`:                   "TST-1",
+		`summary !~ crash`:                      "TST-1 TST-10",
+		`"Story Points" > 3`:                    "TST-2",
+		`cf[10001] <= 3`:                        "TST-1",
+		`created >= "2021-03-05"`:               "TST-2 TST-10",
+		`created < "2021/03/05 10:00"`:          "TST-1",
+		`created > -3d`:                         "TST-10",
+		`created >= startOfMonth() AND created < startOfDay(-5)`: "TST-1 TST-2",
+		`created <= endOfWeek(-1)`:                               "TST-1 TST-2",
+		`key > TST-2`:                                            "TST-10",
+		`issuekey in (TST-1, TST-10)`:                            "TST-1 TST-10",
+		`NOT (status = Done OR assignee = abc)`:                  "TST-10",
+		`project = TST ORDER BY created DESC`:                    "TST-10 TST-2 TST-1",
+		`ORDER BY "Story Points" DESC, key`:                      "TST-2 TST-1 TST-10",
+		`ORDER BY assignee`:                                      "TST-1 TST-10 TST-2",
+		`ORDER BY key DESC`:                                      "TST-10 TST-2 TST-1",
+	}
+	for query, expected := range tt {
+		issues, err := m.Query(query, options)
+		if err != nil {
+			t.Errorf("%s: %s", query, err)
+			continue
+		}
+		if got := keys(issues); got != expected {
+			t.Errorf("%s: Expected %s, got %s", query, expected, got)
+		}
+	}
+}
+
+func TestMirror_Query_Unsupported(t *testing.T) {
+	m, _ := testMirror(t)
+	for _, query := range []string{
+		`status was Open`,
+		`status changed`,
+		`assignee in membersOf("jira-users")`,
+		`assignee = currentUser()`,
+		`Stroy = 3`,
+		`project = `,
+	} {
+		if _, err := m.Query(query, nil); err == nil {
+			t.Errorf("%s: Expected an error", query)
+		}
+	}
+}
+
+func TestRelative(t *testing.T) {
+	now := time.Date(2021, time.March, 11, 12, 0, 0, 0, time.UTC)
+	tt := map[string]time.Time{
+		"-7d":    time.Date(2021, time.March, 4, 12, 0, 0, 0, time.UTC),
+		"+1w 2d": time.Date(2021, time.March, 20, 12, 0, 0, 0, time.UTC),
+		"-1M":    time.Date(2021, time.February, 11, 12, 0, 0, 0, time.UTC),
+		"2h30m":  time.Date(2021, time.March, 11, 14, 30, 0, 0, time.UTC),
+	}
+	for s, expected := range tt {
+		shift, ok := relative(s)
+		if !ok {
+			t.Errorf("%s: not parsed", s)
+			continue
+		}
+		if got := shift(now); !got.Equal(expected) {
+			t.Errorf("%s: Expected %s, got %s", s, expected, got)
+		}
+	}
+	if _, ok := relative("yesterday"); ok {
+		t.Error("Expected an invalid relative date")
+	}
+}
@@ -0,0 +1,665 @@
+package mirror
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	jira "github.com/perolo/jira-client"
+	"github.com/perolo/jira-client/jql"
+)
+
+// QueryOptions specifies the context of an offline query
+type QueryOptions struct {
+	// CurrentUser is the account id, name or email address matched by currentUser()
+	CurrentUser string
+	// Now is the time of now() and of relative dates. Default: the current time.
+	Now time.Time
+	// Location is the time zone of dates in the query and of startOfDay() etc. Default: the local time zone.
+	Location *time.Location
+}
+
+// fieldKind selects how the values of a field are compared
+type fieldKind int
+
+const (
+	kindValue fieldKind = iota
+	kindKey
+	kindID
+	kindDate
+	kindText
+)
+
+// fieldRef is a field of a query resolved to the field ids of the issue JSON
+type fieldRef struct {
+	name string
+	ids  []string
+	kind fieldKind
+}
+
+// fieldAliases are the JQL names of system fields which differ from the field ids
+var fieldAliases = map[string]string{
+	"type":            "issuetype",
+	"fixversion":      "fixVersions",
+	"affectedversion": "versions",
+	"component":       "components",
+	"resolved":        "resolutiondate",
+	"due":             "duedate",
+	"statuscategory":  "statusCategory",
+	"created":         "created",
+	"updated":         "updated",
+	"resolutiondate":  "resolutiondate",
+	"duedate":         "duedate",
+	"lastviewed":      "lastViewed",
+}
+
+// dateFields are the system date fields, for mirrors without field list
+var dateFields = map[string]bool{"created": true, "updated": true, "resolutiondate": true, "duedate": true, "lastViewed": true}
+
+var customFieldRef = regexp.MustCompile(`^cf\[(\d+)\]$`)
+
+// query is a compiled offline query
+type query struct {
+	fields  []jira.Field
+	options QueryOptions
+	match   func(e *entry) bool
+	sorts   []sortRef
+}
+
+type sortRef struct {
+	field fieldRef
+	desc  bool
+}
+
+// Query returns the mirrored issues matching a JQL query, sorted by its ORDER BY clause or else by key.
+//
+// The supported subset of JQL: AND, OR, NOT and parentheses; the operators =, !=, IN, NOT IN, IS, IS NOT, ~, !~,
+// >, >=, < and <=; system fields (key, project, summary, status, statusCategory, type, priority, assignee, labels,
+// component, fixVersion, created, updated, resolved, due, text, ...) and custom fields by name or cf[id];
+// dates like "2021-03-01", "2021/03/01 10:00" or "-7d"; the functions currentUser(), now(), startOfDay(),
+// endOfDay(), startOfWeek(), endOfWeek(), startOfMonth(), endOfMonth(), startOfYear() and endOfYear().
+// History operators (WAS, CHANGED) and other functions are rejected with an error.
+func (m *Mirror) Query(jqlQuery string, options *QueryOptions) ([]*jira.Issue, error) {
+	stmt, err := jql.Parse(jqlQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	q := &query{fields: m.fields}
+	if options != nil {
+		q.options = *options
+	}
+	if q.options.Now.IsZero() {
+		q.options.Now = time.Now()
+	}
+	if q.options.Location == nil {
+		q.options.Location = time.Local
+	}
+
+	q.match = func(*entry) bool { return true }
+	if stmt.Where != nil {
+		if q.match, err = q.compile(stmt.Where); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range stmt.OrderBy {
+		ref, err := q.resolve(field.Field)
+		if err != nil {
+			return nil, err
+		}
+		q.sorts = append(q.sorts, sortRef{field: ref, desc: strings.EqualFold(string(field.Direction), "DESC")})
+	}
+
+	var entries []*entry
+	for _, e := range m.issues {
+		if q.match(e) {
+			entries = append(entries, e)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return q.less(entries[i], entries[j]) })
+
+	issues := make([]*jira.Issue, len(entries))
+	for i := range entries {
+		issues[i] = entries[i].issue
+	}
+	return issues, nil
+}
+
+// resolve maps a field name of a query to the field of the issue JSON
+func (q *query) resolve(name jql.FieldName) (fieldRef, error) {
+	lower := strings.ToLower(name.Name)
+	switch lower {
+	case "key", "issuekey", "issue":
+		return fieldRef{name: name.Name, kind: kindKey}, nil
+	case "id":
+		return fieldRef{name: name.Name, kind: kindID}, nil
+	case "text":
+		return fieldRef{name: name.Name, ids: []string{"summary", "description", "environment", "comment"}, kind: kindText}, nil
+	case "summary", "description", "environment", "comment":
+		return fieldRef{name: name.Name, ids: []string{lower}, kind: kindText}, nil
+	}
+
+	id := name.Name
+	if alias, ok := fieldAliases[lower]; ok {
+		id = alias
+	} else if match := customFieldRef.FindStringSubmatch(lower); match != nil {
+		id = "customfield_" + match[1]
+	}
+	if field := q.field(id); field != nil {
+		kind := kindValue
+		if field.Schema.Type == "date" || field.Schema.Type == "datetime" {
+			kind = kindDate
+		}
+		return fieldRef{name: name.Name, ids: []string{field.ID}, kind: kind}, nil
+	}
+	if len(q.fields) > 0 && id == name.Name && !knownSystemField(lower) {
+		return fieldRef{}, fmt.Errorf("unknown field %s", name.Name)
+	}
+	if dateFields[id] {
+		return fieldRef{name: name.Name, ids: []string{id}, kind: kindDate}, nil
+	}
+	return fieldRef{name: name.Name, ids: []string{id}}, nil
+}
+
+// knownSystemField reports whether a field can be queried without being in the field list
+func knownSystemField(name string) bool {
+	switch name {
+	case "project", "status", "statuscategory", "issuetype", "priority", "resolution", "assignee", "reporter", "creator",
+		"labels", "components", "fixversions", "versions", "parent":
+		return true
+	}
+	return false
+}
+
+// field finds a field of the instance by id, name or clause name
+func (q *query) field(name string) *jira.Field {
+	if field := jira.FindFieldByName(q.fields, name); field != nil {
+		return field
+	}
+	for i := range q.fields {
+		for _, clause := range q.fields[i].ClauseNames {
+			if strings.EqualFold(clause, name) {
+				return &q.fields[i]
+			}
+		}
+	}
+	return nil
+}
+
+// values returns the leaf values of a field of an issue; arrays are flattened
+func (q *query) values(e *entry, ref fieldRef) []interface{} {
+	switch ref.kind {
+	case kindKey:
+		return []interface{}{e.issue.Key}
+	case kindID:
+		return []interface{}{e.issue.ID}
+	}
+
+	var values []interface{}
+	for _, id := range ref.ids {
+		var value interface{}
+		if id == "statusCategory" {
+			if status, ok := e.fields["status"].(map[string]interface{}); ok {
+				value = status["statusCategory"]
+			}
+		} else {
+			value = jira.FindFieldValue(e.fields, id)
+		}
+		values = appendLeaves(values, value)
+	}
+	return values
+}
+
+func appendLeaves(values []interface{}, value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return values
+	case string:
+		if v == "" {
+			return values
+		}
+	case []interface{}:
+		for _, item := range v {
+			values = appendLeaves(values, item)
+		}
+		return values
+	case map[string]interface{}:
+		// comments: {"comments": [...]}
+		if comments, ok := v["comments"].([]interface{}); ok {
+			for _, comment := range comments {
+				if c, ok := comment.(map[string]interface{}); ok {
+					values = appendLeaves(values, c["body"])
+				}
+			}
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// candidates returns the strings a value matches: the value itself, or the names, keys and ids of an object
+func candidates(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(v)}
+	case map[string]interface{}:
+		var result []string
+		for _, key := range []string{"key", "name", "value", "id", "accountId", "displayName", "emailAddress"} {
+			if s, ok := v[key].(string); ok && s != "" {
+				result = append(result, s)
+			}
+		}
+		if child, ok := v["child"].(map[string]interface{}); ok {
+			for _, c := range candidates(child) {
+				result = append(result, c)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// operand is a value of a clause: a string and, for dates and date functions, a time
+type operand struct {
+	text  string
+	time  time.Time
+	empty bool
+}
+
+func (q *query) operands(v jql.ValueNode) ([]operand, error) {
+	switch value := v.(type) {
+	case *jql.Literal:
+		return []operand{{text: value.Text, empty: value.Empty}}, nil
+	case *jql.List:
+		var result []operand
+		for _, item := range value.Values {
+			operands, err := q.operands(item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, operands...)
+		}
+		return result, nil
+	case *jql.FunctionCall:
+		return q.function(value)
+	}
+	return nil, fmt.Errorf("missing value")
+}
+
+func (q *query) function(call *jql.FunctionCall) ([]operand, error) {
+	var arg string
+	if len(call.Args) > 0 {
+		arg = call.Args[0].Text
+	}
+	now := q.options.Now.In(q.options.Location)
+	name := strings.ToLower(call.Name)
+	switch name {
+	case "currentuser":
+		if q.options.CurrentUser == "" {
+			return nil, fmt.Errorf("currentUser() needs QueryOptions.CurrentUser")
+		}
+		return []operand{{text: q.options.CurrentUser}}, nil
+	case "now":
+		return []operand{{time: now}}, nil
+	}
+
+	units := map[string]string{"day": "d", "week": "w", "month": "M", "year": "y"}
+	for period, unit := range units {
+		var start bool
+		switch name {
+		case "startof" + period:
+			start = true
+		case "endof" + period:
+		default:
+			continue
+		}
+
+		t := startOf(now, period)
+		if !start {
+			t = endOf(t, period)
+		}
+		if arg != "" {
+			if _, err := strconv.Atoi(strings.TrimPrefix(arg, "+")); err == nil {
+				arg += unit
+			}
+			shift, ok := relative(arg)
+			if !ok {
+				return nil, fmt.Errorf("invalid offset %q of %s()", arg, call.Name)
+			}
+			t = shift(t)
+		}
+		return []operand{{time: t}}, nil
+	}
+	return nil, fmt.Errorf("function %s() is not supported offline", call.Name)
+}
+
+// startOf returns the start of the day, week (Sunday), month or year of t
+func startOf(t time.Time, period string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch period {
+	case "week":
+		return day.AddDate(0, 0, -int(day.Weekday()))
+	case "month":
+		return day.AddDate(0, 0, 1-day.Day())
+	case "year":
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	}
+	return day
+}
+
+// endOf returns the last millisecond of the period starting at start
+func endOf(start time.Time, period string) time.Time {
+	switch period {
+	case "week":
+		start = start.AddDate(0, 0, 7)
+	case "month":
+		start = start.AddDate(0, 1, 0)
+	case "year":
+		start = start.AddDate(1, 0, 0)
+	default:
+		start = start.AddDate(0, 0, 1)
+	}
+	return start.Add(-time.Millisecond)
+}
+
+var relativePart = regexp.MustCompile(`^(\d+)\s*([yMwdhm])\s*`)
+
+// relative parses a relative date like "-7d", "+1w 2d" or "-1M" and returns the function shifting a time by it
+func relative(s string) (func(time.Time) time.Time, bool) {
+	s = strings.TrimSpace(s)
+	sign := 1
+	if strings.HasPrefix(s, "-") {
+		sign, s = -1, s[1:]
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+	if s == "" {
+		return nil, false
+	}
+
+	var years, months, days int
+	var duration time.Duration
+	for s != "" {
+		match := relativePart.FindStringSubmatch(s)
+		if match == nil {
+			return nil, false
+		}
+		n, _ := strconv.Atoi(match[1])
+		n *= sign
+		switch match[2] {
+		case "y":
+			years += n
+		case "M":
+			months += n
+		case "w":
+			days += 7 * n
+		case "d":
+			days += n
+		case "h":
+			duration += time.Duration(n) * time.Hour
+		case "m":
+			duration += time.Duration(n) * time.Minute
+		}
+		s = s[len(match[0]):]
+	}
+	return func(t time.Time) time.Time { return t.AddDate(years, months, days).Add(duration) }, true
+}
+
+var dateLayouts = []string{"2006-01-02 15:04", "2006/01/02 15:04", "2006-01-02", "2006/01/02"}
+
+// operandTime returns the time of an operand: a function result, a date or a relative date
+func (q *query) operandTime(o operand) (time.Time, bool) {
+	if !o.time.IsZero() {
+		return o.time, true
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, o.text, q.options.Location); err == nil {
+			return t, true
+		}
+	}
+	if shift, ok := relative(o.text); ok {
+		return shift(q.options.Now), true
+	}
+	return time.Time{}, false
+}
+
+// compare compares a value of an issue with an operand: -1, 0 or 1, and false if they are not comparable
+func (q *query) compare(ref fieldRef, value interface{}, o operand) (int, bool) {
+	if ref.kind == kindDate {
+		s, _ := value.(string)
+		t, err := jira.ParseTime(s)
+		if err != nil {
+			return 0, false
+		}
+		ot, ok := q.operandTime(o)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case t.Before(ot):
+			return -1, true
+		case t.After(ot):
+			return 1, true
+		}
+		return 0, true
+	}
+
+	if ref.kind == kindKey {
+		return compareKeys(value.(string), o.text), true
+	}
+
+	for _, c := range candidates(value) {
+		a, errA := strconv.ParseFloat(c, 64)
+		b, errB := strconv.ParseFloat(o.text, 64)
+		if errA == nil && errB == nil {
+			switch {
+			case a < b:
+				return -1, true
+			case a > b:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	for _, c := range candidates(value) {
+		if strings.EqualFold(c, o.text) {
+			return 0, true
+		}
+	}
+	if c := candidates(value); len(c) > 0 {
+		return strings.Compare(strings.ToLower(c[0]), strings.ToLower(o.text)), true
+	}
+	return 0, false
+}
+
+// compareKeys compares issue keys by project and number, e.g. TST-9 < TST-10
+func compareKeys(a, b string) int {
+	ia, ib := strings.LastIndex(a, "-"), strings.LastIndex(b, "-")
+	if ia > 0 && ib > 0 && strings.EqualFold(a[:ia], b[:ib]) {
+		na, errA := strconv.Atoi(a[ia+1:])
+		nb, errB := strconv.Atoi(b[ib+1:])
+		if errA == nil && errB == nil {
+			switch {
+			case na < nb:
+				return -1
+			case na > nb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToUpper(a), strings.ToUpper(b))
+}
+
+func (q *query) compile(expr jql.Expr) (func(*entry) bool, error) {
+	switch e := expr.(type) {
+	case *jql.LogicalExpr:
+		operands := make([]func(*entry) bool, len(e.Operands))
+		for i := range e.Operands {
+			var err error
+			if operands[i], err = q.compile(e.Operands[i]); err != nil {
+				return nil, err
+			}
+		}
+		and := e.Operator == "AND"
+		return func(entry *entry) bool {
+			for _, operand := range operands {
+				if operand(entry) != and {
+					return !and
+				}
+			}
+			return and
+		}, nil
+	case *jql.NotExpr:
+		x, err := q.compile(e.X)
+		if err != nil {
+			return nil, err
+		}
+		return func(entry *entry) bool { return !x(entry) }, nil
+	case *jql.TermExpr:
+		return q.compileTerm(e)
+	}
+	return nil, fmt.Errorf("unsupported expression %T", expr)
+}
+
+func (q *query) compileTerm(t *jql.TermExpr) (func(*entry) bool, error) {
+	if len(t.Predicates) > 0 {
+		return nil, fmt.Errorf("history predicates are not supported offline")
+	}
+	ref, err := q.resolve(t.Field)
+	if err != nil {
+		return nil, err
+	}
+	operands, err := q.operands(t.Value)
+	if err != nil && t.Operator != jql.OpChanged {
+		return nil, err
+	}
+
+	empty := len(operands) == 1 && operands[0].empty
+	equals := func(e *entry) bool {
+		for _, value := range q.values(e, ref) {
+			for _, o := range operands {
+				if c, ok := q.compare(ref, value, o); ok && c == 0 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	isEmpty := func(e *entry) bool { return len(q.values(e, ref)) == 0 }
+
+	switch t.Operator {
+	case jql.OpEquals, jql.OpIn, jql.OpIs:
+		if empty {
+			return isEmpty, nil
+		}
+		if t.Operator == jql.OpIs {
+			return nil, fmt.Errorf("IS needs EMPTY")
+		}
+		return equals, nil
+	case jql.OpNotEquals, jql.OpNotIn, jql.OpIsNot:
+		if empty {
+			return func(e *entry) bool { return !isEmpty(e) }, nil
+		}
+		if t.Operator == jql.OpIsNot {
+			return nil, fmt.Errorf("IS NOT needs EMPTY")
+		}
+		return func(e *entry) bool { return !isEmpty(e) && !equals(e) }, nil
+	case jql.OpContains, jql.OpNotContains:
+		contains := q.contains(ref, operands)
+		if t.Operator == jql.OpNotContains {
+			return func(e *entry) bool { return !contains(e) }, nil
+		}
+		return contains, nil
+	case jql.OpGreater, jql.OpGreaterOrEqual, jql.OpLess, jql.OpLessOrEqual:
+		if len(operands) != 1 || empty {
+			return nil, fmt.Errorf("%s needs a single value", t.Operator)
+		}
+		accept := map[jql.Operator]func(int) bool{
+			jql.OpGreater:        func(c int) bool { return c > 0 },
+			jql.OpGreaterOrEqual: func(c int) bool { return c >= 0 },
+			jql.OpLess:           func(c int) bool { return c < 0 },
+			jql.OpLessOrEqual:    func(c int) bool { return c <= 0 },
+		}[t.Operator]
+		return func(e *entry) bool {
+			for _, value := range q.values(e, ref) {
+				if c, ok := q.compare(ref, value, operands[0]); ok && accept(c) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	}
+	return nil, fmt.Errorf("operator %s is not supported offline", t.Operator)
+}
+
+// contains returns the matcher of ~: all words of the operand occur in the text of the field, ignoring case.
+// The wildcards * and ? are ignored.
+func (q *query) contains(ref fieldRef, operands []operand) func(*entry) bool {
+	var words []string
+	for _, o := range operands {
+		for _, word := range strings.Fields(strings.ToLower(o.text)) {
+			if word = strings.Trim(word, `*?"`); word != "" {
+				words = append(words, word)
+			}
+		}
+	}
+	return func(e *entry) bool {
+		var texts []string
+		for _, value := range q.values(e, ref) {
+			texts = append(texts, candidates(value)...)
+		}
+		text := strings.ToLower(strings.Join(texts, "\n"))
+		for _, word := range words {
+			if !strings.Contains(text, word) {
+				return false
+			}
+		}
+		return len(words) > 0
+	}
+}
+
+// less orders two issues by the sort fields, then by key. Issues without value come last.
+func (q *query) less(a, b *entry) bool {
+	for _, s := range q.sorts {
+		va, vb := q.values(a, s.field), q.values(b, s.field)
+		if len(va) == 0 || len(vb) == 0 {
+			if len(va) != len(vb) {
+				return len(va) > 0
+			}
+			continue
+		}
+		c, ok := q.compare(s.field, va[0], q.sortOperand(s.field, vb[0]))
+		if !ok || c == 0 {
+			continue
+		}
+		if s.desc {
+			return c > 0
+		}
+		return c < 0
+	}
+	return compareKeys(a.issue.Key, b.issue.Key) < 0
+}
+
+// sortOperand turns a value of an issue into an operand, to compare it with a value of another issue
+func (q *query) sortOperand(ref fieldRef, value interface{}) operand {
+	if ref.kind == kindDate {
+		s, _ := value.(string)
+		t, _ := jira.ParseTime(s)
+		return operand{time: t}
+	}
+	if c := candidates(value); len(c) > 0 {
+		return operand{text: c[0]}
+	}
+	return operand{}
+}
@@ -0,0 +1,370 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MoveOptions specifies the target and the mappings of IssueService.Move
+type MoveOptions struct {
+	// ProjectKey is the key of the target project. By default the issues stay in their project.
+	ProjectKey string
+	// IssueTypes maps the name of the issue type of an issue to the name of its issue type after the move.
+	// Issue types which are not mapped are kept.
+	IssueTypes map[string]string
+	// Statuses maps the name of the status of an issue to the name of its status after the move.
+	// Statuses which are not mapped are kept.
+	Statuses map[string]string
+	// DeleteSource deletes the original issue after it was recreated in another project.
+	// Otherwise the original issue is kept and linked to the new one.
+	DeleteSource bool
+	// DryRun only computes the result of the move, without changing any issue
+	DryRun bool
+	// MaxTransitionSteps is the maximum number of transitions performed to reach the status of an issue.
+	// Default: 10.
+	MaxTransitionSteps int
+}
+
+// MoveResult holds the result of a move, with one item per issue
+type MoveResult struct {
+	Items []MoveItem
+}
+
+// MoveItem is the result (or for a dry run, the plan) of moving one issue
+type MoveItem struct {
+	Key string
+	// NewKey is the key of the issue in the target project, if the issue was moved to another project
+	NewKey string
+	// IssueType and Status are the issue type and status after the move
+	IssueType string
+	Status    string
+	// DroppedFields are the fields (and component / version names) which can not be carried over to the target
+	DroppedFields []string
+	// Transitions are the names of the transitions which moved the issue into Status
+	Transitions []string
+	Error       error
+}
+
+// Failed returns the items of the issues which could not be moved
+func (r *MoveResult) Failed() []MoveItem {
+	var failed []MoveItem
+	for _, item := range r.Items {
+		if item.Error != nil {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// defaultMaxTransitionSteps is the maximum number of transitions TransitionToStatusWithContext performs by default
+const defaultMaxTransitionSteps = 10
+
+// MoveWithContext moves issues to another issue type and / or project, mapping their status through workflow transitions.
+// Jira has no REST call to move an issue, so:
+//   - within a project, the issue type is changed with the edit issue API,
+//   - into another project, the issue is recreated in the target project (see CloneWithContext)
+//     and the original is deleted or linked to the new issue.
+//
+// Fields which are not available for the target issue type (according to createmeta) and components / versions
+// without an equally named counterpart in the target project are reported as dropped.
+// Moves into a status which is not part of the workflow of the target issue type fail before any issue is changed.
+// Every issue is moved independently; the error of an issue is reported in its item.
+func (s *IssueService) MoveWithContext(ctx context.Context, issueKeys []string, options *MoveOptions) (*MoveResult, error) {
+	if options == nil {
+		options = &MoveOptions{}
+	}
+
+	metas := make(map[string]*MetaProject)
+	statuses := make(map[string][]IssueTypeStatuses)
+	result := &MoveResult{}
+	for _, key := range issueKeys {
+		item := MoveItem{Key: key}
+		item.Error = s.moveIssueWithContext(ctx, &item, metas, statuses, options)
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
+
+// Move wraps MoveWithContext using the background context.
+func (s *IssueService) Move(issueKeys []string, options *MoveOptions) (*MoveResult, error) {
+	return s.MoveWithContext(context.Background(), issueKeys, options)
+}
+
+func (s *IssueService) moveIssueWithContext(ctx context.Context, item *MoveItem, metas map[string]*MetaProject, statuses map[string][]IssueTypeStatuses, options *MoveOptions) error {
+	source, _, err := s.GetWithContext(ctx, item.Key, nil)
+	if err != nil {
+		return err
+	}
+	if source.Fields == nil {
+		return fmt.Errorf("issue %s has no fields", item.Key)
+	}
+
+	projectKey := options.ProjectKey
+	if projectKey == "" {
+		projectKey = source.Fields.Project.Key
+	}
+	project, ok := metas[projectKey]
+	if !ok {
+		meta, _, err := s.GetCreateMetaWithContext(ctx, projectKey)
+		if err != nil {
+			return err
+		}
+		if project = meta.GetProjectWithKey(projectKey); project == nil {
+			return fmt.Errorf("project %s is not available for creating issues", projectKey)
+		}
+		metas[projectKey] = project
+	}
+
+	item.IssueType = source.Fields.Type.Name
+	if mapped, ok := options.IssueTypes[item.IssueType]; ok {
+		item.IssueType = mapped
+	}
+	issueType := project.GetIssueTypeWithName(item.IssueType)
+	if issueType == nil {
+		return fmt.Errorf("issue type %q is not available in project %s", item.IssueType, projectKey)
+	}
+
+	if source.Fields.Status != nil {
+		item.Status = source.Fields.Status.Name
+	}
+	if mapped, ok := options.Statuses[item.Status]; ok {
+		item.Status = mapped
+	}
+	if item.Status != "" {
+		workflows, ok := statuses[projectKey]
+		if !ok {
+			workflows, _, err = s.client.Project.GetStatusesWithContext(ctx, projectKey)
+			if err != nil {
+				return err
+			}
+			statuses[projectKey] = workflows
+		}
+		if !workflowHasStatus(workflows, issueType.Name, item.Status) {
+			return fmt.Errorf("status %q is not in the workflow of issue type %s in project %s", item.Status, issueType.Name, projectKey)
+		}
+	}
+
+	sameProject := strings.EqualFold(source.Fields.Project.Key, projectKey)
+	item.DroppedFields = droppedFields(source, issueType, sameProject)
+	if options.DryRun {
+		return nil
+	}
+
+	target := source.Key
+	if sameProject {
+		if !strings.EqualFold(source.Fields.Type.Name, issueType.Name) {
+			resp, err := s.UpdateIssueWithContext(ctx, source.Key, map[string]interface{}{
+				"fields": map[string]interface{}{"issuetype": map[string]string{"id": issueType.Id}},
+			})
+			if err != nil {
+				return NewJiraError(resp, err)
+			}
+			Cleanup(resp)
+		}
+	} else {
+		clone, err := s.CloneWithContext(ctx, source.Key, &CloneOptions{
+			ProjectKey:   projectKey,
+			IssueType:    issueType.Name,
+			Comments:     true,
+			SkipBackLink: options.DeleteSource,
+		})
+		if clone != nil {
+			item.NewKey = clone.Key
+			target = clone.Key
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	path, err := s.TransitionToStatusWithContext(ctx, target, item.Status, options.MaxTransitionSteps)
+	for _, transition := range path {
+		item.Transitions = append(item.Transitions, transition.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !sameProject && options.DeleteSource {
+		resp, err := s.DeleteWithContext(ctx, source.Key)
+		if err != nil {
+			return NewJiraError(resp, err)
+		}
+		Cleanup(resp)
+	}
+	return nil
+}
+
+// workflowHasStatus reports whether the workflow of an issue type contains a status
+func workflowHasStatus(workflows []IssueTypeStatuses, issueType, status string) bool {
+	for _, workflow := range workflows {
+		if !strings.EqualFold(workflow.Name, issueType) {
+			continue
+		}
+		for _, s := range workflow.Statuses {
+			if strings.EqualFold(s.Name, status) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TransitionToStatusWithContext moves an issue into the status with the given name, if it is not in that status yet,
+// and returns the transitions it performed.
+// Statuses which can not be reached in one step are searched breadth-first: the transitions available in every status
+// the issue passes are remembered, and the issue takes the first step of the shortest known path into the status,
+// or towards the nearest status whose transitions are not known yet.
+// An error is returned if no path leads into the status within maxSteps transitions (0 means 10);
+// the issue is then left in the last status of the returned path.
+func (s *IssueService) TransitionToStatusWithContext(ctx context.Context, issueKey, status string, maxSteps int) ([]Transition, error) {
+	if status == "" {
+		return nil, nil
+	}
+	if maxSteps <= 0 {
+		maxSteps = defaultMaxTransitionSteps
+	}
+
+	issue, _, err := s.GetWithContext(ctx, issueKey, &GetQueryOptions{Fields: "status"})
+	if err != nil {
+		return nil, err
+	}
+	current := ""
+	if issue.Fields != nil && issue.Fields.Status != nil {
+		current = issue.Fields.Status.Name
+	}
+
+	known := make(map[string][]Transition)
+	var path []Transition
+	for !strings.EqualFold(current, status) {
+		if len(path) >= maxSteps {
+			return path, fmt.Errorf("no path of at most %d transitions leads issue %s to status %q (%s)", maxSteps, issueKey, status, formatTransitionPath(path))
+		}
+
+		transitions, _, err := s.GetTransitionsWithContext(ctx, issueKey)
+		if err != nil {
+			return path, err
+		}
+		known[strings.ToLower(current)] = transitions
+
+		next := nextTransition(known, current, status)
+		if next == nil {
+			return path, fmt.Errorf("no transitions lead issue %s from status %q to %q (%s)", issueKey, current, status, formatTransitionPath(path))
+		}
+		resp, err := s.DoTransitionWithContext(ctx, issueKey, next.ID)
+		if err != nil {
+			return path, err
+		}
+		Cleanup(resp)
+		path = append(path, *next)
+		current = next.To.Name
+	}
+	return path, nil
+}
+
+// TransitionToStatus wraps TransitionToStatusWithContext using the background context.
+func (s *IssueService) TransitionToStatus(issueKey, status string, maxSteps int) ([]Transition, error) {
+	return s.TransitionToStatusWithContext(context.Background(), issueKey, status, maxSteps)
+}
+
+// nextTransition searches the known transitions breadth-first, starting at the status from.
+// It returns the first transition of the shortest path into the status to, or if there is none,
+// towards the nearest status with unknown transitions. nil is returned if neither exists.
+func nextTransition(known map[string][]Transition, from, to string) *Transition {
+	type step struct {
+		status string
+		first  *Transition
+	}
+	visited := map[string]bool{strings.ToLower(from): true}
+	queue := []step{{status: strings.ToLower(from)}}
+	var unexplored *Transition
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		transitions, ok := known[current.status]
+		if !ok {
+			if unexplored == nil {
+				unexplored = current.first
+			}
+			continue
+		}
+		for i := range transitions {
+			next := strings.ToLower(transitions[i].To.Name)
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			first := current.first
+			if first == nil {
+				first = &transitions[i]
+			}
+			if next == strings.ToLower(to) {
+				return first
+			}
+			queue = append(queue, step{status: next, first: first})
+		}
+	}
+	return unexplored
+}
+
+// formatTransitionPath formats the statuses passed by a path of transitions
+func formatTransitionPath(path []Transition) string {
+	if len(path) == 0 {
+		return "no transitions performed"
+	}
+	names := make([]string, len(path))
+	for i, transition := range path {
+		names[i] = transition.To.Name
+	}
+	return "passed " + strings.Join(names, " → ")
+}
+
+// droppedFields returns the fields of source with a value which can not be set for the target issue type,
+// and the components and versions which do not exist in the target project.
+func droppedFields(source *Issue, issueType *MetaIssueType, sameProject bool) []string {
+	var dropped []string
+	available := func(key string) bool {
+		_, ok := issueType.Fields[key]
+		return ok
+	}
+
+	for key, value := range source.Fields.Unknowns {
+		if strings.HasPrefix(key, "customfield_") && value != nil && !available(key) {
+			dropped = append(dropped, key)
+		}
+	}
+
+	named := map[string][]string{}
+	for _, component := range source.Fields.Components {
+		named["components"] = append(named["components"], component.Name)
+	}
+	for _, version := range source.Fields.FixVersions {
+		named["fixVersions"] = append(named["fixVersions"], version.Name)
+	}
+	for _, version := range source.Fields.AffectsVersions {
+		named["versions"] = append(named["versions"], version.Name)
+	}
+	for key, names := range named {
+		if !available(key) {
+			dropped = append(dropped, key)
+			continue
+		}
+		kept := make(map[string]bool)
+		for _, value := range metaNamedValues(issueType, key, names, sameProject) {
+			kept[value["name"]] = true
+		}
+		for _, name := range names {
+			if !kept[name] {
+				dropped = append(dropped, fmt.Sprintf("%s/%s", key, name))
+			}
+		}
+	}
+
+	sort.Strings(dropped)
+	return dropped
+}
@@ -0,0 +1,272 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func testMoveHandlers(t *testing.T, issue string, deleted *bool) {
+	testMux.HandleFunc("/rest/api/2/issue/createmeta", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"projects":[
+			{"key":"TST","issuetypes":[{"id":"1","name":"Task","fields":{"summary":{}}},{"id":"2","name":"Bug","fields":{"summary":{},"components":{}}}]},
+			{"key":"OTHER","issuetypes":[{"id":"3","name":"Task","fields":{"summary":{},"components":{"allowedValues":[{"name":"Backend"}]}}}]}]}`)
+	})
+	testMux.HandleFunc("/rest/api/2/project/TST/statuses", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id":"1","name":"Task","statuses":[{"name":"Open"},{"name":"Done"}]},
+			{"id":"2","name":"Bug","statuses":[{"name":"Open"},{"name":"Triaged"}]}]`)
+	})
+	testMux.HandleFunc("/rest/api/2/project/OTHER/statuses", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id":"3","name":"Task","statuses":[{"name":"Open"},{"name":"In Progress"}]}]`)
+	})
+	testMux.HandleFunc("/rest/api/2/issue/TST-1", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, issue)
+		case "PUT":
+			payload := map[string]map[string]map[string]string{}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatal(err)
+			}
+			if payload["fields"]["issuetype"]["id"] != "2" {
+				t.Errorf("Expected the issue type to be changed to Bug, got %v", payload)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case "DELETE":
+			if deleted == nil {
+				t.Error("Expected the issue not to be deleted")
+			} else {
+				*deleted = true
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	})
+}
+
+func TestIssueService_Move_IssueType(t *testing.T) {
+	setup()
+	defer teardown()
+	testMoveHandlers(t, `{"key":"TST-1","fields":{"project":{"key":"TST"},"issuetype":{"name":"Task"},"status":{"name":"Open"},"customfield_10001":"x"}}`, nil)
+
+	transitioned := false
+	testMux.HandleFunc("/rest/api/2/issue/TST-1/transitions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"transitions":[{"id":"11","name":"Start","to":{"name":"In Progress"}},{"id":"21","name":"Triage","to":{"name":"Triaged"}}]}`)
+			return
+		}
+		payload := CreateTransitionPayload{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload.Transition.ID != "21" {
+			t.Errorf("Expected transition 21, got %s", payload.Transition.ID)
+		}
+		transitioned = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	result, err := testClient.Issue.Move([]string{"TST-1"}, &MoveOptions{
+		IssueTypes: map[string]string{"Task": "Bug"},
+		Statuses:   map[string]string{"Open": "Triaged"},
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	item := result.Items[0]
+	if item.Error != nil {
+		t.Fatalf("Error given: %s", item.Error)
+	}
+	if item.IssueType != "Bug" || item.Status != "Triaged" || !transitioned {
+		t.Errorf("Unexpected result %+v", item)
+	}
+	if fmt.Sprint(item.DroppedFields) != "[customfield_10001]" {
+		t.Errorf("Expected customfield_10001 to be dropped, got %v", item.DroppedFields)
+	}
+}
+
+func TestIssueService_Move_DryRun(t *testing.T) {
+	setup()
+	defer teardown()
+	testMoveHandlers(t, `{"key":"TST-1","fields":{"project":{"key":"TST"},"issuetype":{"name":"Task"},"status":{"name":"Open"},"components":[{"name":"Backend"},{"name":"Frontend"}]}}`, nil)
+
+	testMux.HandleFunc("/rest/api/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no issue to be created in a dry run")
+	})
+
+	result, err := testClient.Issue.Move([]string{"TST-1"}, &MoveOptions{ProjectKey: "OTHER", DryRun: true})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	item := result.Items[0]
+	if item.Error != nil || item.IssueType != "Task" || item.Status != "Open" {
+		t.Errorf("Unexpected result %+v", item)
+	}
+	if fmt.Sprint(item.DroppedFields) != "[components/Frontend]" {
+		t.Errorf("Expected the Frontend component to be dropped, got %v", item.DroppedFields)
+	}
+}
+
+func TestIssueService_Move_DryRunUnknownStatus(t *testing.T) {
+	setup()
+	defer teardown()
+	testMoveHandlers(t, `{"key":"TST-1","fields":{"project":{"key":"TST"},"issuetype":{"name":"Task"},"status":{"name":"Done"}}}`, nil)
+
+	result, err := testClient.Issue.Move([]string{"TST-1"}, &MoveOptions{ProjectKey: "OTHER", DryRun: true})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	item := result.Items[0]
+	if item.Error == nil || item.Error.Error() != `status "Done" is not in the workflow of issue type Task in project OTHER` {
+		t.Errorf("Expected the dry run to fail for status Done, got %v", item.Error)
+	}
+}
+
+func TestIssueService_Move_Project(t *testing.T) {
+	setup()
+	defer teardown()
+	deleted := false
+	testMoveHandlers(t, `{"key":"TST-1","fields":{"project":{"key":"TST"},"issuetype":{"name":"Task"},"status":{"name":"Open"},"summary":"Move me"}}`, &deleted)
+
+	testMux.HandleFunc("/rest/api/2/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"20001","key":"OTHER-1"}`)
+	})
+	testMux.HandleFunc("/rest/api/2/issue/TST-1/remotelink", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	testMux.HandleFunc("/rest/api/2/issue/TST-1/comment", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":0,"comments":[]}`)
+	})
+	testMux.HandleFunc("/rest/api/2/issue/OTHER-1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"key":"OTHER-1","fields":{"status":{"name":"Open"}}}`, nil)
+	})
+	testMux.HandleFunc("/rest/api/2/issueLink", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no back link when the source is deleted")
+	})
+
+	result, err := testClient.Issue.Move([]string{"TST-1"}, &MoveOptions{ProjectKey: "OTHER", DeleteSource: true})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	item := result.Items[0]
+	if item.Error != nil {
+		t.Fatalf("Error given: %s", item.Error)
+	}
+	if item.NewKey != "OTHER-1" || !deleted {
+		t.Errorf("Expected TST-1 to be recreated as OTHER-1 and deleted, got %+v", item)
+	}
+}
+
+func TestIssueService_Move_UnknownIssueType(t *testing.T) {
+	setup()
+	defer teardown()
+	testMoveHandlers(t, `{"key":"TST-1","fields":{"project":{"key":"TST"},"issuetype":{"name":"Epic"},"status":{"name":"Open"}}}`, nil)
+
+	result, err := testClient.Issue.Move([]string{"TST-1"}, nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(result.Failed()) != 1 {
+		t.Errorf("Expected the issue to fail, got %+v", result.Items)
+	}
+}
+
+// testWorkflowHandlers serves TST-1 in a classic workflow, where Closed can only be reached through In Progress and Resolved
+func testWorkflowHandlers(t *testing.T, workflow map[string]string) *[]string {
+	status := "Open"
+	var performed []string
+	testMux.HandleFunc("/rest/api/2/issue/TST-1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprintf(w, `{"key":"TST-1","fields":{"status":{"name":%q}}}`, status)
+	})
+	testMux.HandleFunc("/rest/api/2/issue/TST-1/transitions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			fmt.Fprintf(w, `{"transitions":%s}`, workflow[status])
+			return
+		}
+		payload := CreateTransitionPayload{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+		var transitions []Transition
+		if err := json.Unmarshal([]byte(workflow[status]), &transitions); err != nil {
+			t.Fatal(err)
+		}
+		for _, transition := range transitions {
+			if transition.ID == payload.Transition.ID {
+				status = transition.To.Name
+				performed = append(performed, transition.Name)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		t.Errorf("Transition %s is not available in status %s", payload.Transition.ID, status)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	return &performed
+}
+
+func TestIssueService_TransitionToStatus_Path(t *testing.T) {
+	setup()
+	defer teardown()
+	performed := testWorkflowHandlers(t, map[string]string{
+		"Open":        `[{"id":"4","name":"Start","to":{"name":"In Progress"}}]`,
+		"In Progress": `[{"id":"301","name":"Stop","to":{"name":"Open"}},{"id":"5","name":"Resolve","to":{"name":"Resolved"}}]`,
+		"Resolved":    `[{"id":"3","name":"Reopen","to":{"name":"Open"}},{"id":"701","name":"Close","to":{"name":"Closed"}}]`,
+		"Closed":      `[{"id":"3","name":"Reopen","to":{"name":"Open"}}]`,
+	})
+
+	path, err := testClient.Issue.TransitionToStatus("TST-1", "Closed", 0)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(path) != 3 || path[0].Name != "Start" || path[1].Name != "Resolve" || path[2].Name != "Close" {
+		t.Errorf("Expected the path Start, Resolve, Close, got %v", path)
+	}
+	if fmt.Sprint(*performed) != "[Start Resolve Close]" {
+		t.Errorf("Expected Start, Resolve and Close to be performed, got %v", *performed)
+	}
+}
+
+func TestIssueService_TransitionToStatus_MaxSteps(t *testing.T) {
+	setup()
+	defer teardown()
+	testWorkflowHandlers(t, map[string]string{
+		"Open":        `[{"id":"4","name":"Start","to":{"name":"In Progress"}}]`,
+		"In Progress": `[{"id":"5","name":"Resolve","to":{"name":"Resolved"}}]`,
+		"Resolved":    `[{"id":"701","name":"Close","to":{"name":"Closed"}}]`,
+	})
+
+	path, err := testClient.Issue.TransitionToStatus("TST-1", "Closed", 2)
+	if err == nil {
+		t.Fatal("Expected an error for a status more than 2 transitions away")
+	}
+	if len(path) != 2 {
+		t.Errorf("Expected 2 transitions, got %v", path)
+	}
+}
+
+func TestIssueService_TransitionToStatus_Unreachable(t *testing.T) {
+	setup()
+	defer teardown()
+	performed := testWorkflowHandlers(t, map[string]string{
+		"Open":        `[{"id":"4","name":"Start","to":{"name":"In Progress"}}]`,
+		"In Progress": `[{"id":"301","name":"Stop","to":{"name":"Open"}}]`,
+	})
+
+	path, err := testClient.Issue.TransitionToStatus("TST-1", "Closed", 0)
+	if err == nil {
+		t.Fatal("Expected an error for an unreachable status")
+	}
+	if len(path) != 1 || path[0].Name != "Start" || fmt.Sprint(*performed) != "[Start]" {
+		t.Errorf("Expected only Start to be performed while exploring, got %v", path)
+	}
+}
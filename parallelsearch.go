@@ -0,0 +1,303 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultParallelSearchConcurrency is the number of pages fetched in parallel if no concurrency is given
+const defaultParallelSearchConcurrency = 4
+
+// defaultParallelSearchPageSize is the page size of a parallel search if the search options have no MaxResults
+const defaultParallelSearchPageSize = 100
+
+// defaultParallelSearchRetries is the number of retries of a rate limited page if no retries are given
+const defaultParallelSearchRetries = 3
+
+// ParallelSearchOptions specifies how SearchPagesParallel fetches the pages of a search
+type ParallelSearchOptions struct {
+	// Concurrency is the number of pages fetched in parallel. Default: 4.
+	Concurrency int
+	// RequestsPerSecond limits the rate of the search requests. Default: no limit.
+	RequestsPerSecond float64
+	// MaxRetries is the number of retries of a page rejected with 429 Too Many Requests. Default: 3.
+	// The retry waits for the Retry-After header of the response, or else an exponential backoff from one second.
+	MaxRetries int
+}
+
+// searchPage is a page fetched by a worker of a parallel search
+type searchPage struct {
+	index  int
+	result *SearchResult
+	err    error
+}
+
+// searchLimiter limits the rate of search requests. A nil limiter does not limit.
+type searchLimiter struct {
+	ticker *time.Ticker
+}
+
+func newSearchLimiter(requestsPerSecond float64) *searchLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &searchLimiter{ticker: time.NewTicker(time.Duration(float64(time.Second) / requestsPerSecond))}
+}
+
+func (l *searchLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	select {
+	case <-l.ticker.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *searchLimiter) stop() {
+	if l != nil {
+		l.ticker.Stop()
+	}
+}
+
+// retryDelay returns the time to wait before retrying a rate limited request
+func retryDelay(resp *Response, attempt int) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return time.Second << attempt
+}
+
+// searchWithRetry runs a search, retrying it if Jira rejects it with 429 Too Many Requests
+func (s *IssueService) searchWithRetry(ctx context.Context, jql string, options *SearchOptions, retries int, limiter *searchLimiter) (*SearchResult, error) {
+	for attempt := 0; ; attempt++ {
+		if err := limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+		result, resp, err := s.SearchWithContext(ctx, jql, options)
+		if err == nil {
+			return result, nil
+		}
+		if resp == nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= retries {
+			return nil, err
+		}
+		select {
+		case <-time.After(retryDelay(resp, attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// searchParallel fetches the first page of a search, and the remaining pages with bounded parallelism.
+// With ordered set, the issues are delivered in the order of the pages, otherwise as the pages arrive.
+// Issues delivered before (e.g. shifted to the next page by an issue created during the search) are skipped.
+func (s *IssueService) searchParallel(ctx context.Context, jql string, options *SearchOptions, parallel *ParallelSearchOptions, ordered bool, deliver func(Issue) error) error {
+	searchOptions := SearchOptions{MaxResults: defaultParallelSearchPageSize}
+	if options != nil {
+		searchOptions = *options
+		if searchOptions.MaxResults <= 0 {
+			searchOptions.MaxResults = defaultParallelSearchPageSize
+		}
+	}
+	concurrency, retries, requestsPerSecond := defaultParallelSearchConcurrency, defaultParallelSearchRetries, 0.0
+	if parallel != nil {
+		if parallel.Concurrency > 0 {
+			concurrency = parallel.Concurrency
+		}
+		if parallel.MaxRetries > 0 {
+			retries = parallel.MaxRetries
+		}
+		requestsPerSecond = parallel.RequestsPerSecond
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	limiter := newSearchLimiter(requestsPerSecond)
+	defer limiter.stop()
+
+	fetch := func(startAt int) (*SearchResult, error) {
+		pageOptions := searchOptions
+		pageOptions.StartAt = startAt
+		return s.searchWithRetry(ctx, jql, &pageOptions, retries, limiter)
+	}
+
+	seen := make(map[string]bool)
+	emit := func(issues []Issue) error {
+		for _, issue := range issues {
+			if seen[issue.Key] {
+				continue
+			}
+			seen[issue.Key] = true
+			if err := deliver(issue); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	first, err := fetch(searchOptions.StartAt)
+	if err != nil {
+		return err
+	}
+	if err := emit(first.Issues); err != nil {
+		return err
+	}
+
+	// Jira may return fewer issues per page than requested
+	pageSize := first.MaxResults
+	if pageSize <= 0 {
+		pageSize = searchOptions.MaxResults
+	}
+	total, pages := first.Total, 0
+	if total > searchOptions.StartAt+pageSize {
+		pages = (total - searchOptions.StartAt - 1) / pageSize
+	}
+
+	// window bounds the number of pages fetched ahead of the delivered pages
+	window := make(chan struct{}, 2*concurrency)
+	queue := make(chan int)
+	results := make(chan searchPage)
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range queue {
+				result, err := fetch(searchOptions.StartAt + index*pageSize)
+				select {
+				case results <- searchPage{index: index, result: result, err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(queue)
+		for index := 1; index <= pages; index++ {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case queue <- index:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]*SearchResult)
+	next := 1
+	for page := range results {
+		if err != nil {
+			continue
+		}
+		if page.err != nil {
+			err = page.err
+			cancel()
+			continue
+		}
+		if page.result.Total > total {
+			total = page.result.Total
+		}
+		if !ordered {
+			<-window
+			if err = emit(page.result.Issues); err != nil {
+				cancel()
+			}
+			continue
+		}
+		pending[page.index] = page.result
+		for result, ok := pending[next]; ok; result, ok = pending[next] {
+			delete(pending, next)
+			next++
+			<-window
+			if err = emit(result.Issues); err != nil {
+				cancel()
+				break
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Fetch the issues added to the result while the search was running
+	for startAt := searchOptions.StartAt + (pages+1)*pageSize; startAt < total; startAt += pageSize {
+		result, err := fetch(startAt)
+		if err != nil {
+			return err
+		}
+		if result.Total > total {
+			total = result.Total
+		}
+		if err := emit(result.Issues); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchPagesParallelWithContext will get issues from all pages in a search like SearchPagesWithContext,
+// but fetches the pages after the first one in parallel. The issues are passed to f in the order of the search,
+// one at a time. Issues moving between pages while the search runs are delivered only once;
+// sort the search by a stable field (e.g. ORDER BY key) to keep such moves rare.
+// A nil options uses pages of 100 issues.
+//
+// Jira API docs: https://developer.atlassian.com/jiradev/jira-apis/jira-rest-apis/jira-rest-api-tutorials/jira-rest-api-example-query-issues
+func (s *IssueService) SearchPagesParallelWithContext(ctx context.Context, jql string, options *SearchOptions, parallel *ParallelSearchOptions, f func(Issue) error) error {
+	return s.searchParallel(ctx, jql, options, parallel, true, f)
+}
+
+// SearchPagesParallel wraps SearchPagesParallelWithContext using the background context.
+func (s *IssueService) SearchPagesParallel(jql string, options *SearchOptions, parallel *ParallelSearchOptions, f func(Issue) error) error {
+	return s.SearchPagesParallelWithContext(context.Background(), jql, options, parallel, f)
+}
+
+// SearchChanWithContext fetches the pages of a search in parallel and sends the issues to the returned channel
+// as the pages arrive, i.e. not in the order of the search. The issue channel is closed when the search is done;
+// then the error channel yields the error of the search, if any, and is closed.
+// The caller must read all issues or cancel the context.
+//
+// Jira API docs: https://developer.atlassian.com/jiradev/jira-apis/jira-rest-apis/jira-rest-api-tutorials/jira-rest-api-example-query-issues
+func (s *IssueService) SearchChanWithContext(ctx context.Context, jql string, options *SearchOptions, parallel *ParallelSearchOptions) (<-chan Issue, <-chan error) {
+	issues := make(chan Issue)
+	errs := make(chan error, 1)
+	go func() {
+		err := s.searchParallel(ctx, jql, options, parallel, false, func(issue Issue) error {
+			select {
+			case issues <- issue:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(issues)
+		if err != nil {
+			errs <- err
+		}
+		close(errs)
+	}()
+	return issues, errs
+}
+
+// SearchChan wraps SearchChanWithContext using the background context.
+func (s *IssueService) SearchChan(jql string, options *SearchOptions, parallel *ParallelSearchOptions) (<-chan Issue, <-chan error) {
+	return s.SearchChanWithContext(context.Background(), jql, options, parallel)
+}
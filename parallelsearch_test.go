@@ -0,0 +1,189 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// searchPageHandler serves the issues TST-1 to TST-<total> in pages of maxResults
+func searchPageHandler(t *testing.T, total func(startAt int) int, maxResults int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		if startAt == maxResults {
+			// let the second page arrive last
+			time.Sleep(20 * time.Millisecond)
+		}
+		issues := ""
+		for i := startAt; i < startAt+maxResults && i < total(startAt); i++ {
+			if issues != "" {
+				issues += ","
+			}
+			issues += fmt.Sprintf(`{"key": "TST-%d"}`, i+1)
+		}
+		_, _ = fmt.Fprintf(w, `{"startAt": %d, "maxResults": %d, "total": %d, "issues": [%s]}`, startAt, maxResults, total(startAt), issues)
+	}
+}
+
+func TestIssueService_SearchPagesParallel(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", searchPageHandler(t, func(int) int { return 250 }, 20))
+
+	var keys []string
+	err := testClient.Issue.SearchPagesParallel("project = TST ORDER BY key", &SearchOptions{MaxResults: 100}, &ParallelSearchOptions{Concurrency: 3}, func(issue Issue) error {
+		keys = append(keys, issue.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(keys) != 250 {
+		t.Fatalf("Expected 250 issues, got %d", len(keys))
+	}
+	for i, key := range keys {
+		if key != fmt.Sprintf("TST-%d", i+1) {
+			t.Fatalf("Expected TST-%d at %d, got %s", i+1, i, key)
+		}
+	}
+}
+
+func TestIssueService_SearchPagesParallel_Drift(t *testing.T) {
+	setup()
+	defer teardown()
+	var mutex sync.Mutex
+	requests := 0
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests++
+		mutex.Unlock()
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		switch startAt {
+		case 0:
+			_, _ = fmt.Fprint(w, `{"startAt": 0, "maxResults": 2, "total": 4, "issues": [{"key": "TST-1"}, {"key": "TST-2"}]}`)
+		case 2:
+			// TST-2 moved to the second page, and TST-5 was created
+			_, _ = fmt.Fprint(w, `{"startAt": 2, "maxResults": 2, "total": 5, "issues": [{"key": "TST-2"}, {"key": "TST-3"}]}`)
+		case 4:
+			_, _ = fmt.Fprint(w, `{"startAt": 4, "maxResults": 2, "total": 5, "issues": [{"key": "TST-4"}, {"key": "TST-5"}]}`)
+		default:
+			t.Errorf("Unexpected startAt %d", startAt)
+		}
+	})
+
+	var keys []string
+	err := testClient.Issue.SearchPagesParallel("project = TST", &SearchOptions{MaxResults: 2}, nil, func(issue Issue) error {
+		keys = append(keys, issue.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if fmt.Sprint(keys) != "[TST-1 TST-2 TST-3 TST-4 TST-5]" {
+		t.Errorf("Unexpected issues %v", keys)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
+
+func TestIssueService_SearchPagesParallel_RateLimited(t *testing.T) {
+	setup()
+	defer teardown()
+	var mutex sync.Mutex
+	rejected := false
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		mutex.Lock()
+		reject := startAt == 1 && !rejected
+		rejected = rejected || reject
+		mutex.Unlock()
+		if reject {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"startAt": %d, "maxResults": 1, "total": 3, "issues": [{"key": "TST-%d"}]}`, startAt, startAt+1)
+	})
+
+	var keys []string
+	err := testClient.Issue.SearchPagesParallel("project = TST", &SearchOptions{MaxResults: 1}, &ParallelSearchOptions{RequestsPerSecond: 1000}, func(issue Issue) error {
+		keys = append(keys, issue.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if !rejected || fmt.Sprint(keys) != "[TST-1 TST-2 TST-3]" {
+		t.Errorf("Unexpected issues %v", keys)
+	}
+}
+
+func TestIssueService_SearchPagesParallel_CallbackError(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", searchPageHandler(t, func(int) int { return 100 }, 10))
+
+	stop := errors.New("stop")
+	count := 0
+	err := testClient.Issue.SearchPagesParallel("project = TST", &SearchOptions{MaxResults: 10}, nil, func(issue Issue) error {
+		count++
+		if issue.Key == "TST-15" {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("Expected the error of the callback, got %v", err)
+	}
+	if count != 15 {
+		t.Errorf("Expected 15 issues, got %d", count)
+	}
+}
+
+func TestIssueService_SearchChan(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", searchPageHandler(t, func(int) int { return 45 }, 10))
+
+	issues, errs := testClient.Issue.SearchChan("project = TST", &SearchOptions{MaxResults: 10}, nil)
+	var keys []string
+	for issue := range issues {
+		keys = append(keys, issue.Key)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(keys) != 45 {
+		t.Fatalf("Expected 45 issues, got %d", len(keys))
+	}
+	sort.Strings(keys)
+	for i := 1; i < len(keys); i++ {
+		if keys[i] == keys[i-1] {
+			t.Errorf("Duplicate issue %s", keys[i])
+		}
+	}
+}
+
+func TestIssueService_SearchChan_Cancel(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", searchPageHandler(t, func(int) int { return 1000 }, 10))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	issues, errs := testClient.Issue.SearchChanWithContext(ctx, "project = TST", &SearchOptions{MaxResults: 10}, nil)
+	<-issues
+	cancel()
+	for range issues {
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
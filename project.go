@@ -298,3 +298,37 @@ func (s *ProjectService) GetComponents(projectID string) (*[]ComponentDetail, *R
 
 	return comps, resp, nil
 }
+
+// IssueTypeStatuses holds the statuses of the workflow of one issue type of a project
+type IssueTypeStatuses struct {
+	ID       string   `json:"id" structs:"id"`
+	Name     string   `json:"name" structs:"name"`
+	Subtask  bool     `json:"subtask" structs:"subtask"`
+	Statuses []Status `json:"statuses" structs:"statuses"`
+}
+
+// GetStatusesWithContext returns the statuses of a project, grouped by issue type.
+// Each issue type lists the statuses of its workflow.
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/project-getAllStatuses
+func (s *ProjectService) GetStatusesWithContext(ctx context.Context, projectID string) ([]IssueTypeStatuses, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/api/2/project/%s/statuses", projectID)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var statuses []IssueTypeStatuses
+	resp, err := s.client.Do(req, &statuses)
+	if err != nil {
+		jerr := NewJiraError(resp, err)
+		return nil, resp, jerr
+	}
+
+	return statuses, resp, nil
+}
+
+// GetStatuses wraps GetStatusesWithContext using the background context.
+func (s *ProjectService) GetStatuses(projectID string) ([]IssueTypeStatuses, *Response, error) {
+	return s.GetStatusesWithContext(context.Background(), projectID)
+}
@@ -0,0 +1,238 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// RankOptions specifies where the issues are ranked to.
+// Exactly one of RankBeforeIssue and RankAfterIssue has to be set.
+type RankOptions struct {
+	// RankBeforeIssue is the key of the issue the issues are ranked before
+	RankBeforeIssue string
+	// RankAfterIssue is the key of the issue the issues are ranked after
+	RankAfterIssue string
+	// RankCustomFieldID is the id of the rank field to use. By default the global rank field is used.
+	RankCustomFieldID int
+}
+
+// RankResult holds the result of ranking issues, with one entry per issue
+type RankResult struct {
+	Entries []RankEntry `json:"entries"`
+}
+
+// RankEntry is the ranking result of a single issue.
+// Status is the HTTP status of the operation for this issue.
+type RankEntry struct {
+	IssueID  int      `json:"issueId,omitempty"`
+	IssueKey string   `json:"issueKey,omitempty"`
+	Status   int      `json:"status"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// Failed returns the entries of the issues that could not be ranked
+func (r *RankResult) Failed() []RankEntry {
+	var failed []RankEntry
+	for _, entry := range r.Entries {
+		if entry.Status < 200 || entry.Status > 299 {
+			failed = append(failed, entry)
+		}
+	}
+	return failed
+}
+
+// rankRequest is the payload of the rank issues call
+type rankRequest struct {
+	Issues            []string `json:"issues"`
+	RankBeforeIssue   string   `json:"rankBeforeIssue,omitempty"`
+	RankAfterIssue    string   `json:"rankAfterIssue,omitempty"`
+	RankCustomFieldID int      `json:"rankCustomFieldId,omitempty"`
+}
+
+// RankIssuesWithContext moves the issues before or after a given issue, keeping their order.
+// Jira accepts at most 50 issues per request; longer lists are ranked in chunks,
+// where every chunk is ranked after the last issue of the previous chunk.
+//
+// If some issues could not be ranked, the result holds the status of every issue and an error is returned.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/issue-rankIssues
+func (s *IssueService) RankIssuesWithContext(ctx context.Context, issueKeys []string, options *RankOptions) (*RankResult, error) {
+	if options == nil || (options.RankBeforeIssue == "") == (options.RankAfterIssue == "") {
+		return nil, fmt.Errorf("exactly one of RankBeforeIssue and RankAfterIssue has to be set")
+	}
+
+	result := &RankResult{}
+	request := rankRequest{
+		RankBeforeIssue:   options.RankBeforeIssue,
+		RankAfterIssue:    options.RankAfterIssue,
+		RankCustomFieldID: options.RankCustomFieldID,
+	}
+	for _, chunk := range chunkStrings(issueKeys, maxIssuesPerAgileRequest) {
+		request.Issues = chunk
+		entries, err := s.rankWithContext(ctx, &request)
+		if err != nil {
+			return result, err
+		}
+		result.Entries = append(result.Entries, entries...)
+
+		request.RankBeforeIssue = ""
+		request.RankAfterIssue = chunk[len(chunk)-1]
+	}
+
+	if failed := result.Failed(); len(failed) > 0 {
+		keys := make([]string, 0, len(failed))
+		for _, entry := range failed {
+			keys = append(keys, entry.IssueKey)
+		}
+		return result, fmt.Errorf("ranking failed for %d of %d issues: %s", len(failed), len(issueKeys), strings.Join(keys, ", "))
+	}
+	return result, nil
+}
+
+// RankIssues wraps RankIssuesWithContext using the background context.
+func (s *IssueService) RankIssues(issueKeys []string, options *RankOptions) (*RankResult, error) {
+	return s.RankIssuesWithContext(context.Background(), issueKeys, options)
+}
+
+// rankWithContext sends a single rank request.
+// Jira answers with 204 if all issues were ranked, and with 207 and a status per issue otherwise.
+func (s *IssueService) rankWithContext(ctx context.Context, request *rankRequest) ([]RankEntry, error) {
+	apiEndpoint := "rest/agile/1.0/issue/rank"
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", apiEndpoint, request)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		return nil, NewJiraError(resp, err)
+	}
+	defer Cleanup(resp)
+
+	if resp.StatusCode == http.StatusMultiStatus {
+		result := new(RankResult)
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return nil, err
+		}
+		return result.Entries, nil
+	}
+
+	entries := make([]RankEntry, 0, len(request.Issues))
+	for _, key := range request.Issues {
+		entries = append(entries, RankEntry{IssueKey: key, Status: resp.StatusCode})
+	}
+	return entries, nil
+}
+
+// RankOperation is a single rank call, as computed by RankOperations
+type RankOperation struct {
+	Issues          []string
+	RankBeforeIssue string
+	RankAfterIssue  string
+}
+
+// ApplyRankOrderWithContext reorders issues from their current rank order into the desired order.
+// The issues which are already in the right relative order (the longest such sequence) stay where they are,
+// all other issues are moved with as few rank calls as possible, see RankOperations.
+func (s *IssueService) ApplyRankOrderWithContext(ctx context.Context, current, desired []string, rankCustomFieldID int) (*RankResult, error) {
+	result := &RankResult{}
+	for _, operation := range RankOperations(current, desired) {
+		ranked, err := s.RankIssuesWithContext(ctx, operation.Issues, &RankOptions{
+			RankBeforeIssue:   operation.RankBeforeIssue,
+			RankAfterIssue:    operation.RankAfterIssue,
+			RankCustomFieldID: rankCustomFieldID,
+		})
+		if ranked != nil {
+			result.Entries = append(result.Entries, ranked.Entries...)
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// ApplyRankOrder wraps ApplyRankOrderWithContext using the background context.
+func (s *IssueService) ApplyRankOrder(current, desired []string, rankCustomFieldID int) (*RankResult, error) {
+	return s.ApplyRankOrderWithContext(context.Background(), current, desired, rankCustomFieldID)
+}
+
+// RankOperations computes the rank calls needed to turn the current order of issues into the desired order.
+// The longest subsequence of desired that is already in the current order is kept in place.
+// Every run of consecutive other issues is moved with one operation, after its predecessor in the desired order,
+// or before its successor if the run starts the desired order.
+// Issues of desired that are not part of current are treated as out of order.
+func RankOperations(current, desired []string) []RankOperation {
+	position := make(map[string]int, len(current))
+	for i, key := range current {
+		position[key] = i
+	}
+
+	keep := longestOrderedSubsequence(desired, position)
+
+	var operations []RankOperation
+	for i := 0; i < len(desired); {
+		if keep[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(desired) && !keep[i] {
+			i++
+		}
+		operation := RankOperation{Issues: desired[start:i]}
+		if start > 0 {
+			operation.RankAfterIssue = desired[start-1]
+		} else if i < len(desired) {
+			operation.RankBeforeIssue = desired[i]
+		} else {
+			// None of the issues is known in the current order, so there is nothing to rank against
+			continue
+		}
+		operations = append(operations, operation)
+	}
+	return operations
+}
+
+// longestOrderedSubsequence marks the issues of desired which form the longest subsequence
+// with increasing positions in the current order.
+func longestOrderedSubsequence(desired []string, position map[string]int) []bool {
+	// tails[l] is the index in desired of the smallest tail of an increasing subsequence of length l+1
+	var tails []int
+	previous := make([]int, len(desired))
+	for i, key := range desired {
+		previous[i] = -1
+		pos, ok := position[key]
+		if !ok {
+			continue
+		}
+		low, high := 0, len(tails)
+		for low < high {
+			mid := (low + high) / 2
+			if position[desired[tails[mid]]] < pos {
+				low = mid + 1
+			} else {
+				high = mid
+			}
+		}
+		if low > 0 {
+			previous[i] = tails[low-1]
+		}
+		if low == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[low] = i
+		}
+	}
+
+	keep := make([]bool, len(desired))
+	if len(tails) > 0 {
+		for i := tails[len(tails)-1]; i >= 0; i = previous[i] {
+			keep[i] = true
+		}
+	}
+	return keep
+}
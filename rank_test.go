@@ -0,0 +1,147 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestIssueService_RankIssues(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var requests []rankRequest
+	testMux.HandleFunc("/rest/agile/1.0/issue/rank", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		request := rankRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, request)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	keys := make([]string, 60)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("TST-%d", i+1)
+	}
+
+	result, err := testClient.Issue.RankIssues(keys, &RankOptions{RankBeforeIssue: "TST-100", RankCustomFieldID: 10019})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if len(result.Entries) != 60 {
+		t.Errorf("Expected 60 entries, got %d", len(result.Entries))
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	if len(requests[0].Issues) != 50 || requests[0].RankBeforeIssue != "TST-100" || requests[0].RankCustomFieldID != 10019 {
+		t.Errorf("Unexpected first request %+v", requests[0])
+	}
+	if len(requests[1].Issues) != 10 || requests[1].RankAfterIssue != "TST-50" || requests[1].RankBeforeIssue != "" {
+		t.Errorf("Expected the second chunk to be ranked after TST-50, got %+v", requests[1])
+	}
+}
+
+func TestIssueService_RankIssues_PartialFailure(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/issue/rank", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		w.WriteHeader(http.StatusMultiStatus)
+		fmt.Fprint(w, `{"entries":[{"issueId":10000,"issueKey":"TST-1","status":200},{"issueId":10001,"issueKey":"TST-2","status":403,"errors":["No permission"]}]}`)
+	})
+
+	result, err := testClient.Issue.RankIssues([]string{"TST-1", "TST-2"}, &RankOptions{RankAfterIssue: "TST-3"})
+	if err == nil {
+		t.Error("Expected an error for the failed issue")
+	}
+	failed := result.Failed()
+	if len(failed) != 1 || failed[0].IssueKey != "TST-2" || failed[0].Errors[0] != "No permission" {
+		t.Errorf("Expected TST-2 to fail, got %+v", failed)
+	}
+}
+
+func TestIssueService_RankIssues_InvalidOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	if _, err := testClient.Issue.RankIssues([]string{"TST-1"}, &RankOptions{RankBeforeIssue: "TST-2", RankAfterIssue: "TST-3"}); err == nil {
+		t.Error("Expected an error if both RankBeforeIssue and RankAfterIssue are set")
+	}
+	if _, err := testClient.Issue.RankIssues([]string{"TST-1"}, nil); err == nil {
+		t.Error("Expected an error without rank options")
+	}
+}
+
+func TestRankOperations(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  []string
+		desired  []string
+		expected []RankOperation
+	}{
+		{
+			name:    "already ordered",
+			current: []string{"A", "B", "C"},
+			desired: []string{"A", "B", "C"},
+		},
+		{
+			name:     "move last to front",
+			current:  []string{"A", "B", "C", "D"},
+			desired:  []string{"D", "A", "B", "C"},
+			expected: []RankOperation{{Issues: []string{"D"}, RankBeforeIssue: "A"}},
+		},
+		{
+			name:     "move a run",
+			current:  []string{"A", "B", "C", "D", "E"},
+			desired:  []string{"A", "D", "E", "B", "C"},
+			expected: []RankOperation{{Issues: []string{"D", "E"}, RankAfterIssue: "A"}},
+		},
+		{
+			name:    "reversed",
+			current: []string{"A", "B", "C"},
+			desired: []string{"C", "B", "A"},
+			expected: []RankOperation{
+				{Issues: []string{"C", "B"}, RankBeforeIssue: "A"},
+			},
+		},
+		{
+			name:     "unknown issue",
+			current:  []string{"A", "B"},
+			desired:  []string{"A", "X", "B"},
+			expected: []RankOperation{{Issues: []string{"X"}, RankAfterIssue: "A"}},
+		},
+	}
+
+	for _, test := range tests {
+		operations := RankOperations(test.current, test.desired)
+		if !reflect.DeepEqual(operations, test.expected) {
+			t.Errorf("%s: expected %+v, got %+v", test.name, test.expected, operations)
+		}
+	}
+}
+
+func TestIssueService_ApplyRankOrder(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	testMux.HandleFunc("/rest/agile/1.0/issue/rank", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		calls++
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	_, err := testClient.Issue.ApplyRankOrder([]string{"A", "B", "C", "D"}, []string{"B", "A", "C", "D"}, 0)
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected one rank call, got %d", calls)
+	}
+}
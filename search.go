@@ -0,0 +1,108 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// defaultMaxSearchURLLength is the length of the request URL above which SearchWithContext switches to a POST request,
+// unless SearchOptions.MaxURLLength is set. Many proxies and servers reject URLs longer than a few thousand characters.
+const defaultMaxSearchURLLength = 4000
+
+// MaxKeysPerSearch is the maximum number of issue keys used in one JQL query of SearchKeysWithContext
+const MaxKeysPerSearch = 500
+
+// searchRequest is the body of a search with a POST request
+type searchRequest struct {
+	JQL           string   `json:"jql,omitempty"`
+	StartAt       int      `json:"startAt,omitempty"`
+	MaxResults    int      `json:"maxResults,omitempty"`
+	Fields        []string `json:"fields,omitempty"`
+	Expand        []string `json:"expand,omitempty"`
+	Properties    []string `json:"properties,omitempty"`
+	FieldsByKeys  bool     `json:"fieldsByKeys,omitempty"`
+	ValidateQuery string   `json:"validateQuery,omitempty"`
+}
+
+// expand returns the sections to expand, from Expand and Expands
+func (o *SearchOptions) expand() []string {
+	var expand []string
+	for _, e := range strings.Split(o.Expand, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			expand = append(expand, e)
+		}
+	}
+	return append(expand, o.Expands...)
+}
+
+// SearchPostWithContext will search for tickets according to the jql, sending the query in the body of a POST request.
+// Use it for long queries or long lists of fields, which do not fit into the URL of SearchWithContext.
+//
+// Jira API docs: https://developer.atlassian.com/cloud/jira/platform/rest/v2/api-group-issue-search/#api-rest-api-2-search-post
+func (s *IssueService) SearchPostWithContext(ctx context.Context, jql string, options *SearchOptions) (*SearchResult, *Response, error) {
+	body := searchRequest{JQL: jql}
+	if options != nil {
+		body.StartAt = options.StartAt
+		body.MaxResults = options.MaxResults
+		body.Fields = options.Fields
+		body.Expand = options.expand()
+		body.Properties = options.Properties
+		body.FieldsByKeys = options.FieldsByKeys
+		body.ValidateQuery = options.ValidateQuery
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", "rest/api/2/search", body)
+	if err != nil {
+		return &SearchResult{}, nil, err
+	}
+
+	v := new(SearchResult)
+	resp, err := s.client.Do(req, v)
+	if err != nil {
+		err = NewJiraError(resp, err)
+	}
+	return v, resp, err
+}
+
+// SearchPost wraps SearchPostWithContext using the background context.
+func (s *IssueService) SearchPost(jql string, options *SearchOptions) (*SearchResult, *Response, error) {
+	return s.SearchPostWithContext(context.Background(), jql, options)
+}
+
+// KeyQueries splits a list of issue keys (or ids) into JQL queries "key in (...)" with at most batchSize keys each.
+// A batchSize of 0 uses MaxKeysPerSearch.
+func KeyQueries(keys []string, batchSize int) []string {
+	if batchSize <= 0 {
+		batchSize = MaxKeysPerSearch
+	}
+	var queries []string
+	for _, chunk := range chunkStrings(keys, batchSize) {
+		queries = append(queries, fmt.Sprintf("key in (%s)", strings.Join(chunk, ",")))
+	}
+	return queries
+}
+
+// SearchKeysWithContext will get the issues with the given keys (or ids), calling f for each issue.
+// The keys are searched in batches of MaxKeysPerSearch keys; the order of the issues is the order of the batches.
+// Keys which do not exist or are not visible make Jira reject the query, unless options.ValidateQuery is "warn" or "none".
+//
+// Jira API docs: https://developer.atlassian.com/cloud/jira/platform/rest/v2/api-group-issue-search/#api-rest-api-2-search-post
+func (s *IssueService) SearchKeysWithContext(ctx context.Context, keys []string, options *SearchOptions, f func(Issue) error) error {
+	for _, jql := range KeyQueries(keys, MaxKeysPerSearch) {
+		batchOptions := SearchOptions{MaxResults: 100}
+		if options != nil {
+			batchOptions = *options
+		}
+		batchOptions.StartAt = 0
+		if err := s.SearchPagesWithContext(ctx, jql, &batchOptions, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchKeys wraps SearchKeysWithContext using the background context.
+func (s *IssueService) SearchKeys(keys []string, options *SearchOptions, f func(Issue) error) error {
+	return s.SearchKeysWithContext(context.Background(), keys, options, f)
+}
@@ -0,0 +1,150 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIssueService_SearchPost(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		expected := `map[expand:[renderedFields names] fields:[summary status] fieldsByKeys:true jql:project = TST maxResults:10 properties:[myapp.rank] startAt:5 validateQuery:warn]`
+		if got := fmt.Sprint(body); got != expected {
+			t.Errorf("Expected body %s, got %s", expected, got)
+		}
+		_, _ = fmt.Fprint(w, `{"startAt": 5, "maxResults": 10, "total": 6, "issues": [{"key": "TST-1"}]}`)
+	})
+
+	result, resp, err := testClient.Issue.SearchPost("project = TST", &SearchOptions{
+		StartAt:       5,
+		MaxResults:    10,
+		Expand:        "renderedFields",
+		Expands:       []string{"names"},
+		Fields:        []string{"summary", "status"},
+		Properties:    []string{"myapp.rank"},
+		FieldsByKeys:  true,
+		ValidateQuery: "warn",
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Key != "TST-1" || resp.Total != 6 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestIssueService_Search_Options(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/api/2/search?expand=changelog%2Cnames&fieldsByKeys=true&jql=project+%3D+TST&properties=a%2Cb")
+		_, _ = fmt.Fprint(w, `{"startAt": 0, "maxResults": 50, "total": 0, "issues": []}`)
+	})
+
+	_, _, err := testClient.Issue.Search("project = TST", &SearchOptions{Expand: "changelog", Expands: []string{"names"}, Properties: []string{"a", "b"}, FieldsByKeys: true})
+	if err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestIssueService_Search_SwitchesToPost(t *testing.T) {
+	setup()
+	defer teardown()
+
+	keys := make([]string, 600)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("TST-%d", i+1)
+	}
+	jql := KeyQueries(keys, len(keys))[0]
+
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var body searchRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.JQL != jql {
+			t.Errorf("Unexpected jql %s", body.JQL)
+		}
+		_, _ = fmt.Fprint(w, `{"startAt": 0, "maxResults": 50, "total": 0, "issues": []}`)
+	})
+
+	if _, _, err := testClient.Issue.Search(jql, nil); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestIssueService_Search_MaxURLLength(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		_, _ = fmt.Fprint(w, `{"startAt": 0, "maxResults": 50, "total": 0, "issues": []}`)
+	})
+
+	if _, _, err := testClient.Issue.Search("project = TST", &SearchOptions{MaxURLLength: 20}); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestKeyQueries(t *testing.T) {
+	queries := KeyQueries([]string{"TST-1", "TST-2", "TST-3"}, 2)
+	expected := []string{"key in (TST-1,TST-2)", "key in (TST-3)"}
+	if fmt.Sprint(queries) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, queries)
+	}
+	if queries := KeyQueries(nil, 0); len(queries) != 0 {
+		t.Errorf("Expected no queries, got %v", queries)
+	}
+}
+
+func TestIssueService_SearchKeys(t *testing.T) {
+	setup()
+	defer teardown()
+
+	keys := make([]string, MaxKeysPerSearch+1)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("TST-%d", i+1)
+	}
+
+	var queries []string
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		var body searchRequest
+		if r.Method == "POST" {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			body.JQL = r.URL.Query().Get("jql")
+		}
+		queries = append(queries, body.JQL)
+		key := strings.TrimSuffix(body.JQL[strings.LastIndex(body.JQL, ",")+1:], ")")
+		_, _ = fmt.Fprintf(w, `{"startAt": 0, "maxResults": 100, "total": 1, "issues": [{"key": %q}]}`, strings.TrimPrefix(key, "key in ("))
+	})
+
+	var found []string
+	err := testClient.Issue.SearchKeys(keys, &SearchOptions{MaxResults: 100, Fields: []string{"key"}}, func(issue Issue) error {
+		found = append(found, issue.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(queries) != 2 || !strings.HasPrefix(queries[0], "key in (TST-1,TST-2,") || queries[1] != "key in (TST-501)" {
+		t.Errorf("Unexpected queries %.60v", queries)
+	}
+	if fmt.Sprint(found) != "[TST-500 TST-501]" {
+		t.Errorf("Unexpected issues %v", found)
+	}
+}
@@ -3,6 +3,7 @@ package jira
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
@@ -20,7 +21,52 @@ type IssuesWrapper struct {
 
 // IssuesInSprintResult represents a wrapper struct for search result
 type IssuesInSprintResult struct {
-	Issues []Issue `json:"issues"`
+	StartAt    int     `json:"startAt"`
+	MaxResults int     `json:"maxResults"`
+	Total      int     `json:"total"`
+	Issues     []Issue `json:"issues"`
+}
+
+// These constants are the states a sprint can be in
+const (
+	SprintStateFuture = "future"
+	SprintStateActive = "active"
+	SprintStateClosed = "closed"
+)
+
+// maxIssuesPerAgileRequest is the maximum number of issues that can be moved or ranked in one agile request
+const maxIssuesPerAgileRequest = 50
+
+// AgileIssuesOptions specifies the optional parameters for the agile methods returning issues,
+// like SprintService.GetIssuesForSprintWithOptions
+type AgileIssuesOptions struct {
+	// StartAt: The starting index of the returned issues. Base index: 0.
+	StartAt int `url:"startAt,omitempty"`
+	// MaxResults: The maximum number of issues to return per page. Default: 50.
+	MaxResults int `url:"maxResults,omitempty"`
+	// JQL filters the returned issues further
+	JQL string `url:"jql,omitempty"`
+	// ValidateQuery specifies whether to validate the JQL query or not. Default: true.
+	ValidateQuery *bool `url:"validateQuery,omitempty"`
+	// Fields is the comma separated list of fields to return for each issue. By default, all navigable fields are returned.
+	Fields string `url:"fields,omitempty"`
+	// Expand: Expand specific sections in the returned issues, e.g. changelog
+	Expand string `url:"expand,omitempty"`
+}
+
+// CompleteSprintOptions specifies where the incomplete issues of a sprint are moved to when it is completed.
+// If neither MoveToSprintID nor MoveToNextSprint is set, incomplete issues are moved to the backlog.
+type CompleteSprintOptions struct {
+	// MoveToSprintID is the id of the sprint to move the incomplete issues to
+	MoveToSprintID int
+	// MoveToNextSprint moves the incomplete issues to the first future sprint of the board the sprint originates from.
+	// If the board has no future sprint, the issues are moved to the backlog.
+	MoveToNextSprint bool
+}
+
+// sprintSwap is the payload of the swap sprint call
+type sprintSwap struct {
+	SprintToSwapWith int `json:"sprintToSwapWith"`
 }
 
 // MoveIssuesToSprintWithContext moves issues to a sprint, for a given sprint Id.
@@ -53,6 +99,34 @@ func (s *SprintService) MoveIssuesToSprint(sprintID int, issueIDs []string) (*Re
 	return s.MoveIssuesToSprintWithContext(context.Background(), sprintID, issueIDs)
 }
 
+// MoveIssuesToBacklogWithContext moves issues to the backlog, removing them from all future and active sprints.
+// The maximum number of issues that can be moved in one operation is 50.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/backlog-moveIssuesToBacklog
+// Caller must close resp.Body
+func (s *SprintService) MoveIssuesToBacklogWithContext(ctx context.Context, issueIDs []string) (*Response, error) {
+	apiEndpoint := "rest/agile/1.0/backlog/issue"
+
+	payload := IssuesWrapper{Issues: issueIDs}
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", apiEndpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		err = NewJiraError(resp, err)
+	}
+	return resp, err
+}
+
+// MoveIssuesToBacklog wraps MoveIssuesToBacklogWithContext using the background context.
+// Caller must close resp.Body
+func (s *SprintService) MoveIssuesToBacklog(issueIDs []string) (*Response, error) {
+	return s.MoveIssuesToBacklogWithContext(context.Background(), issueIDs)
+}
+
 // GetIssuesForSprintWithContext returns all issues in a sprint, for a given sprint Id.
 // This only includes issues that the user has permission to view.
 // By default, the returned issues are ordered by rank.
@@ -81,12 +155,309 @@ func (s *SprintService) GetIssuesForSprint(sprintID int) ([]Issue, *Response, er
 	return s.GetIssuesForSprintWithContext(context.Background(), sprintID)
 }
 
+// GetIssuesForSprintWithOptionsWithContext returns one page of the issues in a sprint, for a given sprint Id and options.
+//
+// Jira API Docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-getIssuesForSprint
+func (s *SprintService) GetIssuesForSprintWithOptionsWithContext(ctx context.Context, sprintID int, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.client.Board.getIssuesWithContext(ctx, fmt.Sprintf("rest/agile/1.0/sprint/%d/issue", sprintID), options)
+}
+
+// GetIssuesForSprintWithOptions wraps GetIssuesForSprintWithOptionsWithContext using the background context.
+func (s *SprintService) GetIssuesForSprintWithOptions(sprintID int, options *AgileIssuesOptions) (*IssuesInSprintResult, *Response, error) {
+	return s.GetIssuesForSprintWithOptionsWithContext(context.Background(), sprintID, options)
+}
+
+// GetIssuesForSprintPagesWithContext calls f for every issue in a sprint, reading all pages.
+func (s *SprintService) GetIssuesForSprintPagesWithContext(ctx context.Context, sprintID int, options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.client.Board.getIssuesPagesWithContext(ctx, fmt.Sprintf("rest/agile/1.0/sprint/%d/issue", sprintID), options, f)
+}
+
+// GetIssuesForSprintPages wraps GetIssuesForSprintPagesWithContext using the background context.
+func (s *SprintService) GetIssuesForSprintPages(sprintID int, options *AgileIssuesOptions, f func(Issue) error) error {
+	return s.GetIssuesForSprintPagesWithContext(context.Background(), sprintID, options, f)
+}
+
+// GetWithContext returns the sprint for a given sprint Id.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-getSprint
+func (s *SprintService) GetWithContext(ctx context.Context, sprintID int) (*Sprint, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/sprint/%d", sprintID)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sprint := new(Sprint)
+	resp, err := s.client.Do(req, sprint)
+	if err != nil {
+		jerr := NewJiraError(resp, err)
+		return nil, resp, jerr
+	}
+
+	return sprint, resp, nil
+}
+
+// Get wraps GetWithContext using the background context.
+func (s *SprintService) Get(sprintID int) (*Sprint, *Response, error) {
+	return s.GetWithContext(context.Background(), sprintID)
+}
+
+// SprintOptions are the properties of a sprint which can be set when creating or updating it.
+// Fields which are not set are left out of the request.
+type SprintOptions struct {
+	Name          string `json:"name,omitempty"`
+	StartDate     *Time  `json:"startDate,omitempty"`
+	EndDate       *Time  `json:"endDate,omitempty"`
+	State         string `json:"state,omitempty"`
+	OriginBoardID int    `json:"originBoardId,omitempty"`
+	// Goal is a pointer, so that a partial update can remove the goal with an empty string
+	Goal *string `json:"goal,omitempty"`
+}
+
+// CreateWithContext creates a future sprint. Sprint name and origin board id are required.
+// Start date, end date and goal are optional.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-createSprint
+func (s *SprintService) CreateWithContext(ctx context.Context, options *SprintOptions) (*Sprint, *Response, error) {
+	apiEndpoint := "rest/agile/1.0/sprint"
+	req, err := s.client.NewRequestWithContext(ctx, "POST", apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responseSprint := new(Sprint)
+	resp, err := s.client.Do(req, responseSprint)
+	if err != nil {
+		jerr := NewJiraError(resp, err)
+		return nil, resp, jerr
+	}
+
+	return responseSprint, resp, nil
+}
+
+// Create wraps CreateWithContext using the background context.
+func (s *SprintService) Create(options *SprintOptions) (*Sprint, *Response, error) {
+	return s.CreateWithContext(context.Background(), options)
+}
+
+// UpdateWithContext performs a full update of a sprint.
+// Jira clears the fields missing from a full update, e.g. the goal if options.Goal is not set.
+// Use PartialUpdateWithContext to change single fields.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-updateSprint
+func (s *SprintService) UpdateWithContext(ctx context.Context, sprintID int, options *SprintOptions) (*Sprint, *Response, error) {
+	return s.updateWithContext(ctx, "PUT", sprintID, options)
+}
+
+// Update wraps UpdateWithContext using the background context.
+func (s *SprintService) Update(sprintID int, options *SprintOptions) (*Sprint, *Response, error) {
+	return s.UpdateWithContext(context.Background(), sprintID, options)
+}
+
+// PartialUpdateWithContext updates only the fields of a sprint which are set in options.
+// This is also used to change the state of a sprint, e.g. to start or to complete it.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-partiallyUpdateSprint
+func (s *SprintService) PartialUpdateWithContext(ctx context.Context, sprintID int, options *SprintOptions) (*Sprint, *Response, error) {
+	return s.updateWithContext(ctx, "POST", sprintID, options)
+}
+
+// PartialUpdate wraps PartialUpdateWithContext using the background context.
+func (s *SprintService) PartialUpdate(sprintID int, options *SprintOptions) (*Sprint, *Response, error) {
+	return s.PartialUpdateWithContext(context.Background(), sprintID, options)
+}
+
+func (s *SprintService) updateWithContext(ctx context.Context, method string, sprintID int, options *SprintOptions) (*Sprint, *Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/sprint/%d", sprintID)
+	req, err := s.client.NewRequestWithContext(ctx, method, apiEndpoint, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responseSprint := new(Sprint)
+	resp, err := s.client.Do(req, responseSprint)
+	if err != nil {
+		jerr := NewJiraError(resp, err)
+		return nil, resp, jerr
+	}
+
+	return responseSprint, resp, nil
+}
+
+// DeleteWithContext deletes a sprint. Once a sprint is deleted, all open issues in the sprint will be moved to the backlog.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-deleteSprint
+// Caller must close resp.Body
+func (s *SprintService) DeleteWithContext(ctx context.Context, sprintID int) (*Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/sprint/%d", sprintID)
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", apiEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		err = NewJiraError(resp, err)
+	}
+	return resp, err
+}
+
+// Delete wraps DeleteWithContext using the background context.
+// Caller must close resp.Body
+func (s *SprintService) Delete(sprintID int) (*Response, error) {
+	return s.DeleteWithContext(context.Background(), sprintID)
+}
+
+// StartWithContext starts a future sprint with the given start and end date.
+// If goal is not empty, it replaces the goal of the sprint.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-partiallyUpdateSprint
+func (s *SprintService) StartWithContext(ctx context.Context, sprintID int, startDate, endDate time.Time, goal string) (*Sprint, *Response, error) {
+	start, end := Time(startDate), Time(endDate)
+	options := &SprintOptions{
+		State:     SprintStateActive,
+		StartDate: &start,
+		EndDate:   &end,
+	}
+	if goal != "" {
+		options.Goal = &goal
+	}
+	return s.PartialUpdateWithContext(ctx, sprintID, options)
+}
+
+// Start wraps StartWithContext using the background context.
+func (s *SprintService) Start(sprintID int, startDate, endDate time.Time, goal string) (*Sprint, *Response, error) {
+	return s.StartWithContext(context.Background(), sprintID, startDate, endDate, goal)
+}
+
+// SetGoalWithContext sets the goal of a sprint. An empty goal removes the goal.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-partiallyUpdateSprint
+func (s *SprintService) SetGoalWithContext(ctx context.Context, sprintID int, goal string) (*Sprint, *Response, error) {
+	return s.PartialUpdateWithContext(ctx, sprintID, &SprintOptions{Goal: &goal})
+}
+
+// SetGoal wraps SetGoalWithContext using the background context.
+func (s *SprintService) SetGoal(sprintID int, goal string) (*Sprint, *Response, error) {
+	return s.SetGoalWithContext(context.Background(), sprintID, goal)
+}
+
+// CompleteWithContext completes an active sprint.
+// Issues of the sprint which are not in a status of the "done" category are moved
+// to the sprint or backlog specified by options, in batches of 50 issues.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-partiallyUpdateSprint
+func (s *SprintService) CompleteWithContext(ctx context.Context, sprintID int, options *CompleteSprintOptions) (*Sprint, *Response, error) {
+	if options == nil {
+		options = &CompleteSprintOptions{}
+	}
+
+	var incomplete []string
+	err := s.GetIssuesForSprintPagesWithContext(ctx, sprintID, &AgileIssuesOptions{MaxResults: 50, Fields: "status"}, func(issue Issue) error {
+		if issue.Fields == nil || issue.Fields.Status == nil || issue.Fields.Status.StatusCategory.Key != "done" {
+			incomplete = append(incomplete, issue.Key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	targetSprintID := options.MoveToSprintID
+	if targetSprintID == 0 && options.MoveToNextSprint {
+		targetSprintID, err = s.nextSprintID(ctx, sprintID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	sprint, resp, err := s.PartialUpdateWithContext(ctx, sprintID, &SprintOptions{State: SprintStateClosed})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	for _, keys := range chunkStrings(incomplete, maxIssuesPerAgileRequest) {
+		if targetSprintID != 0 {
+			resp, err = s.MoveIssuesToSprintWithContext(ctx, targetSprintID, keys)
+		} else {
+			resp, err = s.MoveIssuesToBacklogWithContext(ctx, keys)
+		}
+		if err != nil {
+			return sprint, resp, err
+		}
+		Cleanup(resp)
+	}
+
+	return sprint, resp, nil
+}
+
+// Complete wraps CompleteWithContext using the background context.
+func (s *SprintService) Complete(sprintID int, options *CompleteSprintOptions) (*Sprint, *Response, error) {
+	return s.CompleteWithContext(context.Background(), sprintID, options)
+}
+
+// nextSprintID returns the id of the first future sprint on the origin board of the given sprint,
+// or 0 if there is none.
+func (s *SprintService) nextSprintID(ctx context.Context, sprintID int) (int, error) {
+	sprint, _, err := s.GetWithContext(ctx, sprintID)
+	if err != nil {
+		return 0, err
+	}
+
+	sprints, _, err := s.client.Board.GetAllSprintsWithOptionsWithContext(ctx, sprint.OriginBoardID, &GetAllSprintsOptions{State: SprintStateFuture})
+	if err != nil {
+		return 0, err
+	}
+	for _, future := range sprints.Values {
+		if future.ID != sprintID {
+			return future.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+// SwapWithContext swaps the position of a sprint with the position of another sprint.
+//
+// Jira API docs: https://docs.atlassian.com/jira-software/REST/cloud/#agile/1.0/sprint-swapSprint
+// Caller must close resp.Body
+func (s *SprintService) SwapWithContext(ctx context.Context, sprintID, sprintToSwapWith int) (*Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/agile/1.0/sprint/%d/swap", sprintID)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", apiEndpoint, sprintSwap{SprintToSwapWith: sprintToSwapWith})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		err = NewJiraError(resp, err)
+	}
+	return resp, err
+}
+
+// Swap wraps SwapWithContext using the background context.
+// Caller must close resp.Body
+func (s *SprintService) Swap(sprintID, sprintToSwapWith int) (*Response, error) {
+	return s.SwapWithContext(context.Background(), sprintID, sprintToSwapWith)
+}
+
+// chunkStrings splits values into consecutive chunks of at most size elements.
+func chunkStrings(values []string, size int) [][]string {
+	var chunks [][]string
+	for size < len(values) {
+		values, chunks = values[size:], append(chunks, values[:size])
+	}
+	if len(values) > 0 {
+		chunks = append(chunks, values)
+	}
+	return chunks
+}
+
 // GetIssueWithContext returns a full representation of the issue for the given issue key.
 // Jira will attempt to identify the issue by the issueIdOrKey path parameter.
 // This can be an issue id, or an issue key.
 // If the issue cannot be found via an exact match, Jira will also look for the issue in a case-insensitive way, or by looking to see if the issue was moved.
 //
-// The given options will be appended to the query string
+// # The given options will be appended to the query string
 //
 // Jira API docs: https://docs.atlassian.com/jira-software/REST/7.3.1/#agile/1.0/issue-getIssue
 //
@@ -6,7 +6,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestSprintService_MoveIssuesToSprint(t *testing.T) {
@@ -114,3 +116,292 @@ func TestSprintService_GetIssue(t *testing.T) {
 		t.Errorf("Error given: %s", err)
 	}
 }
+
+func TestSprintService_Get(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/sprint/37"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, testAPIEndpoint)
+		fmt.Fprint(w, `{"id":37,"state":"active","name":"sprint 1","startDate":"2015-04-11T15:22:00.000+10:00","endDate":"2015-04-20T01:22:00.000+10:00","completeDate":"2015-04-20T11:43:00.000+1000","originBoardId":5,"goal":"sprint 1 goal"}`)
+	})
+
+	sprint, _, err := testClient.Sprint.Get(37)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if sprint.ID != 37 || sprint.Goal != "sprint 1 goal" || sprint.OriginBoardID != 5 {
+		t.Errorf("Unexpected sprint %+v", sprint)
+	}
+	if sprint.StartDate == nil || time.Time(*sprint.StartDate).Day() != 11 {
+		t.Errorf("Expected start date to be parsed, got %v", sprint.StartDate)
+	}
+	// Jira Server formats some timestamps without a colon in the offset
+	if sprint.CompleteDate == nil || time.Time(*sprint.CompleteDate).UTC().Hour() != 1 {
+		t.Errorf("Expected complete date to be parsed, got %v", sprint.CompleteDate)
+	}
+}
+
+func TestSprintService_Create(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/sprint"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, testAPIEndpoint)
+
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Got error: %v", err)
+		}
+		if _, ok := payload["id"]; ok {
+			t.Error("Expected no id in the payload of a new sprint")
+		}
+		if payload["name"] != "sprint 1" || payload["originBoardId"] != float64(5) {
+			t.Errorf("Unexpected payload %v", payload)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":37,"state":"future","name":"sprint 1","originBoardId":5}`)
+	})
+
+	sprint, _, err := testClient.Sprint.Create(&SprintOptions{Name: "sprint 1", OriginBoardID: 5})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if sprint.ID != 37 || sprint.State != SprintStateFuture {
+		t.Errorf("Unexpected sprint %+v", sprint)
+	}
+}
+
+func TestSprintService_Update(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/sprint/37"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "PUT")
+		testRequestURL(t, r, testAPIEndpoint)
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.TrimSpace(string(body)) != `{"name":"renamed"}` {
+			t.Errorf("Expected only the name in the payload, got %s", body)
+		}
+		fmt.Fprint(w, `{"id":37,"state":"future","name":"renamed"}`)
+	})
+
+	sprint, _, err := testClient.Sprint.Update(37, &SprintOptions{Name: "renamed"})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if sprint.Name != "renamed" {
+		t.Errorf("Expected renamed sprint, got %+v", sprint)
+	}
+}
+
+func TestSprintService_Delete(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/sprint/37"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, testAPIEndpoint)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := testClient.Sprint.Delete(37)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, resp.StatusCode)
+	}
+}
+
+func TestSprintService_Start(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/sprint/37"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, testAPIEndpoint)
+
+		var payload Sprint
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Got error: %v", err)
+		}
+		if payload.State != SprintStateActive || payload.Goal != "ship it" {
+			t.Errorf("Unexpected payload %+v", payload)
+		}
+		if payload.StartDate == nil || payload.EndDate == nil {
+			t.Error("Expected start and end date in the payload")
+		}
+		fmt.Fprint(w, `{"id":37,"state":"active","goal":"ship it"}`)
+	})
+
+	start := time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+	sprint, _, err := testClient.Sprint.Start(37, start, start.AddDate(0, 0, 14), "ship it")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if sprint.State != SprintStateActive {
+		t.Errorf("Expected active sprint, got %s", sprint.State)
+	}
+}
+
+func TestSprintService_SetGoal(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/sprint/37"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, testAPIEndpoint)
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.TrimSpace(string(body)) != `{"goal":""}` {
+			t.Errorf("Expected the empty goal in the payload, got %s", body)
+		}
+		fmt.Fprint(w, `{"id":37,"state":"future"}`)
+	})
+
+	if _, _, err := testClient.Sprint.SetGoal(37, ""); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+}
+
+func TestSprintService_Complete(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var closed bool
+	testMux.HandleFunc("/rest/agile/1.0/sprint/37/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":2,"issues":[
+			{"key":"TST-1","fields":{"status":{"id":"1","statusCategory":{"key":"done"}}}},
+			{"key":"TST-2","fields":{"status":{"id":"2","statusCategory":{"key":"indeterminate"}}}}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/37", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"id":37,"state":"active","originBoardId":5}`)
+		case "POST":
+			var payload Sprint
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Errorf("Got error: %v", err)
+			}
+			if payload.State != SprintStateClosed {
+				t.Errorf("Expected sprint to be closed, got state %q", payload.State)
+			}
+			closed = true
+			fmt.Fprint(w, `{"id":37,"state":"closed","originBoardId":5}`)
+		default:
+			t.Errorf("Unexpected method %s", r.Method)
+		}
+	})
+	testMux.HandleFunc("/rest/agile/1.0/board/5/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestParams(t, r, map[string]string{"state": "future"})
+		fmt.Fprint(w, `{"isLast":true,"values":[{"id":38,"state":"future"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/38/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		if !closed {
+			t.Error("Expected the sprint to be closed before moving its issues")
+		}
+		var payload IssuesWrapper
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Got error: %v", err)
+		}
+		if !reflect.DeepEqual(payload.Issues, []string{"TST-2"}) {
+			t.Errorf("Expected only the incomplete issue to be moved, got %v", payload.Issues)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	sprint, _, err := testClient.Sprint.Complete(37, &CompleteSprintOptions{MoveToNextSprint: true})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if sprint.State != SprintStateClosed {
+		t.Errorf("Expected closed sprint, got %s", sprint.State)
+	}
+}
+
+func TestSprintService_Complete_ToBacklog(t *testing.T) {
+	setup()
+	defer teardown()
+
+	testMux.HandleFunc("/rest/agile/1.0/sprint/37/issue", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":1,"issues":[{"key":"TST-2","fields":{"status":{"id":"2","statusCategory":{"key":"new"}}}}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/37", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		fmt.Fprint(w, `{"id":37,"state":"closed"}`)
+	})
+	var moved []string
+	testMux.HandleFunc("/rest/agile/1.0/backlog/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		var payload IssuesWrapper
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Got error: %v", err)
+		}
+		moved = append(moved, payload.Issues...)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, _, err := testClient.Sprint.Complete(37, nil); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if !reflect.DeepEqual(moved, []string{"TST-2"}) {
+		t.Errorf("Expected TST-2 to be moved to the backlog, got %v", moved)
+	}
+}
+
+func TestSprintService_Swap(t *testing.T) {
+	setup()
+	defer teardown()
+	testAPIEndpoint := "/rest/agile/1.0/sprint/37/swap"
+
+	testMux.HandleFunc(testAPIEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		testRequestURL(t, r, testAPIEndpoint)
+		var payload sprintSwap
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Got error: %v", err)
+		}
+		if payload.SprintToSwapWith != 38 {
+			t.Errorf("Expected swap with sprint 38, got %d", payload.SprintToSwapWith)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, err := testClient.Sprint.Swap(37, 38); err != nil {
+		t.Errorf("Error given: %s", err)
+	}
+}
+
+func TestChunkStrings(t *testing.T) {
+	chunks := chunkStrings([]string{"a", "b", "c", "d", "e"}, 2)
+	expected := [][]string{{"a", "b"}, {"c", "d"}, {"e"}}
+	if !reflect.DeepEqual(chunks, expected) {
+		t.Errorf("Expected %v, got %v", expected, chunks)
+	}
+	if chunks := chunkStrings(nil, 2); len(chunks) != 0 {
+		t.Errorf("Expected no chunks, got %v", chunks)
+	}
+}
+
+func TestSprint_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(Sprint{ID: 37, Name: "sprint 1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"id":37,"name":"sprint 1","completeDate":null,"endDate":null,"startDate":null,"originBoardId":0,"self":"","state":"","goal":""}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
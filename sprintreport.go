@@ -0,0 +1,473 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultStoryPointsField is the name of the field holding the story points on most Jira instances
+const defaultStoryPointsField = "Story Points"
+
+// SprintReportOptions specifies the optional parameters for the sprint report and velocity calculations
+type SprintReportOptions struct {
+	// StoryPointsField is the name (or id) of the field holding the estimation of an issue.
+	// Default: "Story Points".
+	StoryPointsField string
+}
+
+// SprintReport is the computed report of a single sprint.
+// Points are taken from the story points field, as it was at the relevant point in time.
+type SprintReport struct {
+	Sprint      Sprint `json:"sprint"`
+	PointsField string `json:"pointsField"`
+
+	// Committed are the issues which were in the sprint when it was started
+	Committed []SprintReportIssue `json:"committed"`
+	// Completed are the issues which were in the sprint and done at the end of the sprint (or now, if the sprint is active)
+	Completed []SprintReportIssue `json:"completed"`
+	// Incomplete are the issues which were in the sprint but not done at the end of the sprint
+	Incomplete []SprintReportIssue `json:"incomplete"`
+	// Added are the issues which were added to the sprint after it was started
+	Added []SprintReportIssue `json:"added"`
+	// Removed are the issues which were removed from the sprint after it was started
+	Removed []SprintReportIssue `json:"removed"`
+
+	CommittedPoints float64 `json:"committedPoints"`
+	CompletedPoints float64 `json:"completedPoints"`
+	AddedPoints     float64 `json:"addedPoints"`
+	RemovedPoints   float64 `json:"removedPoints"`
+
+	// Days holds the burndown and burnup series, with one entry per day of the sprint
+	Days []SprintReportDay `json:"days"`
+}
+
+// SprintReportIssue is an issue of a sprint report together with its points
+type SprintReportIssue struct {
+	Key     string  `json:"key"`
+	Summary string  `json:"summary"`
+	Points  float64 `json:"points"`
+}
+
+// SprintReportDay holds the state of a sprint at the end of one day.
+// Remaining is the burndown value, Completed and Scope form the burnup chart.
+type SprintReportDay struct {
+	Date      time.Time `json:"date"`
+	Scope     float64   `json:"scope"`
+	Completed float64   `json:"completed"`
+	Remaining float64   `json:"remaining"`
+}
+
+// Velocity holds the committed and completed points of closed sprints, oldest sprint first
+type Velocity struct {
+	Sprints []SprintVelocity `json:"sprints"`
+	// AverageCompleted is the average of the completed points over all sprints
+	AverageCompleted float64 `json:"averageCompleted"`
+	// AverageCommitted is the average of the committed points over all sprints
+	AverageCommitted float64 `json:"averageCommitted"`
+}
+
+// SprintVelocity holds the committed and completed points of one sprint
+type SprintVelocity struct {
+	Sprint    Sprint  `json:"sprint"`
+	Committed float64 `json:"committed"`
+	Completed float64 `json:"completed"`
+}
+
+// GetReportWithContext computes the report of a sprint: committed vs. completed points,
+// the scope change after the start of the sprint and the daily burndown / burnup series.
+// The story points field is resolved by its name and the history of every issue is reconstructed from its changelog.
+//
+// Jira's sprint API only returns the issues which are still in the sprint. The issues which were removed
+// from the sprint are taken from the punted issues of the board's sprint report and loaded with a key search;
+// whether and when they left the sprint is read from the Sprint changes in their changelog.
+func (s *SprintService) GetReportWithContext(ctx context.Context, sprintID int, options *SprintReportOptions) (*SprintReport, error) {
+	sprint, _, err := s.GetWithContext(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	field, doneStatuses, err := s.reportSettings(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.report(ctx, *sprint, field, doneStatuses, time.Now())
+}
+
+// GetReport wraps GetReportWithContext using the background context.
+func (s *SprintService) GetReport(sprintID int, options *SprintReportOptions) (*SprintReport, error) {
+	return s.GetReportWithContext(context.Background(), sprintID, options)
+}
+
+// GetVelocityWithContext computes the velocity of a board over its last count closed sprints.
+func (s *BoardService) GetVelocityWithContext(ctx context.Context, boardID int, count int, options *SprintReportOptions) (*Velocity, error) {
+	var closed []Sprint
+	sprintOptions := &GetAllSprintsOptions{State: SprintStateClosed}
+	for {
+		sprints, _, err := s.GetAllSprintsWithOptionsWithContext(ctx, boardID, sprintOptions)
+		if err != nil {
+			return nil, err
+		}
+		closed = append(closed, sprints.Values...)
+		if sprints.IsLast || len(sprints.Values) == 0 {
+			break
+		}
+		sprintOptions.StartAt += len(sprints.Values)
+	}
+
+	sort.SliceStable(closed, func(i, j int) bool {
+		return sprintEnd(closed[i], time.Time{}).Before(sprintEnd(closed[j], time.Time{}))
+	})
+	if count > 0 && len(closed) > count {
+		closed = closed[len(closed)-count:]
+	}
+
+	field, doneStatuses, err := s.client.Sprint.reportSettings(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	velocity := &Velocity{}
+	for _, sprint := range closed {
+		if sprint.OriginBoardID == 0 {
+			sprint.OriginBoardID = boardID
+		}
+		report, err := s.client.Sprint.report(ctx, sprint, field, doneStatuses, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		velocity.Sprints = append(velocity.Sprints, SprintVelocity{
+			Sprint:    sprint,
+			Committed: report.CommittedPoints,
+			Completed: report.CompletedPoints,
+		})
+		velocity.AverageCommitted += report.CommittedPoints
+		velocity.AverageCompleted += report.CompletedPoints
+	}
+	if len(velocity.Sprints) > 0 {
+		velocity.AverageCommitted /= float64(len(velocity.Sprints))
+		velocity.AverageCompleted /= float64(len(velocity.Sprints))
+	}
+
+	return velocity, nil
+}
+
+// GetVelocity wraps GetVelocityWithContext using the background context.
+func (s *BoardService) GetVelocity(boardID int, count int, options *SprintReportOptions) (*Velocity, error) {
+	return s.GetVelocityWithContext(context.Background(), boardID, count, options)
+}
+
+// reportSettings resolves the story points field and the statuses of the "done" category.
+func (s *SprintService) reportSettings(ctx context.Context, options *SprintReportOptions) (*Field, map[string]bool, error) {
+	name := defaultStoryPointsField
+	if options != nil && options.StoryPointsField != "" {
+		name = options.StoryPointsField
+	}
+	field, _, err := s.client.Field.GetByNameWithContext(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	statuses, _, err := s.client.Status.GetAllStatusesWithContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	doneStatuses := make(map[string]bool)
+	for _, status := range statuses {
+		if status.StatusCategory.Key == "done" {
+			doneStatuses[status.ID] = true
+		}
+	}
+
+	return field, doneStatuses, nil
+}
+
+func (s *SprintService) report(ctx context.Context, sprint Sprint, field *Field, doneStatuses map[string]bool, now time.Time) (*SprintReport, error) {
+	var issues []Issue
+	options := &AgileIssuesOptions{
+		MaxResults: 50,
+		Expand:     "changelog",
+		Fields:     "summary,status,created,issuetype," + field.ID,
+	}
+	known := make(map[string]bool)
+	err := s.GetIssuesForSprintPagesWithContext(ctx, sprint.ID, options, func(issue Issue) error {
+		known[issue.Key] = true
+		issues = append(issues, issue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// issues which were removed from the sprint, only their changelog tells when
+	removed, err := s.puntedIssueKeys(ctx, sprint)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, key := range removed {
+		if !known[key] {
+			known[key] = true
+			keys = append(keys, key)
+		}
+	}
+	searchOptions := &SearchOptions{MaxResults: 50, Expand: "changelog", Fields: strings.Split(options.Fields, ","), ValidateQuery: "warn"}
+	err = s.client.Issue.SearchKeysWithContext(ctx, keys, searchOptions, func(issue Issue) error {
+		issues = append(issues, issue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSprintReport(sprint, issues, field, doneStatuses, now), nil
+}
+
+// sprintReportContents is the part of the Greenhopper sprint report listing the issues removed from a sprint
+type sprintReportContents struct {
+	Contents struct {
+		PuntedIssues []struct {
+			Key string `json:"key"`
+		} `json:"puntedIssues"`
+	} `json:"contents"`
+}
+
+// puntedIssueKeys returns the keys of the issues which were removed from a sprint, as listed by the
+// sprint report of the board the sprint belongs to. The agile API has no equivalent and JQL does not
+// support WAS for the sprint field. Sprints without a board have no punted issues.
+func (s *SprintService) puntedIssueKeys(ctx context.Context, sprint Sprint) ([]string, error) {
+	if sprint.OriginBoardID == 0 {
+		return nil, nil
+	}
+	apiEndpoint := fmt.Sprintf("rest/greenhopper/1.0/rapid/charts/sprintreport?rapidViewId=%d&sprintId=%d", sprint.OriginBoardID, sprint.ID)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", apiEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(sprintReportContents)
+	resp, err := s.client.Do(req, result)
+	if err != nil {
+		return nil, NewJiraError(resp, err)
+	}
+
+	keys := make([]string, 0, len(result.Contents.PuntedIssues))
+	for _, issue := range result.Contents.PuntedIssues {
+		keys = append(keys, issue.Key)
+	}
+	return keys, nil
+}
+
+// NewSprintReport computes a sprint report from the issues of the sprint (including their changelog).
+// field is the story points field and doneStatuses contains the ids of all statuses of the "done" category.
+// For sprints that are not completed yet, now is used as the end of the sprint.
+func NewSprintReport(sprint Sprint, issues []Issue, field *Field, doneStatuses map[string]bool, now time.Time) *SprintReport {
+	report := &SprintReport{Sprint: sprint, PointsField: field.ID}
+	if sprint.StartDate == nil {
+		return report
+	}
+	start := time.Time(*sprint.StartDate)
+	end := sprintEnd(sprint, now)
+
+	histories := make([]sprintIssueHistory, len(issues))
+	for i := range issues {
+		histories[i] = newSprintIssueHistory(&issues[i], sprint.ID, field)
+	}
+
+	for _, h := range histories {
+		inAtStart := h.inSprint(start)
+		inAtEnd := h.inSprint(end)
+
+		if inAtStart {
+			issue := h.reportIssue(start)
+			report.Committed = append(report.Committed, issue)
+			report.CommittedPoints += issue.Points
+		}
+		if !inAtStart && h.addedAfter(start, end) {
+			issue := h.reportIssue(end)
+			report.Added = append(report.Added, issue)
+			report.AddedPoints += issue.Points
+		}
+		if !inAtEnd && h.removedAfter(start, end) {
+			issue := h.reportIssue(end)
+			report.Removed = append(report.Removed, issue)
+			report.RemovedPoints += issue.Points
+		}
+		if inAtEnd {
+			issue := h.reportIssue(end)
+			if doneStatuses[statusAt(h.issue, h.statusChanges, end)] {
+				report.Completed = append(report.Completed, issue)
+				report.CompletedPoints += issue.Points
+			} else {
+				report.Incomplete = append(report.Incomplete, issue)
+			}
+		}
+	}
+
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	for day.Before(end) {
+		at := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		if at.After(end) {
+			at = end
+		}
+		entry := SprintReportDay{Date: day}
+		for _, h := range histories {
+			if !h.inSprint(at) {
+				continue
+			}
+			points := h.pointsAt(at)
+			entry.Scope += points
+			if doneStatuses[statusAt(h.issue, h.statusChanges, at)] {
+				entry.Completed += points
+			}
+		}
+		entry.Remaining = entry.Scope - entry.Completed
+		report.Days = append(report.Days, entry)
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return report
+}
+
+// sprintEnd returns the point in time a sprint ended, or now for sprints which are not completed yet.
+func sprintEnd(sprint Sprint, now time.Time) time.Time {
+	if sprint.CompleteDate != nil {
+		return time.Time(*sprint.CompleteDate)
+	}
+	if sprint.State == SprintStateClosed && sprint.EndDate != nil {
+		return time.Time(*sprint.EndDate)
+	}
+	return now
+}
+
+// sprintIssueHistory holds the changes of an issue which are relevant for a sprint report
+type sprintIssueHistory struct {
+	issue         *Issue
+	sprintID      string
+	field         *Field
+	sprintChanges []fieldChange
+	pointChanges  []fieldChange
+	statusChanges []fieldChange
+}
+
+func newSprintIssueHistory(issue *Issue, sprintID int, field *Field) sprintIssueHistory {
+	return sprintIssueHistory{
+		issue:         issue,
+		sprintID:      strconv.Itoa(sprintID),
+		field:         field,
+		sprintChanges: fieldChanges(issue, "Sprint"),
+		pointChanges:  fieldChanges(issue, field.Name),
+		statusChanges: fieldChanges(issue, "status"),
+	}
+}
+
+// inSprint reports whether the issue was part of the sprint at the given point in time.
+// Issues without a sprint history are considered to be in the sprint since their creation.
+func (h sprintIssueHistory) inSprint(at time.Time) bool {
+	if h.issue.Fields != nil {
+		created := time.Time(h.issue.Fields.Created)
+		if !created.IsZero() && created.After(at) {
+			return false
+		}
+	}
+	if len(h.sprintChanges) == 0 {
+		return true
+	}
+
+	in := containsSprintID(h.sprintChanges[0].From, h.sprintID)
+	for _, change := range h.sprintChanges {
+		if change.At.After(at) {
+			break
+		}
+		in = containsSprintID(change.To, h.sprintID)
+	}
+	return in
+}
+
+// addedAfter reports whether the issue was added to the sprint in the given period
+func (h sprintIssueHistory) addedAfter(from, to time.Time) bool {
+	if len(h.sprintChanges) == 0 {
+		if h.issue.Fields == nil {
+			return false
+		}
+		created := time.Time(h.issue.Fields.Created)
+		return created.After(from) && !created.After(to)
+	}
+	for _, change := range h.sprintChanges {
+		if change.At.After(from) && !change.At.After(to) &&
+			!containsSprintID(change.From, h.sprintID) && containsSprintID(change.To, h.sprintID) {
+			return true
+		}
+	}
+	return false
+}
+
+// removedAfter reports whether the issue was removed from the sprint in the given period
+func (h sprintIssueHistory) removedAfter(from, to time.Time) bool {
+	for _, change := range h.sprintChanges {
+		if change.At.After(from) && !change.At.After(to) &&
+			containsSprintID(change.From, h.sprintID) && !containsSprintID(change.To, h.sprintID) {
+			return true
+		}
+	}
+	return false
+}
+
+// pointsAt returns the story points of the issue at the given point in time
+func (h sprintIssueHistory) pointsAt(at time.Time) float64 {
+	points := h.currentPoints()
+	if len(h.pointChanges) > 0 {
+		points = parsePoints(h.pointChanges[0].FromString)
+	}
+	for _, change := range h.pointChanges {
+		if change.At.After(at) {
+			break
+		}
+		points = parsePoints(change.ToString)
+	}
+	return points
+}
+
+func (h sprintIssueHistory) currentPoints() float64 {
+	if h.issue.Fields == nil {
+		return 0
+	}
+	switch value := h.issue.Fields.Unknowns[h.field.ID].(type) {
+	case float64:
+		return value
+	case string:
+		return parsePoints(value)
+	}
+	return 0
+}
+
+func (h sprintIssueHistory) reportIssue(at time.Time) SprintReportIssue {
+	issue := SprintReportIssue{Key: h.issue.Key, Points: h.pointsAt(at)}
+	if h.issue.Fields != nil {
+		issue.Summary = h.issue.Fields.Summary
+	}
+	return issue
+}
+
+// containsSprintID reports whether a comma separated list of sprint ids, as found in the changelog, contains id
+func containsSprintID(ids string, id string) bool {
+	for _, value := range strings.Split(ids, ",") {
+		if strings.TrimSpace(value) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePoints parses the string representation of story points, as found in the changelog
+func parsePoints(value string) float64 {
+	points, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0
+	}
+	return points
+}
@@ -0,0 +1,227 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const testSprintReportIssues = `{"startAt":0,"maxResults":50,"total":4,"issues":[
+{"key":"TST-1","fields":{"summary":"A","created":"2023-01-01T08:00:00.000+0000","status":{"id":"3"},"customfield_10002":3},
+ "changelog":{"histories":[
+  {"id":"1","created":"2023-01-01T12:00:00.000+0000","items":[{"field":"Sprint","from":"","to":"37"}]},
+  {"id":"2","created":"2023-01-03T10:00:00.000+0000","items":[{"field":"status","from":"1","to":"3"}]}]}},
+{"key":"TST-2","fields":{"summary":"B","created":"2023-01-01T08:00:00.000+0000","status":{"id":"1"},"customfield_10002":8},
+ "changelog":{"histories":[
+  {"id":"3","created":"2023-01-01T12:00:00.000+0000","items":[{"field":"Sprint","from":"","to":"37"}]},
+  {"id":"4","created":"2023-01-03T08:00:00.000+0000","items":[{"field":"Story Points","fromString":"5","toString":"8"}]}]}},
+{"key":"TST-3","fields":{"summary":"C","created":"2023-01-03T09:00:00.000+0000","status":{"id":"3"},"customfield_10002":2},
+ "changelog":{"histories":[
+  {"id":"5","created":"2023-01-04T10:00:00.000+0000","items":[{"field":"status","from":"1","to":"3"}]}]}},
+{"key":"TST-4","fields":{"summary":"D","created":"2023-01-01T08:00:00.000+0000","status":{"id":"1"},"customfield_10002":1},
+ "changelog":{"histories":[
+  {"id":"6","created":"2023-01-01T12:00:00.000+0000","items":[{"field":"Sprint","from":"","to":"37"}]},
+  {"id":"7","created":"2023-01-03T12:00:00.000+0000","items":[{"field":"Sprint","from":"37","to":"38"}]}]}}
+]}`
+
+func testSprintReportSprint() Sprint {
+	start := Time(time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC))
+	complete := Time(time.Date(2023, 1, 4, 18, 0, 0, 0, time.UTC))
+	return Sprint{ID: 37, Name: "Sprint 1", State: SprintStateClosed, StartDate: &start, CompleteDate: &complete}
+}
+
+func TestNewSprintReport(t *testing.T) {
+	result := new(SearchResult)
+	if err := json.Unmarshal([]byte(testSprintReportIssues), result); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	field := &Field{ID: "customfield_10002", Name: "Story Points"}
+	report := NewSprintReport(testSprintReportSprint(), result.Issues, field, map[string]bool{"3": true}, time.Now())
+
+	if report.CommittedPoints != 9 {
+		t.Errorf("Expected 9 committed points, got %g", report.CommittedPoints)
+	}
+	if report.CompletedPoints != 5 {
+		t.Errorf("Expected 5 completed points, got %g", report.CompletedPoints)
+	}
+	if report.AddedPoints != 2 || len(report.Added) != 1 || report.Added[0].Key != "TST-3" {
+		t.Errorf("Expected TST-3 to be added with 2 points, got %v", report.Added)
+	}
+	if report.RemovedPoints != 1 || len(report.Removed) != 1 || report.Removed[0].Key != "TST-4" {
+		t.Errorf("Expected TST-4 to be removed with 1 point, got %v", report.Removed)
+	}
+	if len(report.Incomplete) != 1 || report.Incomplete[0].Points != 8 {
+		t.Errorf("Expected TST-2 to be incomplete with 8 points, got %v", report.Incomplete)
+	}
+
+	expected := []SprintReportDay{
+		{Scope: 9, Completed: 0, Remaining: 9},
+		{Scope: 13, Completed: 3, Remaining: 10},
+		{Scope: 13, Completed: 5, Remaining: 8},
+	}
+	if len(report.Days) != len(expected) {
+		t.Fatalf("Expected %d days, got %d", len(expected), len(report.Days))
+	}
+	for i, day := range report.Days {
+		if day.Scope != expected[i].Scope || day.Completed != expected[i].Completed || day.Remaining != expected[i].Remaining {
+			t.Errorf("Day %s: expected %+v, got %+v", day.Date.Format("2006-01-02"), expected[i], day)
+		}
+	}
+}
+
+func TestNewSprintReport_FutureSprint(t *testing.T) {
+	report := NewSprintReport(Sprint{ID: 1, State: SprintStateFuture}, nil, &Field{ID: "customfield_10002"}, nil, time.Now())
+	if len(report.Days) != 0 || report.CommittedPoints != 0 {
+		t.Errorf("Expected an empty report for a sprint which has not started, got %+v", report)
+	}
+}
+
+func TestNewSprintReport_NoFields(t *testing.T) {
+	report := NewSprintReport(testSprintReportSprint(), []Issue{{Key: "TST-1"}}, &Field{ID: "customfield_10002"}, nil, time.Now())
+	if len(report.Committed) != 1 || len(report.Added) != 0 {
+		t.Errorf("Expected an issue without fields to be committed, got %+v", report)
+	}
+}
+
+func TestNewSprintReport_Removed(t *testing.T) {
+	issues := []Issue{
+		// moved to the next sprint during the sprint
+		{Key: "TST-1", Fields: &IssueFields{Created: Time(time.Date(2023, 1, 1, 8, 0, 0, 0, time.UTC))}, Changelog: &Changelog{Histories: []ChangelogHistory{
+			{Created: "2023-01-01T12:00:00.000+0000", Items: []ChangelogItems{{Field: "Sprint", From: "", To: "37"}}},
+			{Created: "2023-01-03T12:00:00.000+0000", Items: []ChangelogItems{{Field: "Sprint", From: "37", To: "38"}}}}}},
+		// removed and added back during the sprint
+		{Key: "TST-2", Fields: &IssueFields{Created: Time(time.Date(2023, 1, 1, 8, 0, 0, 0, time.UTC))}, Changelog: &Changelog{Histories: []ChangelogHistory{
+			{Created: "2023-01-01T12:00:00.000+0000", Items: []ChangelogItems{{Field: "Sprint", From: "", To: "37"}}},
+			{Created: "2023-01-02T12:00:00.000+0000", Items: []ChangelogItems{{Field: "Sprint", From: "37", To: ""}}},
+			{Created: "2023-01-03T12:00:00.000+0000", Items: []ChangelogItems{{Field: "Sprint", From: "", To: "37"}}}}}},
+		// removed before the sprint was started
+		{Key: "TST-3", Fields: &IssueFields{Created: Time(time.Date(2023, 1, 1, 8, 0, 0, 0, time.UTC))}, Changelog: &Changelog{Histories: []ChangelogHistory{
+			{Created: "2023-01-01T12:00:00.000+0000", Items: []ChangelogItems{{Field: "Sprint", From: "", To: "37"}}},
+			{Created: "2023-01-01T14:00:00.000+0000", Items: []ChangelogItems{{Field: "Sprint", From: "37", To: "36, 38"}}}}}},
+		// removed after the sprint was completed
+		{Key: "TST-4", Fields: &IssueFields{Created: Time(time.Date(2023, 1, 1, 8, 0, 0, 0, time.UTC))}, Changelog: &Changelog{Histories: []ChangelogHistory{
+			{Created: "2023-01-01T12:00:00.000+0000", Items: []ChangelogItems{{Field: "Sprint", From: "", To: "37"}}},
+			{Created: "2023-01-05T12:00:00.000+0000", Items: []ChangelogItems{{Field: "Sprint", From: "37", To: "38"}}}}}},
+	}
+
+	report := NewSprintReport(testSprintReportSprint(), issues, &Field{ID: "customfield_10002", Name: "Story Points"}, nil, time.Now())
+	if len(report.Removed) != 1 || report.Removed[0].Key != "TST-1" {
+		t.Errorf("Expected only TST-1 to be removed, got %v", report.Removed)
+	}
+	if len(report.Committed) != 3 {
+		t.Errorf("Expected TST-1, TST-2 and TST-4 to be committed, got %v", report.Committed)
+	}
+}
+
+func testSprintReportHandlers(t *testing.T) {
+	testMux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id":"summary","name":"Summary"},{"id":"customfield_10002","name":"Story Points","custom":true}]`)
+	})
+	testMux.HandleFunc("/rest/api/2/status", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `[{"id":"1","name":"Open","statusCategory":{"key":"new"}},{"id":"3","name":"Done","statusCategory":{"key":"done"}}]`)
+	})
+}
+
+func TestSprintService_GetReport(t *testing.T) {
+	setup()
+	defer teardown()
+	testSprintReportHandlers(t)
+
+	testMux.HandleFunc("/rest/agile/1.0/sprint/37", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"id":37,"name":"Sprint 1","state":"closed","originBoardId":5,"startDate":"2023-01-02T09:00:00.000Z","completeDate":"2023-01-04T18:00:00.000Z"}`)
+	})
+	// TST-4 was moved to the next sprint, so only the punted issues of the board's sprint report list it
+	var all struct {
+		Issues []json.RawMessage `json:"issues"`
+	}
+	if err := json.Unmarshal([]byte(testSprintReportIssues), &all); err != nil {
+		t.Fatal(err)
+	}
+	testMux.HandleFunc("/rest/agile/1.0/sprint/37/issue", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("expand"); got != "changelog" {
+			t.Errorf("Expected changelog to be expanded, got %q", got)
+		}
+		fmt.Fprintf(w, `{"startAt":0,"maxResults":50,"total":3,"issues":[%s,%s,%s]}`, all.Issues[0], all.Issues[1], all.Issues[2])
+	})
+	testMux.HandleFunc("/rest/greenhopper/1.0/rapid/charts/sprintreport", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestParams(t, r, map[string]string{"rapidViewId": "5", "sprintId": "37"})
+		fmt.Fprint(w, `{"contents":{"completedIssues":[],"puntedIssues":[{"id":10004,"key":"TST-4"}]}}`)
+	})
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		if got := r.URL.Query().Get("jql"); got != "key in (TST-4)" {
+			t.Errorf("Unexpected JQL %q", got)
+		}
+		if got := r.URL.Query().Get("expand"); got != "changelog" {
+			t.Errorf("Expected changelog to be expanded, got %q", got)
+		}
+		fmt.Fprintf(w, `{"startAt":0,"maxResults":50,"total":1,"issues":[%s]}`, all.Issues[3])
+	})
+
+	report, err := testClient.Sprint.GetReport(37, nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if report.PointsField != "customfield_10002" {
+		t.Errorf("Expected story points field customfield_10002, got %s", report.PointsField)
+	}
+	if report.CommittedPoints != 9 || report.CompletedPoints != 5 {
+		t.Errorf("Expected 9 committed and 5 completed points, got %g and %g", report.CommittedPoints, report.CompletedPoints)
+	}
+	if len(report.Removed) != 1 || report.Removed[0].Key != "TST-4" {
+		t.Errorf("Expected TST-4 to be removed, got %v", report.Removed)
+	}
+}
+
+func TestBoardService_GetVelocity(t *testing.T) {
+	setup()
+	defer teardown()
+	testSprintReportHandlers(t)
+
+	testMux.HandleFunc("/rest/agile/1.0/board/5/sprint", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestParams(t, r, map[string]string{"state": "closed"})
+		fmt.Fprint(w, `{"isLast":true,"values":[
+			{"id":36,"state":"closed","startDate":"2022-12-01T09:00:00.000Z","completeDate":"2022-12-14T18:00:00.000Z"},
+			{"id":37,"state":"closed","startDate":"2023-01-02T09:00:00.000Z","completeDate":"2023-01-04T18:00:00.000Z"},
+			{"id":35,"state":"closed","startDate":"2022-11-01T09:00:00.000Z","completeDate":"2022-11-14T18:00:00.000Z"}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/36/issue", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"startAt":0,"maxResults":50,"total":1,"issues":[
+			{"key":"TST-9","fields":{"created":"2022-11-20T08:00:00.000+0000","status":{"id":"3"},"customfield_10002":3}}]}`)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/37/issue", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testSprintReportIssues)
+	})
+	testMux.HandleFunc("/rest/agile/1.0/sprint/35/issue", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected the oldest sprint not to be part of the velocity")
+	})
+	testMux.HandleFunc("/rest/greenhopper/1.0/rapid/charts/sprintreport", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("rapidViewId"); got != "5" {
+			t.Errorf("Expected the sprint report of board 5, got %q", got)
+		}
+		fmt.Fprint(w, `{"contents":{"puntedIssues":[]}}`)
+	})
+
+	velocity, err := testClient.Board.GetVelocity(5, 2, nil)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(velocity.Sprints) != 2 || velocity.Sprints[0].Sprint.ID != 36 || velocity.Sprints[1].Sprint.ID != 37 {
+		t.Fatalf("Expected sprints 36 and 37, got %+v", velocity.Sprints)
+	}
+	if velocity.AverageCompleted != 4 {
+		t.Errorf("Expected an average of 4 completed points, got %g", velocity.AverageCompleted)
+	}
+	if velocity.AverageCommitted != 6 {
+		t.Errorf("Expected an average of 6 committed points, got %g", velocity.AverageCommitted)
+	}
+}
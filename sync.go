@@ -0,0 +1,369 @@
+package jira
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// defaultSyncOverlap is the time subtracted from the checkpoint of a sync, for clock skew and index lag of Jira
+const defaultSyncOverlap = time.Minute
+
+// defaultSyncReconcileInterval is the time between two reconciliations of a sync if no interval is given
+const defaultSyncReconcileInterval = 24 * time.Hour
+
+// orderByClause matches the ORDER BY clause at the end of a JQL query
+var orderByClause = regexp.MustCompile(`(?is)\s*\border\s+by\s+[^"']*$`)
+
+// SyncEventType is the type of a sync event
+type SyncEventType string
+
+// The types of sync events
+const (
+	SyncUpsert SyncEventType = "upsert"
+	SyncDelete SyncEventType = "delete"
+)
+
+// SyncEvent is a change of an issue found by a sync. Issue is nil for deletions.
+type SyncEvent struct {
+	Type  SyncEventType
+	Key   string
+	Issue *Issue
+}
+
+// SyncCheckpoint is the state of an incremental sync, saved after every successful sync
+type SyncCheckpoint struct {
+	// Updated is the newest updated time of the synchronized issues
+	Updated time.Time `json:"updated"`
+	// Reconciled is the time of the last reconciliation of the issue keys
+	Reconciled time.Time `json:"reconciled"`
+	// Issues holds the updated time of every synchronized issue by key
+	Issues map[string]time.Time `json:"issues"`
+}
+
+// CheckpointStore persists the checkpoints of syncs by name
+type CheckpointStore interface {
+	// Load returns the checkpoint with the given name, or nil if there is none
+	Load(name string) (*SyncCheckpoint, error)
+	// Save stores the checkpoint with the given name
+	Save(name string, checkpoint *SyncCheckpoint) error
+}
+
+// FileCheckpointStore stores every checkpoint in a JSON file in a directory
+type FileCheckpointStore struct {
+	Dir string
+}
+
+// NewFileCheckpointStore returns a checkpoint store writing to dir, which is created if needed
+func NewFileCheckpointStore(dir string) *FileCheckpointStore {
+	return &FileCheckpointStore{Dir: dir}
+}
+
+// path returns the file of a checkpoint. Names are hashed, as they are usually JQL queries.
+func (f *FileCheckpointStore) path(name string) string {
+	sum := sha1.Sum([]byte(name))
+	return filepath.Join(f.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Load reads the checkpoint with the given name, or returns nil if there is no file
+func (f *FileCheckpointStore) Load(name string) (*SyncCheckpoint, error) {
+	data, err := ioutil.ReadFile(f.path(name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := new(SyncCheckpoint)
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", f.path(name), err)
+	}
+	return checkpoint, nil
+}
+
+// Save writes the checkpoint with the given name. The file is replaced atomically.
+func (f *FileCheckpointStore) Save(name string, checkpoint *SyncCheckpoint) error {
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(f.Dir, "checkpoint-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(name))
+}
+
+// SyncOptions specifies the optional parameters of Sync
+type SyncOptions struct {
+	// Name is the name of the checkpoint. Default: the JQL query.
+	Name string
+	// Store persists the checkpoints. Default: a FileCheckpointStore in the user cache directory.
+	Store CheckpointStore
+	// Fields are the fields of the synchronized issues; updated is always added. Default: all navigable fields.
+	Fields []string
+	// Expand: Expand specific sections in the synchronized issues
+	Expand string
+	// PageSize is the number of issues fetched with one search request. Default: 100.
+	PageSize int
+	// Overlap is subtracted from the checkpoint, to catch issues indexed late. Default: one minute.
+	Overlap time.Duration
+	// ReconcileInterval is the time between two searches for deleted issues. Default: 24 hours.
+	// A negative interval disables the reconciliation.
+	ReconcileInterval time.Duration
+	// Location is the time zone of the user, which Jira uses for the timestamps in JQL.
+	// Default: the time zone of the current user (see UserService.GetSelfLocation).
+	Location *time.Location
+}
+
+// defaultCheckpointStore returns the file store in the cache directory of the user
+func defaultCheckpointStore() (CheckpointStore, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return NewFileCheckpointStore(filepath.Join(dir, "jira-client", "sync")), nil
+}
+
+// SyncWithContext synchronizes the issues matching a JQL query incrementally, calling f for every change.
+// The first sync emits all issues. Later syncs only search the issues updated since the checkpoint
+// and skip issues which did not change since they were emitted. From time to time (see SyncOptions.ReconcileInterval)
+// the keys of all matching issues are loaded: issues which were deleted or left the query are emitted as deletions,
+// issues which entered the query without update are emitted as upserts.
+//
+// The checkpoint is saved only if the sync and all calls of f succeed, so every change is emitted at least once.
+// Issues are paged by their updated time rather than by offset (see syncPagesWithContext), so issues updated
+// while the sync runs do not cause other issues to be skipped; they are emitted again with their new update.
+// The query must not sort by a field containing quotes, as its ORDER BY clause is replaced.
+func (s *IssueService) SyncWithContext(ctx context.Context, jql string, options *SyncOptions, f func(SyncEvent) error) (*SyncCheckpoint, error) {
+	opts := SyncOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Name == "" {
+		opts.Name = jql
+	}
+	if opts.Store == nil {
+		store, err := defaultCheckpointStore()
+		if err != nil {
+			return nil, err
+		}
+		opts.Store = store
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 100
+	}
+	if opts.Overlap == 0 {
+		opts.Overlap = defaultSyncOverlap
+	}
+	if opts.ReconcileInterval == 0 {
+		opts.ReconcileInterval = defaultSyncReconcileInterval
+	}
+	if opts.Location == nil {
+		loc, err := s.client.User.GetSelfLocationWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		opts.Location = loc
+	}
+	if len(opts.Fields) > 0 && !containsString(opts.Fields, "updated") && !containsString(opts.Fields, "*all") {
+		opts.Fields = append(append([]string{}, opts.Fields...), "updated")
+	}
+
+	checkpoint, err := opts.Store.Load(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	full := checkpoint == nil
+	if full {
+		checkpoint = &SyncCheckpoint{}
+	}
+	if checkpoint.Issues == nil {
+		checkpoint.Issues = make(map[string]time.Time)
+	}
+
+	query := orderByClause.ReplaceAllString(jql, "")
+	searchOptions := &SearchOptions{MaxResults: opts.PageSize, Fields: opts.Fields, Expand: opts.Expand}
+
+	upsert := func(issue Issue) error {
+		var updated time.Time
+		if issue.Fields != nil {
+			updated = time.Time(issue.Fields.Updated)
+		}
+		if previous, ok := checkpoint.Issues[issue.Key]; ok && !updated.After(previous) {
+			return nil
+		}
+		if err := f(SyncEvent{Type: SyncUpsert, Key: issue.Key, Issue: &issue}); err != nil {
+			return err
+		}
+		checkpoint.Issues[issue.Key] = updated
+		if updated.After(checkpoint.Updated) {
+			checkpoint.Updated = updated
+		}
+		return nil
+	}
+
+	var since *time.Time
+	if !full {
+		t := checkpoint.Updated.Add(-opts.Overlap)
+		since = &t
+	}
+	started := time.Now()
+	if err := s.syncPagesWithContext(ctx, query, since, searchOptions, opts.Location, upsert); err != nil {
+		return nil, err
+	}
+
+	if full {
+		checkpoint.Reconciled = started
+	} else if opts.ReconcileInterval > 0 && started.Sub(checkpoint.Reconciled) >= opts.ReconcileInterval {
+		if err := s.reconcileWithContext(ctx, query, checkpoint, searchOptions, upsert, f); err != nil {
+			return nil, err
+		}
+		checkpoint.Reconciled = started
+	}
+
+	if err := opts.Store.Save(opts.Name, checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// Sync wraps SyncWithContext using the background context.
+func (s *IssueService) Sync(jql string, options *SyncOptions, f func(SyncEvent) error) (*SyncCheckpoint, error) {
+	return s.SyncWithContext(context.Background(), jql, options, f)
+}
+
+// syncPagesWithContext calls f for the issues matching the query which were updated since the given time,
+// ordered by their updated time. Pages are not read by offset, as an issue updated during the sync moves to the end
+// of the result and would shift all later pages: every page starts at the minute (the precision of JQL) of the last issue
+// of the previous page, so f is called again for issues at the page boundaries. Only a minute with more issues than fit
+// on a page is read by offset, and read again from its start whenever its number of issues changes.
+func (s *IssueService) syncPagesWithContext(ctx context.Context, query string, since *time.Time, options *SearchOptions, loc *time.Location, f func(Issue) error) error {
+	minute := false
+	offset, total := 0, 0
+	for {
+		searchQuery := query
+		if since != nil {
+			clause := "updated >= " + FormatJQLTime(*since, loc)
+			if minute {
+				clause += " AND updated < " + FormatJQLTime(since.Add(time.Minute), loc)
+			}
+			if query == "" {
+				searchQuery = clause
+			} else {
+				searchQuery = fmt.Sprintf("(%s) AND %s", query, clause)
+			}
+		}
+		pageOptions := *options
+		pageOptions.StartAt = offset
+		result, _, err := s.SearchWithContext(ctx, searchQuery+" ORDER BY updated ASC, key ASC", &pageOptions)
+		if err != nil {
+			return err
+		}
+		issues := result.Issues
+		for _, issue := range issues {
+			if err := f(issue); err != nil {
+				return err
+			}
+		}
+
+		if minute && offset > 0 && result.Total != total {
+			// issues entered or left the minute, which shifts the offsets
+			offset, total = 0, result.Total
+			continue
+		}
+		total = result.Total
+		if len(issues) == 0 || len(issues) < result.MaxResults || result.StartAt+len(issues) >= result.Total {
+			if !minute {
+				return nil
+			}
+			// the minute is complete, continue with the issues updated after it
+			next := since.Add(time.Minute)
+			since, minute, offset = &next, false, 0
+			continue
+		}
+
+		var last time.Time
+		if fields := issues[len(issues)-1].Fields; fields != nil {
+			last = time.Time(fields.Updated).Truncate(time.Minute)
+		}
+		switch {
+		case minute:
+			offset += len(issues)
+		case since == nil || last.After(since.Truncate(time.Minute)):
+			since, offset = &last, 0
+		default:
+			// the whole page was updated in the same minute
+			start := since.Truncate(time.Minute)
+			since, minute, offset = &start, true, 0
+		}
+	}
+}
+
+// reconcileWithContext compares the keys of all issues matching the query with the keys of the checkpoint,
+// emitting deletions for the missing issues and upserts for the new ones
+func (s *IssueService) reconcileWithContext(ctx context.Context, query string, checkpoint *SyncCheckpoint, options *SearchOptions, upsert func(Issue) error, f func(SyncEvent) error) error {
+	current := make(map[string]bool)
+	err := s.SearchPagesWithContext(ctx, query, &SearchOptions{MaxResults: options.MaxResults, Fields: []string{"key"}}, func(issue Issue) error {
+		current[issue.Key] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var deleted, added []string
+	for key := range checkpoint.Issues {
+		if !current[key] {
+			deleted = append(deleted, key)
+		}
+	}
+	for key := range current {
+		if _, ok := checkpoint.Issues[key]; !ok {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(deleted)
+	sort.Strings(added)
+
+	for _, key := range deleted {
+		if err := f(SyncEvent{Type: SyncDelete, Key: key}); err != nil {
+			return err
+		}
+		delete(checkpoint.Issues, key)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	return s.SearchKeysWithContext(ctx, added, options, upsert)
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
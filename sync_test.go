@@ -0,0 +1,217 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type memoryCheckpointStore map[string]*SyncCheckpoint
+
+func (m memoryCheckpointStore) Load(name string) (*SyncCheckpoint, error) {
+	return m[name], nil
+}
+
+func (m memoryCheckpointStore) Save(name string, checkpoint *SyncCheckpoint) error {
+	m[name] = checkpoint
+	return nil
+}
+
+func TestIssueService_Sync(t *testing.T) {
+	setup()
+	defer teardown()
+
+	results := map[string]string{
+		`project = TST ORDER BY updated ASC, key ASC`: `[
+			{"key": "TST-1", "fields": {"updated": "2021-03-01T10:00:30.000+0000"}},
+			{"key": "TST-2", "fields": {"updated": "2021-03-01T10:05:00.000+0000"}}]`,
+		`(project = TST) AND updated >= "2021-03-01 10:04" ORDER BY updated ASC, key ASC`: `[
+			{"key": "TST-2", "fields": {"updated": "2021-03-01T10:05:00.000+0000"}},
+			{"key": "TST-3", "fields": {"updated": "2021-03-01T10:10:00.000+0000"}}]`,
+		`(project = TST) AND updated >= "2021-03-01 10:09" ORDER BY updated ASC, key ASC`: `[
+			{"key": "TST-3", "fields": {"updated": "2021-03-01T10:10:00.000+0000"}}]`,
+		`project = TST`:  `[{"key": "TST-1"}, {"key": "TST-3"}, {"key": "TST-4"}]`,
+		`key in (TST-4)`: `[{"key": "TST-4", "fields": {"updated": "2021-02-01T08:00:00.000+0000"}}]`,
+	}
+	var queries []string
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		jql := r.URL.Query().Get("jql")
+		queries = append(queries, jql)
+		issues, ok := results[jql]
+		if !ok {
+			t.Errorf("Unexpected query %s", jql)
+			issues = "[]"
+		}
+		_, _ = fmt.Fprintf(w, `{"startAt": 0, "maxResults": 50, "total": 3, "issues": %s}`, issues)
+	})
+
+	store := memoryCheckpointStore{}
+	options := &SyncOptions{Store: store, Location: time.UTC, ReconcileInterval: -1}
+	var events []string
+	record := func(event SyncEvent) error {
+		events = append(events, string(event.Type)+" "+event.Key)
+		return nil
+	}
+
+	if _, err := testClient.Issue.Sync("project = TST ORDER BY created DESC", options, record); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if fmt.Sprint(events) != "[upsert TST-1 upsert TST-2]" {
+		t.Errorf("Unexpected events of the first sync %v", events)
+	}
+
+	events = nil
+	checkpoint, err := testClient.Issue.Sync("project = TST ORDER BY created DESC", options, record)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if fmt.Sprint(events) != "[upsert TST-3]" {
+		t.Errorf("Unexpected events of the second sync %v", events)
+	}
+	if !checkpoint.Updated.Equal(time.Date(2021, time.March, 1, 10, 10, 0, 0, time.UTC)) || len(checkpoint.Issues) != 3 {
+		t.Errorf("Unexpected checkpoint %+v", checkpoint)
+	}
+
+	events = nil
+	options.ReconcileInterval = time.Nanosecond
+	if _, err := testClient.Issue.Sync("project = TST ORDER BY created DESC", options, record); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if fmt.Sprint(events) != "[delete TST-2 upsert TST-4]" {
+		t.Errorf("Unexpected events of the reconciling sync %v", events)
+	}
+	if _, ok := store["project = TST ORDER BY created DESC"].Issues["TST-2"]; ok {
+		t.Error("Expected TST-2 to be removed from the checkpoint")
+	}
+	if len(queries) != 5 {
+		t.Errorf("Unexpected queries %q", queries)
+	}
+}
+
+// testSyncServer serves the issues with the given updated times for the queries of a sync, evaluating their
+// updated clauses. update is called before every request, to change issues during the sync.
+func testSyncServer(t *testing.T, issues map[string]time.Time, update func(request int)) {
+	clause := regexp.MustCompile(`updated ([<>]=?) "([^"]+)"`)
+	request := 0
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		update(request)
+		request++
+		if request > 100 {
+			t.Fatal("Too many requests")
+		}
+
+		var keys []string
+		for key, updated := range issues {
+			match := true
+			for _, c := range clause.FindAllStringSubmatch(r.URL.Query().Get("jql"), -1) {
+				limit, _ := time.Parse("2006-01-02 15:04", c[2])
+				if (c[1] == ">=" && updated.Before(limit)) || (c[1] == "<" && !updated.Before(limit)) {
+					match = false
+				}
+			}
+			if match {
+				keys = append(keys, key)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if !issues[keys[i]].Equal(issues[keys[j]]) {
+				return issues[keys[i]].Before(issues[keys[j]])
+			}
+			return keys[i] < keys[j]
+		})
+
+		startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+		maxResults, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+		var page []string
+		for i := startAt; i < len(keys) && i < startAt+maxResults; i++ {
+			page = append(page, fmt.Sprintf(`{"key": %q, "fields": {"updated": %q}}`, keys[i], issues[keys[i]].Format("2006-01-02T15:04:05.000-0700")))
+		}
+		_, _ = fmt.Fprintf(w, `{"startAt": %d, "maxResults": %d, "total": %d, "issues": [%s]}`, startAt, maxResults, len(keys), strings.Join(page, ","))
+	})
+}
+
+func TestIssueService_Sync_UpdatedDuringSync(t *testing.T) {
+	base := time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+	for name, step := range map[string]time.Duration{"minutes": time.Minute, "same minute": time.Second} {
+		t.Run(name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			issues := make(map[string]time.Time)
+			for i := 1; i <= 5; i++ {
+				issues[fmt.Sprintf("TST-%d", i)] = base.Add(time.Duration(i) * step)
+			}
+			testSyncServer(t, issues, func(request int) {
+				if request == 2 {
+					// an issue already emitted moves to the end of the result
+					issues["TST-1"] = base.Add(30 * time.Minute)
+				}
+			})
+
+			var events []string
+			checkpoint, err := testClient.Issue.Sync("project = TST", &SyncOptions{Store: memoryCheckpointStore{}, Location: time.UTC, PageSize: 2}, func(event SyncEvent) error {
+				events = append(events, event.Key)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Error given: %s", err)
+			}
+			if fmt.Sprint(events) != "[TST-1 TST-2 TST-3 TST-4 TST-5 TST-1]" {
+				t.Errorf("Unexpected events %v", events)
+			}
+			if !checkpoint.Updated.Equal(base.Add(30 * time.Minute)) {
+				t.Errorf("Unexpected checkpoint %v", checkpoint.Updated)
+			}
+		})
+	}
+}
+
+func TestIssueService_Sync_CallbackError(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"startAt": 0, "maxResults": 50, "total": 1, "issues": [{"key": "TST-1", "fields": {"updated": "2021-03-01T10:00:30.000+0000"}}]}`)
+	})
+
+	store := memoryCheckpointStore{}
+	_, err := testClient.Issue.Sync("project = TST", &SyncOptions{Store: store, Location: time.UTC}, func(event SyncEvent) error {
+		return fmt.Errorf("warehouse down")
+	})
+	if err == nil || err.Error() != "warehouse down" {
+		t.Errorf("Expected the error of the callback, got %v", err)
+	}
+	if len(store) != 0 {
+		t.Error("Expected no checkpoint after a failed sync")
+	}
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	store := NewFileCheckpointStore(t.TempDir() + "/checkpoints")
+
+	checkpoint, err := store.Load("project = TST")
+	if err != nil || checkpoint != nil {
+		t.Fatalf("Expected no checkpoint, got %v, %v", checkpoint, err)
+	}
+
+	updated := time.Date(2021, time.March, 1, 10, 0, 0, 0, time.UTC)
+	err = store.Save("project = TST", &SyncCheckpoint{Updated: updated, Issues: map[string]time.Time{"TST-1": updated}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpoint, err = store.Load("project = TST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !checkpoint.Updated.Equal(updated) || !checkpoint.Issues["TST-1"].Equal(updated) {
+		t.Errorf("Unexpected checkpoint %+v", checkpoint)
+	}
+	if other, _ := store.Load("project = OTHER"); other != nil {
+		t.Errorf("Expected no checkpoint for another query, got %+v", other)
+	}
+}
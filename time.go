@@ -0,0 +1,104 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeLayouts are the layouts of the timestamps Jira emits, e.g. "2016-05-24T00:25:17.000-0700",
+// "2016-05-24T00:25:17.000Z", "2016-05-24T00:25:17+07:00" or "2016-05-24T00:25:17".
+// Missing fractional seconds are accepted by the .999 layouts.
+var timeLayouts = []string{
+	"2006-01-02T15:04:05.999Z0700",
+	"2006-01-02T15:04:05.999Z07:00",
+	"2006-01-02T15:04:05.999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// jqlTimeLayout is the layout of a timestamp in JQL
+const jqlTimeLayout = "2006-01-02 15:04"
+
+// ParseTime parses a timestamp in any of the formats used by Jira.
+// Timestamps without time zone are in UTC.
+func ParseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown time format %q", s)
+}
+
+// unmarshalTime parses a JSON timestamp: a string in any Jira format, or a number of milliseconds since the epoch.
+// ok is false for null and empty strings.
+func unmarshalTime(b []byte) (t time.Time, ok bool, err error) {
+	s := string(b)
+	if s == "null" || s == `""` {
+		return t, false, nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		t, err = ParseTime(unquoted)
+		return t, err == nil, err
+	}
+	millis, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return t, false, fmt.Errorf("unknown time format %s", s)
+	}
+	return time.Unix(0, millis*int64(time.Millisecond)), true, nil
+}
+
+// FormatJQLTime formats a timestamp for a JQL query, e.g. "2021-03-01 09:00" (with the quotes).
+// JQL interprets timestamps in the time zone of the user running the query,
+// so loc should be the time zone of that user (see UserService.GetSelfLocation). A nil loc keeps the time zone of t.
+func FormatJQLTime(t time.Time, loc *time.Location) string {
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return strconv.Quote(t.Format(jqlTimeLayout))
+}
+
+// FormatJQLDate formats the day of a timestamp for a JQL query, e.g. "2021-03-01" (with the quotes),
+// in the time zone loc (see FormatJQLTime).
+func FormatJQLDate(t time.Time, loc *time.Location) string {
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return strconv.Quote(t.Format("2006-01-02"))
+}
+
+// Location returns the time zone of the user.
+// It is the UTC location if the user has no time zone.
+func (u *User) Location() (*time.Location, error) {
+	if u.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(u.TimeZone)
+}
+
+// GetSelfLocationWithContext returns the time zone configured for the current user,
+// which is the time zone Jira uses to interpret the timestamps in JQL queries of the user.
+func (s *UserService) GetSelfLocationWithContext(ctx context.Context) (*time.Location, error) {
+	user, _, err := s.GetSelfWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return user.Location()
+}
+
+// GetSelfLocation wraps GetSelfLocationWithContext using the background context.
+func (s *UserService) GetSelfLocation() (*time.Location, error) {
+	return s.GetSelfLocationWithContext(context.Background())
+}
+
+// Time returns the time of a request date, from the epoch or else the ISO 8601 timestamp
+func (d *RequestDate) Time() (time.Time, error) {
+	if d.Epoch != 0 {
+		return time.Unix(0, d.Epoch*int64(time.Millisecond)), nil
+	}
+	return ParseTime(d.ISO8601)
+}
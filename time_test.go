@@ -0,0 +1,123 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	expected := time.Date(2016, time.May, 24, 7, 25, 17, 0, time.UTC)
+	tt := []struct {
+		input  string
+		millis int
+	}{
+		{"2016-05-24T00:25:17.000-0700", 0},
+		{"2016-05-24T07:25:17.123+0000", 123},
+		{"2016-05-24T07:25:17.5Z", 500},
+		{"2016-05-24T07:25:17Z", 0},
+		{"2016-05-24T09:25:17+02:00", 0},
+		{"2016-05-24T07:25:17", 0},
+	}
+	for _, tc := range tt {
+		got, err := ParseTime(tc.input)
+		if err != nil {
+			t.Errorf("%s: Error given: %s", tc.input, err)
+			continue
+		}
+		if want := expected.Add(time.Duration(tc.millis) * time.Millisecond); !got.Equal(want) {
+			t.Errorf("%s: Expected %s, got %s", tc.input, want, got)
+		}
+	}
+
+	if _, err := ParseTime("yesterday"); err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func TestTime_UnmarshalJSON(t *testing.T) {
+	var v struct {
+		A *Time `json:"a"`
+		B *Time `json:"b"`
+		C Time  `json:"c"`
+		D Date  `json:"d"`
+	}
+	err := json.Unmarshal([]byte(`{"a":"2016-05-24T07:25:17Z","b":null,"c":1464074717000,"d":"2016-05-24T07:25:17.000+0000"}`), &v)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	expected := time.Date(2016, time.May, 24, 7, 25, 17, 0, time.UTC)
+	if v.A == nil || !time.Time(*v.A).Equal(expected) || v.B != nil || !time.Time(v.C).Equal(expected) || !time.Time(v.D).Equal(expected) {
+		t.Errorf("Unexpected times %+v", v)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":"soon"}`), &v); err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func TestComment_TypedTimes(t *testing.T) {
+	var comment Comment
+	if err := json.Unmarshal([]byte(`{"id":"1","created":"2016-05-24T00:25:17.000-0700","updated":"2016-05-24T07:25:17.000Z"}`), &comment); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if comment.Created == nil || comment.Updated == nil || !comment.Created.Equal(*comment.Updated) {
+		t.Errorf("Unexpected comment times %v %v", comment.Created, comment.Updated)
+	}
+
+	data, err := json.Marshal(&Comment{Body: "no times"})
+	if err != nil || strings.Contains(string(data), "created") || strings.Contains(string(data), "updated") {
+		t.Errorf("Unexpected JSON %s (%v)", data, err)
+	}
+}
+
+func TestChangelogHistory_CreatedTime(t *testing.T) {
+	history := ChangelogHistory{Created: "2018-06-20T16:50:35Z"}
+	created, err := history.CreatedTime()
+	if err != nil || !created.Equal(time.Date(2018, time.June, 20, 16, 50, 35, 0, time.UTC)) {
+		t.Errorf("Unexpected created time %s (%v)", created, err)
+	}
+}
+
+func TestFormatJQLTime(t *testing.T) {
+	instant := time.Date(2021, time.March, 1, 23, 30, 0, 0, time.UTC)
+	loc := time.FixedZone("CET", 3600)
+	if got := FormatJQLTime(instant, loc); got != `"2021-03-02 00:30"` {
+		t.Errorf("Unexpected JQL time %s", got)
+	}
+	if got := FormatJQLDate(instant, nil); got != `"2021-03-01"` {
+		t.Errorf("Unexpected JQL date %s", got)
+	}
+}
+
+func TestUserService_GetSelfLocation(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/myself", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"name":"fred","timeZone":"UTC"}`)
+	})
+
+	loc, err := testClient.User.GetSelfLocation()
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if loc.String() != "UTC" {
+		t.Errorf("Unexpected location %s", loc)
+	}
+}
+
+func TestRequestDate_Time(t *testing.T) {
+	date := &RequestDate{ISO8601: "2021-03-01T09:00:00+0100"}
+	got, err := date.Time()
+	if err != nil || !got.Equal(time.Date(2021, time.March, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected time %s (%v)", got, err)
+	}
+	date.Epoch = 1614585600000
+	if got, _ := date.Time(); !got.Equal(time.Date(2021, time.March, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected time %s", got)
+	}
+}
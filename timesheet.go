@@ -0,0 +1,220 @@
+package jira
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimesheetGroup is a dimension by which the worklogs of a timesheet are totalled
+type TimesheetGroup string
+
+// The dimensions of a timesheet
+const (
+	TimesheetByUser    TimesheetGroup = "user"
+	TimesheetByDay     TimesheetGroup = "day"
+	TimesheetByProject TimesheetGroup = "project"
+	TimesheetByIssue   TimesheetGroup = "issue"
+)
+
+// TimesheetOptions specifies the optional parameters for IssueService.GetTimesheet
+type TimesheetOptions struct {
+	// From and To limit the worklogs to those started in [From, To). Zero values do not limit.
+	From time.Time
+	To   time.Time
+	// Location is the time zone used to assign a worklog to a day. Default: the time zone of the worklog.
+	Location *time.Location
+	// Users limits the worklogs to these authors (name, account id or key)
+	Users []string
+}
+
+// Timesheet holds the time logged per user, day and issue
+type Timesheet struct {
+	Entries []TimesheetEntry
+}
+
+// TimesheetEntry is the time logged by a user on one day on one issue
+type TimesheetEntry struct {
+	User    string
+	Day     string // 2006-01-02
+	Project string
+	Issue   string
+	Seconds int
+}
+
+// TimesheetTotal is the time logged for one combination of the values of the grouped dimensions
+type TimesheetTotal struct {
+	// Keys are the values of the dimensions, in the order of the groups
+	Keys    []string
+	Seconds int
+}
+
+// GetTimesheetWithContext collects the worklogs of all issues matching the JQL query into a timesheet.
+// All worklogs of every issue are loaded, so a JQL query like "worklogDate >= 2021-01-01" keeps the number of issues small.
+func (s *IssueService) GetTimesheetWithContext(ctx context.Context, jql string, options *TimesheetOptions) (*Timesheet, error) {
+	if options == nil {
+		options = &TimesheetOptions{}
+	}
+	users := make(map[string]bool, len(options.Users))
+	for _, user := range options.Users {
+		users[user] = true
+	}
+
+	timesheet := &Timesheet{}
+	entries := make(map[TimesheetEntry]int)
+	err := s.SearchPagesWithContext(ctx, jql, &SearchOptions{MaxResults: 100, Fields: []string{"project"}}, func(issue Issue) error {
+		records, err := s.GetAllWorklogsWithContext(ctx, issue.Key)
+		if err != nil {
+			return err
+		}
+
+		var project string
+		if issue.Fields != nil {
+			project = issue.Fields.Project.Key
+		}
+		for _, record := range records {
+			if record.Started == nil {
+				continue
+			}
+			started := time.Time(*record.Started)
+			if (!options.From.IsZero() && started.Before(options.From)) || (!options.To.IsZero() && !started.Before(options.To)) {
+				continue
+			}
+			user := worklogAuthor(record.Author)
+			if len(users) > 0 && !userMatches(record.Author, users) {
+				continue
+			}
+			if options.Location != nil {
+				started = started.In(options.Location)
+			}
+
+			key := TimesheetEntry{User: user, Day: started.Format("2006-01-02"), Project: project, Issue: issue.Key}
+			entries[key] += record.TimeSpentSeconds.Seconds()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for entry, seconds := range entries {
+		entry.Seconds = seconds
+		timesheet.Entries = append(timesheet.Entries, entry)
+	}
+	sort.Slice(timesheet.Entries, func(i, j int) bool {
+		a, b := timesheet.Entries[i], timesheet.Entries[j]
+		if a.User != b.User {
+			return a.User < b.User
+		}
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		return a.Issue < b.Issue
+	})
+	return timesheet, nil
+}
+
+// GetTimesheet wraps GetTimesheetWithContext using the background context.
+func (s *IssueService) GetTimesheet(jql string, options *TimesheetOptions) (*Timesheet, error) {
+	return s.GetTimesheetWithContext(context.Background(), jql, options)
+}
+
+// Total returns the total time in seconds
+func (t *Timesheet) Total() int {
+	total := 0
+	for _, entry := range t.Entries {
+		total += entry.Seconds
+	}
+	return total
+}
+
+// Totals returns the time logged per combination of the grouped dimensions, sorted by the keys.
+// Totals(TimesheetByUser, TimesheetByDay) returns the time per user and day.
+func (t *Timesheet) Totals(groups ...TimesheetGroup) []TimesheetTotal {
+	seconds := make(map[string]int)
+	keys := make(map[string][]string)
+	for _, entry := range t.Entries {
+		values := make([]string, len(groups))
+		for i, group := range groups {
+			values[i] = entry.value(group)
+		}
+		id := strings.Join(values, "\x00")
+		seconds[id] += entry.Seconds
+		keys[id] = values
+	}
+
+	ids := make([]string, 0, len(keys))
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	totals := make([]TimesheetTotal, 0, len(ids))
+	for _, id := range ids {
+		totals = append(totals, TimesheetTotal{Keys: keys[id], Seconds: seconds[id]})
+	}
+	return totals
+}
+
+// WriteCSV writes the totals of the grouped dimensions as CSV, with a header line and the time in seconds and hours.
+// Without groups, all entries are written with every dimension.
+func (t *Timesheet) WriteCSV(w io.Writer, groups ...TimesheetGroup) error {
+	if len(groups) == 0 {
+		groups = []TimesheetGroup{TimesheetByUser, TimesheetByDay, TimesheetByProject, TimesheetByIssue}
+	}
+
+	writer := csv.NewWriter(w)
+	header := make([]string, 0, len(groups)+2)
+	for _, group := range groups {
+		header = append(header, string(group))
+	}
+	if err := writer.Write(append(header, "seconds", "hours")); err != nil {
+		return err
+	}
+
+	for _, total := range t.Totals(groups...) {
+		record := append(append([]string{}, total.Keys...), strconv.Itoa(total.Seconds), fmt.Sprintf("%.2f", float64(total.Seconds)/3600))
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (e TimesheetEntry) value(group TimesheetGroup) string {
+	switch group {
+	case TimesheetByUser:
+		return e.User
+	case TimesheetByDay:
+		return e.Day
+	case TimesheetByProject:
+		return e.Project
+	case TimesheetByIssue:
+		return e.Issue
+	}
+	return ""
+}
+
+// worklogAuthor returns the identifier of the author of a worklog: the name on Jira Server, the account id on Jira Cloud
+func worklogAuthor(user *User) string {
+	if user == nil {
+		return ""
+	}
+	if user.Name != "" {
+		return user.Name
+	}
+	if user.AccountID != "" {
+		return user.AccountID
+	}
+	return user.Key
+}
+
+func userMatches(user *User, users map[string]bool) bool {
+	return user != nil && (users[user.Name] || users[user.AccountID] || users[user.Key])
+}
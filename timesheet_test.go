@@ -0,0 +1,71 @@
+package jira
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestIssueService_GetTimesheet(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/search", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":2,"issues":[{"key":"TST-1","fields":{"project":{"key":"TST"}}},{"key":"OPS-1","fields":{"project":{"key":"OPS"}}}]}`)
+	})
+	testMux.HandleFunc("/rest/api/2/issue/TST-1/worklog", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"startAt":0,"maxResults":20,"total":3,"worklogs":[
+			{"id":"1","author":{"name":"fred"},"started":"2021-03-01T09:00:00.000+0000","timeSpentSeconds":3600},
+			{"id":"2","author":{"name":"fred"},"started":"2021-03-01T13:00:00.000+0000","timeSpentSeconds":1800},
+			{"id":"3","author":{"name":"anna"},"started":"2021-02-28T09:00:00.000+0000","timeSpentSeconds":7200}]}`)
+	})
+	testMux.HandleFunc("/rest/api/2/issue/OPS-1/worklog", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"startAt":0,"maxResults":20,"total":1,"worklogs":[
+			{"id":"4","author":{"name":"anna"},"started":"2021-03-02T23:30:00.000+0000","timeSpentSeconds":900}]}`)
+	})
+
+	timesheet, err := testClient.Issue.GetTimesheet("worklogDate >= 2021-03-01", &TimesheetOptions{
+		From:     time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC),
+		Location: time.FixedZone("CET", 3600),
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+
+	expected := []TimesheetEntry{
+		{User: "anna", Day: "2021-03-03", Project: "OPS", Issue: "OPS-1", Seconds: 900},
+		{User: "fred", Day: "2021-03-01", Project: "TST", Issue: "TST-1", Seconds: 5400},
+	}
+	if !reflect.DeepEqual(timesheet.Entries, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, timesheet.Entries)
+	}
+	if timesheet.Total() != 6300 {
+		t.Errorf("Expected a total of 6300 seconds, got %d", timesheet.Total())
+	}
+}
+
+func TestTimesheet_Totals(t *testing.T) {
+	timesheet := &Timesheet{Entries: []TimesheetEntry{
+		{User: "fred", Day: "2021-03-01", Project: "TST", Issue: "TST-1", Seconds: 3600},
+		{User: "fred", Day: "2021-03-01", Project: "OPS", Issue: "OPS-1", Seconds: 1800},
+		{User: "anna", Day: "2021-03-02", Project: "TST", Issue: "TST-2", Seconds: 900},
+	}}
+
+	totals := timesheet.Totals(TimesheetByProject)
+	expected := []TimesheetTotal{{Keys: []string{"OPS"}, Seconds: 1800}, {Keys: []string{"TST"}, Seconds: 4500}}
+	if !reflect.DeepEqual(totals, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, totals)
+	}
+
+	var buf bytes.Buffer
+	if err := timesheet.WriteCSV(&buf, TimesheetByUser, TimesheetByDay); err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	csv := "user,day,seconds,hours\nanna,2021-03-02,900,0.25\nfred,2021-03-01,5400,1.50\n"
+	if buf.String() != csv {
+		t.Errorf("Expected %q, got %q", csv, buf.String())
+	}
+}
@@ -0,0 +1,207 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// maxWorklogsPerListRequest is the maximum number of worklog ids Jira accepts in one worklog/list request
+const maxWorklogsPerListRequest = 1000
+
+// DeleteWorklogQueryOptions specifies the optional parameters for the Delete Worklog method.
+// AdjustEstimate is one of "new" (with NewEstimate), "leave", "manual" (with IncreaseBy) or "auto".
+type DeleteWorklogQueryOptions struct {
+	NotifyUsers          bool   `url:"notifyUsers,omitempty"`
+	AdjustEstimate       string `url:"adjustEstimate,omitempty"`
+	NewEstimate          string `url:"newEstimate,omitempty"`
+	IncreaseBy           string `url:"increaseBy,omitempty"`
+	OverrideEditableFlag bool   `url:"overrideEditableFlag,omitempty"`
+}
+
+// WorklogChange is a worklog which was updated or deleted, as returned by the incremental worklog endpoints
+type WorklogChange struct {
+	WorklogID   int              `json:"worklogId" structs:"worklogId"`
+	UpdatedTime int64            `json:"updatedTime" structs:"updatedTime"`
+	Properties  []EntityProperty `json:"properties,omitempty" structs:"properties,omitempty"`
+}
+
+// WorklogChangeList is a page of changed worklogs.
+// Since and Until are Unix timestamps in milliseconds; Until is the since value of the next page.
+type WorklogChangeList struct {
+	Values   []WorklogChange `json:"values" structs:"values"`
+	Since    int64           `json:"since" structs:"since"`
+	Until    int64           `json:"until" structs:"until"`
+	Self     string          `json:"self" structs:"self"`
+	NextPage string          `json:"nextPage,omitempty" structs:"nextPage,omitempty"`
+	LastPage bool            `json:"lastPage" structs:"lastPage"`
+}
+
+// DeleteWorklogRecordWithContext deletes a worklog record of an issue.
+// The remaining estimate is adjusted according to the options, e.g. WithQueryOptions(&DeleteWorklogQueryOptions{AdjustEstimate: "leave"}).
+// Caller must close resp.Body
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/issue-deleteWorklog
+func (s *IssueService) DeleteWorklogRecordWithContext(ctx context.Context, issueID, worklogID string, options ...func(*http.Request) error) (*Response, error) {
+	apiEndpoint := fmt.Sprintf("rest/api/2/issue/%s/worklog/%s", issueID, worklogID)
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", apiEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, option := range options {
+		err = option(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.client.Do(req, nil)
+	if err != nil {
+		jerr := NewJiraError(resp, err)
+		return resp, jerr
+	}
+	return resp, nil
+}
+
+// DeleteWorklogRecord wraps DeleteWorklogRecordWithContext using the background context.
+// Caller must close resp.Body
+func (s *IssueService) DeleteWorklogRecord(issueID, worklogID string, options ...func(*http.Request) error) (*Response, error) {
+	return s.DeleteWorklogRecordWithContext(context.Background(), issueID, worklogID, options...)
+}
+
+// GetAllWorklogsWithContext returns all worklogs of an issue, loading every page of the worklog API.
+// Unlike GetWorklogs, the result is not limited to the first page.
+func (s *IssueService) GetAllWorklogsWithContext(ctx context.Context, issueID string) ([]WorklogRecord, error) {
+	var records []WorklogRecord
+	for {
+		worklog, resp, err := s.GetWorklogsWithContext(ctx, issueID, WithQueryOptions(&GetWorklogsQueryOptions{StartAt: int64(len(records))}))
+		if err != nil {
+			return nil, NewJiraError(resp, err)
+		}
+		records = append(records, worklog.Worklogs...)
+		if len(worklog.Worklogs) == 0 || len(records) >= worklog.Total {
+			return records, nil
+		}
+	}
+}
+
+// GetAllWorklogs wraps GetAllWorklogsWithContext using the background context.
+func (s *IssueService) GetAllWorklogs(issueID string) ([]WorklogRecord, error) {
+	return s.GetAllWorklogsWithContext(context.Background(), issueID)
+}
+
+// GetUpdatedWorklogsWithContext returns a page of the worklogs updated since the given Unix timestamp in milliseconds
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/worklog-getIdsOfWorklogsModifiedSince
+func (s *IssueService) GetUpdatedWorklogsWithContext(ctx context.Context, since int64) (*WorklogChangeList, *Response, error) {
+	return s.getWorklogChangesWithContext(ctx, "rest/api/2/worklog/updated", since)
+}
+
+// GetUpdatedWorklogs wraps GetUpdatedWorklogsWithContext using the background context.
+func (s *IssueService) GetUpdatedWorklogs(since int64) (*WorklogChangeList, *Response, error) {
+	return s.GetUpdatedWorklogsWithContext(context.Background(), since)
+}
+
+// GetDeletedWorklogsWithContext returns a page of the worklogs deleted since the given Unix timestamp in milliseconds
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/worklog-getIdsOfWorklogsDeletedSince
+func (s *IssueService) GetDeletedWorklogsWithContext(ctx context.Context, since int64) (*WorklogChangeList, *Response, error) {
+	return s.getWorklogChangesWithContext(ctx, "rest/api/2/worklog/deleted", since)
+}
+
+// GetDeletedWorklogs wraps GetDeletedWorklogsWithContext using the background context.
+func (s *IssueService) GetDeletedWorklogs(since int64) (*WorklogChangeList, *Response, error) {
+	return s.GetDeletedWorklogsWithContext(context.Background(), since)
+}
+
+// GetUpdatedWorklogsPagesWithContext calls f for every worklog updated since the given Unix timestamp in milliseconds,
+// following the pages of the API. It returns the until value of the last page, which is the since value of the next sync.
+func (s *IssueService) GetUpdatedWorklogsPagesWithContext(ctx context.Context, since int64, f func(WorklogChange) error) (int64, error) {
+	return s.getWorklogChangesPagesWithContext(ctx, "rest/api/2/worklog/updated", since, f)
+}
+
+// GetUpdatedWorklogsPages wraps GetUpdatedWorklogsPagesWithContext using the background context.
+func (s *IssueService) GetUpdatedWorklogsPages(since int64, f func(WorklogChange) error) (int64, error) {
+	return s.GetUpdatedWorklogsPagesWithContext(context.Background(), since, f)
+}
+
+// GetDeletedWorklogsPagesWithContext calls f for every worklog deleted since the given Unix timestamp in milliseconds,
+// following the pages of the API. It returns the until value of the last page, which is the since value of the next sync.
+func (s *IssueService) GetDeletedWorklogsPagesWithContext(ctx context.Context, since int64, f func(WorklogChange) error) (int64, error) {
+	return s.getWorklogChangesPagesWithContext(ctx, "rest/api/2/worklog/deleted", since, f)
+}
+
+// GetDeletedWorklogsPages wraps GetDeletedWorklogsPagesWithContext using the background context.
+func (s *IssueService) GetDeletedWorklogsPages(since int64, f func(WorklogChange) error) (int64, error) {
+	return s.GetDeletedWorklogsPagesWithContext(context.Background(), since, f)
+}
+
+// GetWorklogsByIDWithContext returns the worklogs with the given ids, e.g. the ids returned by GetUpdatedWorklogs.
+// Jira returns at most 1000 worklogs per request; longer lists are loaded in chunks.
+//
+// Jira API docs: https://docs.atlassian.com/software/jira/docs/api/REST/latest/#api/2/worklog-getWorklogsForIds
+func (s *IssueService) GetWorklogsByIDWithContext(ctx context.Context, worklogIDs []int) ([]WorklogRecord, error) {
+	apiEndpoint := "rest/api/2/worklog/list"
+
+	var records []WorklogRecord
+	for offset := 0; offset < len(worklogIDs); offset += maxWorklogsPerListRequest {
+		end := offset + maxWorklogsPerListRequest
+		if end > len(worklogIDs) {
+			end = len(worklogIDs)
+		}
+
+		req, err := s.client.NewRequestWithContext(ctx, "POST", apiEndpoint, map[string][]int{"ids": worklogIDs[offset:end]})
+		if err != nil {
+			return nil, err
+		}
+
+		var page []WorklogRecord
+		resp, err := s.client.Do(req, &page)
+		if err != nil {
+			return nil, NewJiraError(resp, err)
+		}
+		records = append(records, page...)
+	}
+	return records, nil
+}
+
+// GetWorklogsByID wraps GetWorklogsByIDWithContext using the background context.
+func (s *IssueService) GetWorklogsByID(worklogIDs []int) ([]WorklogRecord, error) {
+	return s.GetWorklogsByIDWithContext(context.Background(), worklogIDs)
+}
+
+func (s *IssueService) getWorklogChangesWithContext(ctx context.Context, apiEndpoint string, since int64) (*WorklogChangeList, *Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s?since=%d", apiEndpoint, since), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes := new(WorklogChangeList)
+	resp, err := s.client.Do(req, changes)
+	if err != nil {
+		return nil, resp, NewJiraError(resp, err)
+	}
+	return changes, resp, nil
+}
+
+func (s *IssueService) getWorklogChangesPagesWithContext(ctx context.Context, apiEndpoint string, since int64, f func(WorklogChange) error) (int64, error) {
+	for {
+		changes, _, err := s.getWorklogChangesWithContext(ctx, apiEndpoint, since)
+		if err != nil {
+			return since, err
+		}
+		for _, change := range changes.Values {
+			if err := f(change); err != nil {
+				return since, err
+			}
+		}
+		// An empty page keeps until at 0
+		if changes.Until > since {
+			since = changes.Until
+		}
+		if changes.LastPage || len(changes.Values) == 0 {
+			return since, nil
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestIssueService_DeleteWorklogRecord(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/issue/10000/worklog/1", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "DELETE")
+		testRequestURL(t, r, "/rest/api/2/issue/10000/worklog/1?adjustEstimate=manual&increaseBy=2h")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	resp, err := testClient.Issue.DeleteWorklogRecord("10000", "1", WithQueryOptions(&DeleteWorklogQueryOptions{AdjustEstimate: "manual", IncreaseBy: "2h"}))
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	defer Cleanup(resp)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected Status code 204. Given %d", resp.StatusCode)
+	}
+}
+
+func TestIssueService_GetAllWorklogs(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/issue/10000/worklog", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("startAt") {
+		case "":
+			fmt.Fprint(w, `{"startAt":0,"maxResults":2,"total":3,"worklogs":[{"id":"1"},{"id":"2"}]}`)
+		case "2":
+			fmt.Fprint(w, `{"startAt":2,"maxResults":2,"total":3,"worklogs":[{"id":"3"}]}`)
+		default:
+			t.Errorf("Unexpected startAt %s", r.URL.Query().Get("startAt"))
+		}
+	})
+
+	records, err := testClient.Issue.GetAllWorklogs("10000")
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(records) != 3 || records[2].ID != "3" {
+		t.Errorf("Expected 3 worklogs, got %+v", records)
+	}
+}
+
+func TestIssueService_GetUpdatedWorklogsPages(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/worklog/updated", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		switch r.URL.Query().Get("since") {
+		case "1000":
+			fmt.Fprint(w, `{"values":[{"worklogId":1,"updatedTime":1500},{"worklogId":2,"updatedTime":1700}],"since":1000,"until":1700,"lastPage":false}`)
+		case "1700":
+			fmt.Fprint(w, `{"values":[{"worklogId":3,"updatedTime":1800}],"since":1700,"until":1800,"lastPage":true}`)
+		default:
+			t.Errorf("Unexpected since %s", r.URL.Query().Get("since"))
+		}
+	})
+
+	var ids []int
+	until, err := testClient.Issue.GetUpdatedWorklogsPages(1000, func(change WorklogChange) error {
+		ids = append(ids, change.WorklogID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if until != 1800 || fmt.Sprint(ids) != "[1 2 3]" {
+		t.Errorf("Unexpected result %d %v", until, ids)
+	}
+}
+
+func TestIssueService_GetDeletedWorklogs(t *testing.T) {
+	setup()
+	defer teardown()
+	testMux.HandleFunc("/rest/api/2/worklog/deleted", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "GET")
+		testRequestURL(t, r, "/rest/api/2/worklog/deleted?since=1000")
+		fmt.Fprint(w, `{"values":[{"worklogId":7,"updatedTime":1500}],"since":1000,"until":1500,"lastPage":true}`)
+	})
+
+	changes, _, err := testClient.Issue.GetDeletedWorklogs(1000)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if len(changes.Values) != 1 || changes.Values[0].WorklogID != 7 || !changes.LastPage {
+		t.Errorf("Unexpected changes %+v", changes)
+	}
+}
+
+func TestIssueService_GetWorklogsByID(t *testing.T) {
+	setup()
+	defer teardown()
+	requests := 0
+	testMux.HandleFunc("/rest/api/2/worklog/list", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, "POST")
+		requests++
+		payload := map[string][]int{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+		records := make([]WorklogRecord, len(payload["ids"]))
+		for i, id := range payload["ids"] {
+			records[i].ID = fmt.Sprint(id)
+		}
+		if err := json.NewEncoder(w).Encode(records); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	ids := make([]int, 1500)
+	for i := range ids {
+		ids[i] = i + 1
+	}
+	records, err := testClient.Issue.GetWorklogsByID(ids)
+	if err != nil {
+		t.Fatalf("Error given: %s", err)
+	}
+	if requests != 2 || len(records) != 1500 || records[1499].ID != "1500" {
+		t.Errorf("Expected 1500 worklogs in 2 requests, got %d in %d", len(records), requests)
+	}
+}